@@ -6,10 +6,12 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
+	"github.com/konflux-ci/gitops-registration-service/internal/audit"
 	"github.com/konflux-ci/gitops-registration-service/internal/config"
 	"github.com/konflux-ci/gitops-registration-service/internal/server"
+	"github.com/konflux-ci/gitops-registration-service/internal/tracing"
+	"github.com/konflux-ci/gitops-registration-service/internal/version"
 	"github.com/sirupsen/logrus"
 )
 
@@ -30,6 +32,26 @@ func main() {
 		log.Fatalf("Invalid impersonation configuration: %v", impersonationErr)
 	}
 
+	// Record the effective configuration for support; secrets are masked
+	log.WithField("config", cfg.Redacted()).Info("Loaded configuration")
+
+	// Initialize tracing (no-op unless observability.tracing.enabled)
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Observability.Tracing, log)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to initialize tracing")
+	}
+
+	// Send security-relevant actions to the audit stream
+	closeAudit, err := audit.Setup(cfg.Observability.Audit)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to initialize audit logging")
+	}
+	defer func() {
+		if err := closeAudit(); err != nil {
+			log.WithError(err).Error("Failed to close audit log")
+		}
+	}()
+
 	// Initialize server
 	srv, err := server.New(cfg, log)
 	if err != nil {
@@ -42,7 +64,10 @@ func main() {
 
 	// Start server
 	go func() {
-		log.WithField("port", cfg.Server.Port).Info("Starting GitOps Registration Service")
+		log.WithFields(logrus.Fields{
+			"port":    cfg.Server.Port,
+			"version": version.Get(),
+		}).Info("Starting GitOps Registration Service")
 		if err := srv.Start(ctx); err != nil && err != http.ErrServerClosed {
 			log.WithError(err).Fatal("Server failed to start")
 		}
@@ -56,7 +81,7 @@ func main() {
 	log.Info("Shutting down server...")
 
 	// Graceful shutdown with timeout
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), srv.ShutdownTimeout())
 	defer shutdownCancel()
 
 	if err := srv.Shutdown(shutdownCtx); err != nil {
@@ -64,4 +89,8 @@ func main() {
 	} else {
 		log.Info("Server gracefully stopped")
 	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.WithError(err).Error("Failed to flush traces")
+	}
 }
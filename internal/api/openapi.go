@@ -0,0 +1,549 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/konflux-ci/gitops-registration-service/internal/version"
+)
+
+// OpenAPIVersion is the OpenAPI specification version the generated document conforms to
+const OpenAPIVersion = "3.0.3"
+
+// Document is a minimal OpenAPI 3 document
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem maps lower-case HTTP methods to operations
+type PathItem map[string]*Operation
+
+// Operation describes a single API operation on a path
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter describes a path, query or header parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes a JSON request body
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes an operation response
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType wraps the schema for a content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds reusable schemas
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is the subset of JSON schema used by the generated document
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+const jsonContentType = "application/json"
+
+// Spec builds the OpenAPI document for the service's HTTP API.
+// Paths must be kept in sync with the routes registered by the server.
+func Spec() *Document {
+	g := newSchemaGenerator()
+
+	errorResponses := func(codes ...int) map[string]Response {
+		responses := map[string]Response{}
+		for _, code := range codes {
+			responses[strconv.Itoa(code)] = g.jsonResponse(http.StatusText(code), types.ErrorResponse{})
+		}
+		return responses
+	}
+	withResponse := func(responses map[string]Response, code int, description string, body interface{}) map[string]Response {
+		if body == nil {
+			responses[strconv.Itoa(code)] = Response{Description: description}
+		} else {
+			responses[strconv.Itoa(code)] = g.jsonResponse(description, body)
+		}
+		return responses
+	}
+
+	idParam := Parameter{
+		Name:        "id",
+		In:          "path",
+		Description: "Registration ID",
+		Required:    true,
+		Schema:      &Schema{Type: "string"},
+	}
+	purgeParam := Parameter{
+		Name:        "purge",
+		In:          "query",
+		Description: "Remove an archived registration's resources; without it the registration is only archived",
+		Schema:      &Schema{Type: "boolean"},
+	}
+
+	paths := map[string]PathItem{
+		"/health/live": {
+			"get": {
+				OperationID: "healthLive",
+				Summary:     "Liveness probe",
+				Tags:        []string{"health"},
+				Responses: map[string]Response{
+					"200": g.jsonResponse("Service is alive", map[string]interface{}{}),
+				},
+			},
+		},
+		"/health/ready": {
+			"get": {
+				OperationID: "healthReady",
+				Summary:     "Readiness probe, checks Kubernetes and ArgoCD connectivity",
+				Tags:        []string{"health"},
+				Responses: map[string]Response{
+					"200": g.jsonResponse("Service is ready", map[string]interface{}{}),
+					"503": g.jsonResponse("A dependency is unavailable", map[string]interface{}{}),
+				},
+			},
+		},
+		"/version": {
+			"get": {
+				OperationID: "getVersion",
+				Summary:     "Build metadata of the running service",
+				Tags:        []string{"operations"},
+				Responses: map[string]Response{
+					"200": g.jsonResponse("Build metadata", version.Info{}),
+				},
+			},
+		},
+		"/metrics": {
+			"get": {
+				OperationID: "metrics",
+				Summary:     "Prometheus metrics",
+				Tags:        []string{"operations"},
+				Responses: map[string]Response{
+					"200": {Description: "Metrics in Prometheus text exposition format"},
+				},
+			},
+		},
+		"/openapi.json": {
+			"get": {
+				OperationID: "getOpenAPISpec",
+				Summary:     "This OpenAPI document",
+				Tags:        []string{"operations"},
+				Responses: map[string]Response{
+					"200": g.jsonResponse("OpenAPI document", map[string]interface{}{}),
+				},
+			},
+		},
+		"/api/v1/registrations": {
+			"post": {
+				OperationID: "createRegistration",
+				Summary:     "Register a repository into a new namespace",
+				Tags:        []string{"registrations"},
+				Parameters: []Parameter{{
+					Name:        "Idempotency-Key",
+					In:          "header",
+					Description: "Replays the original response for retried requests with the same body; a concurrent use returns 409 and a different body 422",
+					Schema:      &Schema{Type: "string"},
+				}},
+				RequestBody: g.jsonRequestBody(types.RegistrationRequest{}),
+				Responses: withResponse(errorResponses(400, 401, 403, 409, 422, 429, 500, 504),
+					201, "Registration created, or the caller's existing one when repeated for the same repository; the Location header and selfLink point at it",
+					types.Registration{}),
+			},
+			"get": {
+				OperationID: "listRegistrations",
+				Summary:     "List registrations",
+				Tags:        []string{"registrations"},
+				Parameters: []Parameter{
+					{
+						Name:        "namespace",
+						In:          "query",
+						Description: "Only return the registration for this namespace",
+						Schema:      &Schema{Type: "string"},
+					},
+					{
+						Name:        "owner",
+						In:          "query",
+						Description: "Only return registrations created by this user",
+						Schema:      &Schema{Type: "string"},
+					},
+					{
+						Name:        "team",
+						In:          "query",
+						Description: "Only return registrations made for this team",
+						Schema:      &Schema{Type: "string"},
+					},
+					{
+						Name:        "org",
+						In:          "query",
+						Description: "Only return registrations whose repository belongs to this organization, ignoring case",
+						Schema:      &Schema{Type: "string"},
+					},
+					{
+						Name:        "meta.<key>",
+						In:          "query",
+						Description: "Only return registrations whose <key> metadata tag has this value; repeat with other keys to match several tags",
+						Schema:      &Schema{Type: "string"},
+					},
+					{
+						Name:        "includeArchived",
+						In:          "query",
+						Description: "Also return archived registrations",
+						Schema:      &Schema{Type: "boolean"},
+					},
+				},
+				Responses: withResponse(errorResponses(400, 500, 504),
+					200, "Registrations", []types.Registration{}),
+			},
+		},
+		"/api/v1/registrations/batch": {
+			"post": {
+				OperationID: "createRegistrationBatch",
+				Summary:     "Create several registrations, reporting the outcome of each item in request order",
+				Tags:        []string{"registrations"},
+				RequestBody: g.jsonRequestBody(types.BatchRegistrationRequest{}),
+				Responses: withResponse(errorResponses(400, 401, 403, 500),
+					200, "Per-item results", types.BatchRegistrationResponse{}),
+			},
+		},
+		"/api/v1/registrations/existing": {
+			"post": {
+				OperationID: "registerExistingNamespace",
+				Summary:     "Register a repository into an existing namespace",
+				Tags:        []string{"registrations"},
+				RequestBody: g.jsonRequestBody(types.ExistingNamespaceRequest{}),
+				Responses: withResponse(errorResponses(400, 401, 403, 409, 422, 500, 504),
+					201, "Registration created; the Location header and selfLink point at it", types.Registration{}),
+			},
+		},
+		"/api/v1/registrations/by-namespace/{namespace}": {
+			"delete": {
+				OperationID: "deleteRegistrationByNamespace",
+				Summary:     "Archive the registration that owns a namespace, or purge it once archived (owner or admin)",
+				Tags:        []string{"registrations"},
+				Parameters: []Parameter{{
+					Name:        "namespace",
+					In:          "path",
+					Description: "Namespace of the registration",
+					Required:    true,
+					Schema:      &Schema{Type: "string"},
+				}, purgeParam},
+				Responses: withResponse(withResponse(errorResponses(400, 401, 403, 404, 409, 500, 504),
+					204, "Registration purged", nil),
+					200, "Registration archived", types.Registration{}),
+			},
+		},
+		"/api/v1/registrations/{id}": {
+			"get": {
+				OperationID: "getRegistration",
+				Summary:     "Get a registration",
+				Tags:        []string{"registrations"},
+				Parameters:  []Parameter{idParam},
+				Responses: withResponse(errorResponses(400, 404),
+					200, "Registration", types.Registration{}),
+			},
+			"patch": {
+				OperationID: "updateRegistration",
+				Summary:     "Update a registration's branch and path (owner or admin)",
+				Tags:        []string{"registrations"},
+				Parameters:  []Parameter{idParam},
+				RequestBody: g.jsonRequestBody(types.RegistrationUpdateRequest{}),
+				Responses: withResponse(errorResponses(400, 401, 403, 404, 409, 500, 504),
+					200, "Registration updated", types.Registration{}),
+			},
+			"delete": {
+				OperationID: "deleteRegistration",
+				Summary:     "Archive a registration, or purge it once archived (owner or admin)",
+				Tags:        []string{"registrations"},
+				Parameters:  []Parameter{idParam, purgeParam},
+				Responses: withResponse(withResponse(errorResponses(400, 401, 403, 404, 409, 500, 504),
+					204, "Registration purged", nil),
+					200, "Registration archived", types.Registration{}),
+			},
+		},
+		"/api/v1/registrations/{id}/status": {
+			"get": {
+				OperationID: "getRegistrationStatus",
+				Summary:     "Get the status of a registration",
+				Tags:        []string{"registrations"},
+				Parameters:  []Parameter{idParam},
+				Responses: withResponse(errorResponses(400, 404),
+					200, "Registration status", types.RegistrationStatus{}),
+			},
+		},
+		"/api/v1/registrations/{id}/resources": {
+			"get": {
+				OperationID: "getRegistrationResources",
+				Summary:     "List the resources deployed by the registration's Application",
+				Tags:        []string{"registrations"},
+				Parameters:  []Parameter{idParam},
+				Responses: withResponse(errorResponses(400, 404, 500, 504),
+					200, "Application resources", []types.ApplicationResource{}),
+			},
+		},
+		"/api/v1/registrations/{id}/history": {
+			"get": {
+				OperationID: "getRegistrationHistory",
+				Summary:     "List the syncs recorded in the registration's Application history, newest first",
+				Tags:        []string{"registrations"},
+				Parameters: []Parameter{idParam, {
+					Name:        "limit",
+					In:          "query",
+					Description: "Return only the most recent entries",
+					Schema:      &Schema{Type: "integer"},
+				}},
+				Responses: withResponse(errorResponses(400, 404, 500, 504),
+					200, "Application sync history", []types.ApplicationHistoryEntry{}),
+			},
+		},
+		"/api/v1/registrations/{id}/events": {
+			"get": {
+				OperationID: "getRegistrationEvents",
+				Summary:     "List the Kubernetes Events this service recorded in the registration's namespace",
+				Tags:        []string{"registrations"},
+				Parameters:  []Parameter{idParam},
+				Responses: withResponse(errorResponses(400, 404, 500, 504),
+					200, "Registration events", []types.RegistrationEvent{}),
+			},
+		},
+		"/api/v1/registrations/{id}/sync": {
+			"post": {
+				OperationID: "syncRegistration",
+				Summary:     "Trigger a sync of the registration's Application",
+				Tags:        []string{"registrations"},
+				Parameters:  []Parameter{idParam},
+				Responses: withResponse(errorResponses(400),
+					200, "Sync triggered", map[string]interface{}{}),
+			},
+		},
+		"/api/v1/registrations/{id}/refresh": {
+			"post": {
+				OperationID: "refreshRegistration",
+				Summary:     "Ask ArgoCD to re-read the registration's repository without syncing (owner or admin)",
+				Tags:        []string{"registrations"},
+				Parameters: []Parameter{idParam, {
+					Name:        "hard",
+					In:          "query",
+					Description: "Bypass ArgoCD's manifest cache; defaults to true",
+					Schema:      &Schema{Type: "boolean"},
+				}},
+				Responses: withResponse(errorResponses(400, 401, 403, 404, 500, 504),
+					202, "Refresh requested", map[string]interface{}{}),
+			},
+		},
+		"/api/v1/registrations/{id}/reauthorize": {
+			"post": {
+				OperationID: "reauthorizeRegistration",
+				Summary:     "Re-check whether the registration's owner still has access to its namespace (admin only)",
+				Tags:        []string{"registrations"},
+				Parameters:  []Parameter{idParam},
+				Responses: withResponse(errorResponses(400, 401, 403, 404, 422, 504),
+					200, "Current access decision for the owner", types.ReauthorizationResult{}),
+			},
+		},
+		"/api/v1/registrations/{id}/unarchive": {
+			"post": {
+				OperationID: "unarchiveRegistration",
+				Summary:     "Resume an archived registration's Application and clear its archived mark (owner or admin)",
+				Tags:        []string{"registrations"},
+				Parameters:  []Parameter{idParam},
+				Responses: withResponse(errorResponses(400, 401, 403, 404, 500, 504),
+					200, "Registration restored", types.Registration{}),
+			},
+		},
+		"/api/v1/tenants": {
+			"get": {
+				OperationID: "listTenants",
+				Summary:     "List managed namespaces with their Application health",
+				Tags:        []string{"tenants"},
+				Parameters: []Parameter{{
+					Name:        "health",
+					In:          "query",
+					Description: "Only return tenants with this health status",
+					Schema:      &Schema{Type: "string"},
+				}},
+				Responses: withResponse(errorResponses(401, 403, 500, 504),
+					200, "Tenants", []types.TenantStatus{}),
+			},
+		},
+		"/api/v1/config": {
+			"get": {
+				OperationID: "getConfig",
+				Summary:     "Get the effective service configuration with secrets redacted",
+				Tags:        []string{"operations"},
+				Responses: withResponse(errorResponses(401, 403),
+					200, "Effective configuration", map[string]interface{}{}),
+			},
+		},
+		"/api/v1/appprojects": {
+			"delete": {
+				OperationID: "deleteAppProjectByRepoHash",
+				Summary:     "Force-delete the AppProject and Application registered for a repository hash",
+				Tags:        []string{"operations"},
+				Parameters: []Parameter{{
+					Name:        "repoHash",
+					In:          "query",
+					Description: "Repository hash label of the AppProject to delete",
+					Required:    true,
+					Schema:      &Schema{Type: "string"},
+				}},
+				Responses: withResponse(errorResponses(400, 401, 403, 404, 500, 504),
+					200, "Deleted AppProject", types.AppProject{}),
+			},
+		},
+		"/api/v1/appprojects/backfill-repo-hash-labels": {
+			"post": {
+				OperationID: "backfillRepoHashLabels",
+				Summary:     "Add missing repository hash labels to AppProjects managed by the service",
+				Tags:        []string{"operations"},
+				Responses: withResponse(errorResponses(401, 403, 500, 504),
+					200, "Names of the labeled AppProjects", map[string]interface{}{}),
+			},
+		},
+		"/api/v1/diagnostics/hash-collisions": {
+			"get": {
+				OperationID: "listRepositoryHashCollisions",
+				Summary:     "List repository hash label values shared by AppProjects of different repositories",
+				Tags:        []string{"operations"},
+				Responses: withResponse(errorResponses(401, 403, 500, 504),
+					200, "Colliding hashes with their repositories and AppProjects", map[string]interface{}{}),
+			},
+		},
+	}
+
+	return &Document{
+		OpenAPI: OpenAPIVersion,
+		Info: Info{
+			Title:       "GitOps Registration Service",
+			Description: "Registers GitOps repositories and provisions namespaces with ArgoCD resources",
+			Version:     "v1",
+		},
+		Paths:      paths,
+		Components: Components{Schemas: g.schemas},
+	}
+}
+
+// SpecJSON returns the OpenAPI document encoded as JSON
+func SpecJSON() ([]byte, error) {
+	return json.Marshal(Spec())
+}
+
+// schemaGenerator derives JSON schemas from Go types and collects named structs as components
+type schemaGenerator struct {
+	schemas map[string]*Schema
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{schemas: map[string]*Schema{}}
+}
+
+func (g *schemaGenerator) jsonResponse(description string, body interface{}) Response {
+	return Response{
+		Description: description,
+		Content:     map[string]MediaType{jsonContentType: {Schema: g.schemaFor(reflect.TypeOf(body))}},
+	}
+}
+
+func (g *schemaGenerator) jsonRequestBody(body interface{}) *RequestBody {
+	return &RequestBody{
+		Required: true,
+		Content:  map[string]MediaType{jsonContentType: {Schema: g.schemaFor(reflect.TypeOf(body))}},
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (g *schemaGenerator) schemaFor(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.String:
+		return &Schema{Type: "string"}
+	case t.Kind() == reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return &Schema{Type: "number"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case t.Kind() == reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case t.Kind() == reflect.Struct:
+		return g.structSchema(t)
+	default:
+		return &Schema{}
+	}
+}
+
+// structSchema registers a struct as a component schema and returns a reference to it
+func (g *schemaGenerator) structSchema(t reflect.Type) *Schema {
+	ref := &Schema{Ref: "#/components/schemas/" + t.Name()}
+	if _, ok := g.schemas[t.Name()]; ok {
+		return ref
+	}
+
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	// Register before walking fields so recursive types terminate
+	g.schemas[t.Name()] = schema
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = g.schemaFor(field.Type)
+	}
+
+	return ref
+}
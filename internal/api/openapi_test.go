@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpec_ComponentSchemas(t *testing.T) {
+	spec := Spec()
+
+	assert.Equal(t, OpenAPIVersion, spec.OpenAPI)
+
+	for _, name := range []string{"RegistrationRequest", "Registration", "RegistrationStatus", "ErrorResponse", "TenantStatus"} {
+		assert.Contains(t, spec.Components.Schemas, name)
+	}
+
+	registration := spec.Components.Schemas["Registration"]
+	require.NotNil(t, registration)
+	assert.Equal(t, "#/components/schemas/Repository", registration.Properties["repository"].Ref)
+	assert.Equal(t, "date-time", registration.Properties["createdAt"].Format)
+	assert.Equal(t, "object", registration.Properties["labels"].Type)
+	assert.Equal(t, "string", registration.Properties["labels"].AdditionalProperties.Type)
+}
+
+func TestSpec_OperationsReferenceSchemas(t *testing.T) {
+	spec := Spec()
+
+	create := spec.Paths["/api/v1/registrations"]["post"]
+	require.NotNil(t, create)
+	assert.Equal(t, "#/components/schemas/RegistrationRequest",
+		create.RequestBody.Content[jsonContentType].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/Registration",
+		create.Responses["201"].Content[jsonContentType].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/ErrorResponse",
+		create.Responses["409"].Content[jsonContentType].Schema.Ref)
+
+	list := spec.Paths["/api/v1/registrations"]["get"]
+	require.NotNil(t, list)
+	assert.Equal(t, "array", list.Responses["200"].Content[jsonContentType].Schema.Type)
+
+	remove := spec.Paths["/api/v1/registrations/{id}"]["delete"]
+	require.NotNil(t, remove)
+	assert.Empty(t, remove.Responses["204"].Content)
+}
+
+func TestSpecJSON(t *testing.T) {
+	data, err := SpecJSON()
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, OpenAPIVersion, decoded["openapi"])
+	assert.Contains(t, decoded, "paths")
+	assert.Contains(t, decoded, "components")
+}
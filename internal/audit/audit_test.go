@@ -0,0 +1,77 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudit_Fields(t *testing.T) {
+	hook := test.NewLocal(Logger())
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-1")
+
+	Audit(ctx, ActionRegistrationCreate, "alice", "team-a", OutcomeSuccess)
+	Audit(context.Background(), ActionNamespaceAccess, "mallory", "kube-system", OutcomeDenied)
+
+	require.Len(t, hook.AllEntries(), 2)
+
+	created := hook.AllEntries()[0]
+	assert.Equal(t, logrus.InfoLevel, created.Level)
+	assert.Equal(t, true, created.Data["audit"])
+	assert.Equal(t, ActionRegistrationCreate, created.Data["action"])
+	assert.Equal(t, "alice", created.Data["user"])
+	assert.Equal(t, "team-a", created.Data["target"])
+	assert.Equal(t, OutcomeSuccess, created.Data["outcome"])
+	assert.Equal(t, "req-1", created.Data["requestId"])
+
+	denied := hook.LastEntry()
+	assert.Equal(t, logrus.WarnLevel, denied.Level)
+	assert.Equal(t, OutcomeDenied, denied.Data["outcome"])
+	assert.NotContains(t, denied.Data, "requestId")
+}
+
+func TestSetup(t *testing.T) {
+	t.Cleanup(func() {
+		_, err := Setup(config.AuditConfig{})
+		require.NoError(t, err)
+	})
+
+	t.Run("File sink at warn level", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		closeAudit, err := Setup(config.AuditConfig{Output: path, Level: "warn"})
+		require.NoError(t, err)
+
+		Audit(context.Background(), ActionConfigRead, "alice", "", OutcomeSuccess)
+		Audit(context.Background(), ActionConfigRead, "bob", "", OutcomeDenied)
+		require.NoError(t, closeAudit())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &record), "only the denial should be written")
+		assert.Equal(t, "bob", record["user"])
+		assert.Equal(t, ActionConfigRead, record["action"])
+	})
+
+	t.Run("Invalid level", func(t *testing.T) {
+		_, err := Setup(config.AuditConfig{Level: "loud"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid audit log level")
+	})
+
+	t.Run("Unwritable file", func(t *testing.T) {
+		_, err := Setup(config.AuditConfig{Output: filepath.Join(t.TempDir(), "missing", "audit.log")})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to open audit log")
+	})
+}
@@ -1,64 +1,247 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 )
 
 // Config holds the complete application configuration
 type Config struct {
-	Server        ServerConfig        `yaml:"server"`
-	ArgoCD        ArgoCDConfig        `yaml:"argocd"`
-	Kubernetes    KubernetesConfig    `yaml:"kubernetes"`
-	Security      SecurityConfig      `yaml:"security"`
-	Registration  RegistrationConfig  `yaml:"registration"`
-	Authorization AuthorizationConfig `yaml:"authorization"`
-	Tenants       TenantsConfig       `yaml:"tenants"`
-	Capacity      CapacityConfig      `yaml:"capacity"`
+	Server        ServerConfig        `yaml:"server" json:"server"`
+	ArgoCD        ArgoCDConfig        `yaml:"argocd" json:"argocd"`
+	Kubernetes    KubernetesConfig    `yaml:"kubernetes" json:"kubernetes"`
+	Security      SecurityConfig      `yaml:"security" json:"security"`
+	Registration  RegistrationConfig  `yaml:"registration" json:"registration"`
+	Authorization AuthorizationConfig `yaml:"authorization" json:"authorization"`
+	Tenants       TenantsConfig       `yaml:"tenants" json:"tenants"`
+	Capacity      CapacityConfig      `yaml:"capacity" json:"capacity"`
+	Observability ObservabilityConfig `yaml:"observability" json:"observability"`
+	Reconcile     ReconcileConfig     `yaml:"reconcile" json:"reconcile"`
+	Notifications NotificationsConfig `yaml:"notifications" json:"notifications"`
+	Batch         BatchConfig         `yaml:"batch" json:"batch"`
+	Upstream      UpstreamConfig      `yaml:"upstream" json:"upstream"`
 }
 
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
-	Port    int    `yaml:"port"`
-	Timeout string `yaml:"timeout"`
+	Port            int             `yaml:"port" json:"port"`
+	Timeout         string          `yaml:"timeout" json:"timeout"`
+	ShutdownTimeout string          `yaml:"shutdownTimeout" json:"shutdownTimeout"` // Grace period for in-flight requests on shutdown
+	DrainDelay      string          `yaml:"drainDelay" json:"drainDelay"`           // Readiness reports draining this long before the listener closes
+	StartupTimeout  string          `yaml:"startupTimeout" json:"startupTimeout"`   // How long the initial dependency check may retry before startup fails
+	AccessLog       AccessLogConfig `yaml:"accessLog" json:"accessLog"`
+	TLS             TLSConfig       `yaml:"tls" json:"tls"`
+	CORS            CORSConfig      `yaml:"cors" json:"cors"`
+	// Reject every write request with 503 so changes are frozen, e.g. during cluster maintenance
+	MaintenanceMode bool `yaml:"maintenanceMode" json:"maintenanceMode"`
+	// How long /health/ready reuses a dependency check result; failing checks are reused for a jittered,
+	// growing backoff instead. 0 checks the dependencies on every probe.
+	ReadinessCacheTTL string `yaml:"readinessCacheTTL" json:"readinessCacheTTL"`
+	// Serve /health/* and /metrics over plain HTTP on this port instead of the API port; 0 keeps them on the API port
+	HealthPort int `yaml:"healthPort,omitempty" json:"healthPort,omitempty"`
+}
+
+// CORSConfig holds the cross-origin settings applied to every response
+type CORSConfig struct {
+	AllowedOrigins   []string `yaml:"allowedOrigins" json:"allowedOrigins"` // "*" allows any origin, wildcards like "https://*.example.com" are supported
+	AllowedMethods   []string `yaml:"allowedMethods" json:"allowedMethods"`
+	AllowCredentials bool     `yaml:"allowCredentials" json:"allowCredentials"`
+}
+
+// TLSConfig holds HTTPS serving and client certificate authentication settings
+type TLSConfig struct {
+	CertFile     string `yaml:"certFile" json:"certFile"` // Empty serves plain HTTP
+	KeyFile      string `yaml:"keyFile" json:"keyFile"`
+	ClientCAFile string `yaml:"clientCAFile" json:"clientCAFile"`
+	// Accept client certificates signed by ClientCAFile as an alternative to Bearer tokens
+	ClientAuth bool `yaml:"clientAuth" json:"clientAuth"`
+}
+
+// AccessLogConfig holds per-request access logging configuration
+type AccessLogConfig struct {
+	Level        string   `yaml:"level" json:"level"`
+	ExcludePaths []string `yaml:"excludePaths" json:"excludePaths"` // Path prefixes that are not logged
 }
 
 // ArgoCDConfig holds ArgoCD connection configuration
 type ArgoCDConfig struct {
-	Server    string `yaml:"server"`
-	Namespace string `yaml:"namespace"`
-	GRPC      bool   `yaml:"grpc"`
+	Server                     string          `yaml:"server" json:"server"`
+	Namespace                  string          `yaml:"namespace" json:"namespace"`
+	GRPC                       bool            `yaml:"grpc" json:"grpc"`
+	AllowedDestinationClusters []string        `yaml:"allowedDestinationClusters,omitempty" json:"allowedDestinationClusters,omitempty"`
+	SyncRetry                  SyncRetryConfig `yaml:"syncRetry" json:"syncRetry"`
+	RequestTimeout             string          `yaml:"requestTimeout" json:"requestTimeout"`
+	// Role granted on every tenant AppProject; the built-in tenant-role is used when unset
+	ProjectRole ProjectRoleConfig `yaml:"projectRole" json:"projectRole"`
+	// Bearer token for the ArgoCD API server, used to query repository connection state
+	Token string `yaml:"token,omitempty" json:"token,omitempty"`
+	// Sync windows added to every tenant AppProject unless the registration request sets its own
+	DefaultSyncWindows []SyncWindowConfig `yaml:"defaultSyncWindows,omitempty" json:"defaultSyncWindows,omitempty"`
+	// Notification subscriptions added to every tenant Application unless the registration request sets its own
+	DefaultNotifications []NotificationSubscriptionConfig `yaml:"defaultNotifications,omitempty" json:"defaultNotifications,omitempty"`
+}
+
+// ProjectRoleConfig describes the role generated in tenant AppProjects
+type ProjectRoleConfig struct {
+	Name string `yaml:"name" json:"name"`
+	// Rules of the form "<resource>, <action>, <effect>", scoped to the tenant's project when rendered
+	Policies []string `yaml:"policies" json:"policies"`
+}
+
+// DefaultProjectRole returns the role that lets tenants view, update and sync their own Applications
+func DefaultProjectRole() ProjectRoleConfig {
+	return ProjectRoleConfig{
+		Name: "tenant-role",
+		Policies: []string{
+			"applications, sync, allow",
+			"applications, get, allow",
+			"applications, update, allow",
+		},
+	}
+}
+
+var (
+	projectRoleNamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([-_a-zA-Z0-9]*[a-zA-Z0-9])?$`)
+
+	projectPolicyResources = map[string]bool{
+		"applications": true, "applicationsets": true, "logs": true, "exec": true, "repositories": true, "clusters": true,
+	}
+	projectPolicyActions = map[string]bool{
+		"*": true, "get": true, "create": true, "update": true, "delete": true, "sync": true, "override": true,
+	}
+)
+
+// RenderPolicies expands the role's rules into ArgoCD policy lines for project. Every line is scoped to
+// the project's own Applications; a project name that is empty or would match other projects renders nothing.
+func (r ProjectRoleConfig) RenderPolicies(project string) []string {
+	if project == "" || strings.ContainsAny(project, "*/, ") {
+		return nil
+	}
+
+	policies := make([]string, 0, len(r.Policies))
+	for _, rule := range r.Policies {
+		resource, action, effect, err := parseProjectPolicy(rule)
+		if err != nil {
+			continue
+		}
+		policies = append(policies, fmt.Sprintf("p, proj:%s:%s, %s, %s, %s/*, %s",
+			project, r.Name, resource, action, project, effect))
+	}
+	return policies
+}
+
+// validate checks the role name and that every rule parses
+func (r ProjectRoleConfig) validate() []error {
+	var errs []error
+	if !projectRoleNamePattern.MatchString(r.Name) {
+		errs = append(errs, fmt.Errorf("argocd.projectRole.name %q must be alphanumeric with dashes or underscores", r.Name))
+	}
+	if len(r.Policies) == 0 {
+		errs = append(errs, fmt.Errorf("argocd.projectRole.policies must contain at least one rule"))
+	}
+	for _, rule := range r.Policies {
+		if _, _, _, err := parseProjectPolicy(rule); err != nil {
+			errs = append(errs, fmt.Errorf("argocd.projectRole.policies: %w", err))
+		}
+	}
+	return errs
+}
+
+// parseProjectPolicy splits a "<resource>, <action>, <effect>" rule and checks each part
+func parseProjectPolicy(rule string) (resource, action, effect string, err error) {
+	parts := strings.Split(rule, ",")
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf("rule %q must have the form \"<resource>, <action>, <effect>\"", rule)
+	}
+	resource, action, effect = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), strings.TrimSpace(parts[2])
+
+	if !projectPolicyResources[resource] {
+		return "", "", "", fmt.Errorf("rule %q has unsupported resource %q", rule, resource)
+	}
+	if !projectPolicyActions[action] && !strings.HasPrefix(action, "action/") {
+		return "", "", "", fmt.Errorf("rule %q has unsupported action %q", rule, action)
+	}
+	if effect != "allow" && effect != "deny" {
+		return "", "", "", fmt.Errorf("rule %q has effect %q, expected allow or deny", rule, effect)
+	}
+	return resource, action, effect, nil
+}
+
+// SyncRetryConfig holds the retry policy applied to generated ArgoCD Applications
+type SyncRetryConfig struct {
+	Limit              int64  `yaml:"limit" json:"limit"`
+	BackoffDuration    string `yaml:"backoffDuration" json:"backoffDuration"`
+	BackoffFactor      int64  `yaml:"backoffFactor" json:"backoffFactor"`
+	BackoffMaxDuration string `yaml:"backoffMaxDuration" json:"backoffMaxDuration"`
 }
 
 // KubernetesConfig holds Kubernetes client configuration
 type KubernetesConfig struct {
-	Namespace string `yaml:"namespace"`
+	Namespace      string `yaml:"namespace" json:"namespace"`
+	RequestTimeout string `yaml:"requestTimeout" json:"requestTimeout"`
 }
 
 // SecurityConfig holds security-related configuration
 type SecurityConfig struct {
-	AllowedResourceTypes       []string                     `yaml:"allowedResourceTypes"`
-	ResourceAllowList          []ServiceResourceRestriction `yaml:"resourceAllowList,omitempty"`
-	ResourceDenyList           []ServiceResourceRestriction `yaml:"resourceDenyList,omitempty"`
-	RequireAppProjectPerTenant bool                         `yaml:"requireAppProjectPerTenant"`
+	AllowedResourceTypes       []string                     `yaml:"allowedResourceTypes" json:"allowedResourceTypes"`
+	ResourceAllowList          []ServiceResourceRestriction `yaml:"resourceAllowList,omitempty" json:"resourceAllowList,omitempty"`
+	ResourceDenyList           []ServiceResourceRestriction `yaml:"resourceDenyList,omitempty" json:"resourceDenyList,omitempty"`
+	RequireAppProjectPerTenant bool                         `yaml:"requireAppProjectPerTenant" json:"requireAppProjectPerTenant"`
 	// Deprecated: Use Impersonation.Enabled instead
-	EnableServiceAccountImpersonation bool `yaml:"enableServiceAccountImpersonation"`
+	EnableServiceAccountImpersonation bool `yaml:"enableServiceAccountImpersonation" json:"enableServiceAccountImpersonation"`
 	// New impersonation configuration
-	Impersonation ImpersonationConfig `yaml:"impersonation"`
+	Impersonation ImpersonationConfig `yaml:"impersonation" json:"impersonation"`
+	// RBAC names used when impersonation is disabled
+	LegacyServiceAccountName string `yaml:"legacyServiceAccountName" json:"legacyServiceAccountName"`
+	LegacyRoleBindingName    string `yaml:"legacyRoleBindingName" json:"legacyRoleBindingName"`
+	LegacyRole               string `yaml:"legacyRole" json:"legacyRole"`
+	// Refuse to delete or update namespaces lacking the gitops.io/managed-by label
+	RequireManagedLabelForMutation bool `yaml:"requireManagedLabelForMutation" json:"requireManagedLabelForMutation"`
+	// Glob patterns of unmanaged namespaces that existing-namespace registration may claim while the guard is on
+	ClaimableNamespaces []string `yaml:"claimableNamespaces" json:"claimableNamespaces"`
+	// Hosts registered repositories may be served from, empty allows any host
+	AllowedRepositoryHosts []string `yaml:"allowedRepositoryHosts" json:"allowedRepositoryHosts"`
+	// What makes two registrations conflict: the repository URL (repo) or the URL and path (repo+path)
+	ConflictScope string `yaml:"conflictScope" json:"conflictScope"`
+	// Label key holding the repository hash on namespaces and AppProjects; empty uses gitops.io/repository-hash
+	RepositoryHashLabelKey string `yaml:"repositoryHashLabelKey" json:"repositoryHashLabelKey"`
+	// Hex characters of the SHA-256 kept in repository hash labels, 8 to 63; 0 uses 8. Longer hashes make
+	// collisions between repositories less likely
+	RepositoryHashLength int `yaml:"repositoryHashLength" json:"repositoryHashLength"`
+	// Refuse to start when resourceAllowList or resourceDenyList names a kind the cluster does not serve,
+	// instead of only logging a warning
+	StrictResourceValidation bool `yaml:"strictResourceValidation" json:"strictResourceValidation"`
+	// How long a ClusterRole validation result is reused before the role is read again; empty or 0 disables caching
+	ClusterRoleCacheTTL string `yaml:"clusterRoleCacheTTL" json:"clusterRoleCacheTTL"`
 }
 
+// Repository conflict scopes
+const (
+	ConflictScopeRepo     = "repo"
+	ConflictScopeRepoPath = "repo+path"
+)
+
 // ImpersonationConfig holds ArgoCD impersonation configuration
 type ImpersonationConfig struct {
-	Enabled                bool   `yaml:"enabled"`
-	ClusterRole            string `yaml:"clusterRole"`
-	ServiceAccountBaseName string `yaml:"serviceAccountBaseName"`
-	ValidatePermissions    bool   `yaml:"validatePermissions"`
-	AutoCleanup            bool   `yaml:"autoCleanup"`
+	Enabled                bool   `yaml:"enabled" json:"enabled"`
+	ClusterRole            string `yaml:"clusterRole" json:"clusterRole"`
+	ServiceAccountBaseName string `yaml:"serviceAccountBaseName" json:"serviceAccountBaseName"`
+	ValidatePermissions    bool   `yaml:"validatePermissions" json:"validatePermissions"`
+	AutoCleanup            bool   `yaml:"autoCleanup" json:"autoCleanup"`
 }
 
 // ServiceResourceRestriction represents a resource type restriction for service-level configuration
@@ -69,33 +252,155 @@ type ServiceResourceRestriction struct {
 
 // RegistrationConfig holds registration control settings
 type RegistrationConfig struct {
-	AllowNewNamespaces bool `yaml:"allowNewNamespaces"`
+	AllowNewNamespaces          bool   `yaml:"allowNewNamespaces" json:"allowNewNamespaces"`
+	DeleteNamespaceOnDeregister bool   `yaml:"deleteNamespaceOnDeregister" json:"deleteNamespaceOnDeregister"`
+	IdempotencyKeyTTL           string `yaml:"idempotencyKeyTTL" json:"idempotencyKeyTTL"`
+	DefaultBranch               string `yaml:"defaultBranch" json:"defaultBranch"`
+	UseApplicationSet           bool   `yaml:"useApplicationSet" json:"useApplicationSet"`
+	// Confirm ArgoCD can reach a repository before registering it with a credentials secret
+	ValidateRepoAccess bool `yaml:"validateRepoAccess" json:"validateRepoAccess"`
+	// Maximum number of new-namespace registrations a non-admin user may own; 0 means unlimited
+	MaxPerUser int `yaml:"maxPerUser" json:"maxPerUser"`
+	// Take over an AppProject that already exists under an existing namespace's name instead of refusing it
+	AdoptExistingAppProject bool `yaml:"adoptExistingAppProject" json:"adoptExistingAppProject"`
+	// How long a registration waits for a terminating namespace of the same name to be removed; empty does not wait
+	WaitForTerminatingNamespace string `yaml:"waitForTerminatingNamespace" json:"waitForTerminatingNamespace"`
+	// How long a registration waits for its new namespace to become Active before creating ArgoCD resources,
+	// for clusters whose admission or quota controllers finish namespaces asynchronously; empty does not wait
+	WaitForNamespaceActive string `yaml:"waitForNamespaceActive" json:"waitForNamespaceActive"`
+	// Add a cluster-scoped destination to AppProjects so tenants can deploy the cluster resources in
+	// security.resourceAllowList; requires an allow list
+	AllowClusterScopedDestination bool `yaml:"allowClusterScopedDestination" json:"allowClusterScopedDestination"`
+	// Glob patterns (path.Match syntax, case-sensitive) for namespaces that may never be registered
+	NamespaceDenyPatterns []string `yaml:"namespaceDenyPatterns" json:"namespaceDenyPatterns"`
+	// Create an Application for each registration; when false only the AppProject and RBAC are managed
+	// and tenants create their own Applications. Unset means true.
+	ManageApplication *bool `yaml:"manageApplication,omitempty" json:"manageApplication,omitempty"`
+}
+
+// ManagesApplication reports whether registrations create an Application, defaulting to true when
+// manageApplication is unset
+func (r RegistrationConfig) ManagesApplication() bool {
+	return r.ManageApplication == nil || *r.ManageApplication
 }
 
 // AuthorizationConfig holds authorization configuration
 type AuthorizationConfig struct {
-	RequiredRole              string `yaml:"requiredRole"`
-	EnableSubjectAccessReview bool   `yaml:"enableSubjectAccessReview"`
-	AuditFailedAttempts       bool   `yaml:"auditFailedAttempts"`
+	RequiredRole              string   `yaml:"requiredRole" json:"requiredRole"`
+	RequiredRoles             []string `yaml:"requiredRoles,omitempty" json:"requiredRoles,omitempty"`
+	EnableSubjectAccessReview bool     `yaml:"enableSubjectAccessReview" json:"enableSubjectAccessReview"`
+	AuditFailedAttempts       bool     `yaml:"auditFailedAttempts" json:"auditFailedAttempts"`
+	AdminGroups               []string `yaml:"adminGroups,omitempty" json:"adminGroups,omitempty"`
+	AdminUsers                []string `yaml:"adminUsers,omitempty" json:"adminUsers,omitempty"`
+}
+
+// Roles returns requiredRole followed by requiredRoles, without blanks or duplicates.
+// Holding any one of them is enough to be granted namespace access.
+func (a AuthorizationConfig) Roles() []string {
+	var roles []string
+	seen := make(map[string]bool)
+	for _, role := range append([]string{a.RequiredRole}, a.RequiredRoles...) {
+		if role == "" || seen[role] {
+			continue
+		}
+		seen[role] = true
+		roles = append(roles, role)
+	}
+	return roles
 }
 
 // TenantsConfig holds tenant-related configuration
 type TenantsConfig struct {
-	NamespacePrefix      string            `yaml:"namespacePrefix"`
-	DefaultResourceQuota map[string]string `yaml:"defaultResourceQuota"`
+	NamespacePrefix             string            `yaml:"namespacePrefix" json:"namespacePrefix"`
+	DefaultResourceQuota        map[string]string `yaml:"defaultResourceQuota" json:"defaultResourceQuota"`
+	DefaultNamespaceLabels      map[string]string `yaml:"defaultNamespaceLabels,omitempty" json:"defaultNamespaceLabels,omitempty"`
+	DefaultNamespaceAnnotations map[string]string `yaml:"defaultNamespaceAnnotations,omitempty" json:"defaultNamespaceAnnotations,omitempty"`
+	// Also apply the defaults when converting an existing namespace
+	ApplyDefaultsToExistingNamespaces bool `yaml:"applyDefaultsToExistingNamespaces" json:"applyDefaultsToExistingNamespaces"`
+	// Default container requests and limits, created as a LimitRange in new tenant namespaces; unset creates none
+	DefaultLimitRange *LimitRangeConfig `yaml:"defaultLimitRange,omitempty" json:"defaultLimitRange,omitempty"`
+	// Key prefixes permitted for pass-through namespace annotations; unset allows all, an empty list denies all
+	AllowedAnnotationPrefixes []string `yaml:"allowedAnnotationPrefixes,omitempty" json:"allowedAnnotationPrefixes,omitempty"`
+}
+
+// AnnotationAllowed reports whether a pass-through namespace annotation key matches allowedAnnotationPrefixes
+func (t TenantsConfig) AnnotationAllowed(key string) bool {
+	if t.AllowedAnnotationPrefixes == nil {
+		return true
+	}
+	for _, prefix := range t.AllowedAnnotationPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
 }
 
+// LimitRangeConfig holds the container defaults of a tenant LimitRange, as resource name to quantity
+type LimitRangeConfig struct {
+	Default        map[string]string `yaml:"default" json:"default"`               // Limits for containers that set none
+	DefaultRequest map[string]string `yaml:"defaultRequest" json:"defaultRequest"` // Requests for containers that set none
+}
+
+// ReservedMetadataPrefix marks labels and annotations owned by the service
+const ReservedMetadataPrefix = "gitops.io/"
+
 // CapacityConfig holds capacity management configuration
 type CapacityConfig struct {
-	Enabled bool           `yaml:"enabled"`
-	Limits  CapacityLimits `yaml:"limits"`
+	Enabled bool           `yaml:"enabled" json:"enabled"`
+	Limits  CapacityLimits `yaml:"limits" json:"limits"`
 }
 
 // CapacityLimits represents capacity limits configuration
 type CapacityLimits struct {
-	MaxNamespaces      int     `yaml:"maxNamespaces"`
-	MaxTenantsPerUser  int     `yaml:"maxTenantsPerUser"`
-	EmergencyThreshold float64 `yaml:"emergencyThreshold"`
+	MaxNamespaces      int     `yaml:"maxNamespaces" json:"maxNamespaces"`
+	MaxTenantsPerUser  int     `yaml:"maxTenantsPerUser" json:"maxTenantsPerUser"`
+	EmergencyThreshold float64 `yaml:"emergencyThreshold" json:"emergencyThreshold"`
+}
+
+// ObservabilityConfig holds tracing and other telemetry configuration
+type ObservabilityConfig struct {
+	Tracing TracingConfig `yaml:"tracing" json:"tracing"`
+	Audit   AuditConfig   `yaml:"audit" json:"audit"`
+}
+
+// AuditConfig holds the sink for the audit stream of security-relevant actions
+type AuditConfig struct {
+	Output string `yaml:"output" json:"output"` // stdout, stderr or a file path
+	Level  string `yaml:"level" json:"level"`   // warn keeps only failures and denials
+}
+
+// TracingConfig holds OpenTelemetry tracing configuration
+type TracingConfig struct {
+	Enabled      bool   `yaml:"enabled" json:"enabled"`
+	OTLPEndpoint string `yaml:"otlpEndpoint" json:"otlpEndpoint"`
+	Insecure     bool   `yaml:"insecure" json:"insecure"` // Export over plain HTTP instead of TLS
+}
+
+// ReconcileConfig holds settings for the periodic drift reconciler
+type ReconcileConfig struct {
+	Interval   string `yaml:"interval" json:"interval"` // Empty or zero disables the reconciler
+	AutoRepair bool   `yaml:"autoRepair" json:"autoRepair"`
+}
+
+// BatchConfig holds settings for the batch registration endpoint
+type BatchConfig struct {
+	Concurrency int `yaml:"concurrency" json:"concurrency"` // Items registered in parallel within one batch
+	MaxItems    int `yaml:"maxItems" json:"maxItems"`       // Largest number of items accepted in one batch
+}
+
+// UpstreamConfig bounds the load this service puts on the Kubernetes and ArgoCD API servers
+type UpstreamConfig struct {
+	MaxConcurrentRequests int    `yaml:"maxConcurrentRequests" json:"maxConcurrentRequests"` // Calls in flight at once across both services; 0 is unlimited
+	QueueTimeout          string `yaml:"queueTimeout" json:"queueTimeout"`                   // How long a call waits for a free slot; empty waits as long as the request does
+}
+
+// NotificationsConfig holds settings for registration lifecycle webhooks
+type NotificationsConfig struct {
+	WebhookURL    string `yaml:"webhookURL" json:"webhookURL"`       // Empty disables notifications
+	SigningSecret string `yaml:"signingSecret" json:"signingSecret"` // Optional HMAC-SHA256 key for payload signatures
+	MaxAttempts   int    `yaml:"maxAttempts" json:"maxAttempts"`
+	Timeout       string `yaml:"timeout" json:"timeout"`
 }
 
 // Load reads configuration from environment variables and config file
@@ -105,7 +410,7 @@ func Load() (*Config, error) {
 
 	// Load from config file if specified (before environment variable overrides)
 	if configPath := os.Getenv("CONFIG_PATH"); configPath != "" {
-		if err := loadFromFile(cfg, configPath); err != nil {
+		if err := loadFromPath(cfg, configPath); err != nil {
 			return nil, fmt.Errorf("failed to load config file %s: %w", configPath, err)
 		}
 	}
@@ -113,9 +418,9 @@ func Load() (*Config, error) {
 	// Override with environment variables (these take precedence over file config)
 	applyEnvironmentOverrides(cfg)
 
-	// Validate resource restrictions
-	if err := validateResourceRestrictions(cfg.Security.ResourceAllowList, cfg.Security.ResourceDenyList); err != nil {
-		return nil, fmt.Errorf("invalid resource restrictions configuration: %w", err)
+	// Fail fast on invalid or contradictory settings
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
 	return cfg, nil
@@ -125,16 +430,38 @@ func Load() (*Config, error) {
 func getDefaultConfig() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port:    8080,
-			Timeout: "30s",
+			Port:              8080,
+			Timeout:           "30s",
+			ShutdownTimeout:   "30s",
+			DrainDelay:        "5s",
+			StartupTimeout:    "60s",
+			ReadinessCacheTTL: "5s",
+			AccessLog: AccessLogConfig{
+				Level:        "info",
+				ExcludePaths: []string{"/health/", "/metrics"},
+			},
+			CORS: CORSConfig{
+				AllowedOrigins:   []string{"*"},
+				AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+				AllowCredentials: true,
+			},
 		},
 		ArgoCD: ArgoCDConfig{
 			Server:    "argocd-server.argocd.svc.cluster.local",
 			Namespace: "argocd",
 			GRPC:      true,
+			SyncRetry: SyncRetryConfig{
+				Limit:              5,
+				BackoffDuration:    "5s",
+				BackoffFactor:      2,
+				BackoffMaxDuration: "3m",
+			},
+			RequestTimeout: "10s",
+			ProjectRole:    DefaultProjectRole(),
 		},
 		Kubernetes: KubernetesConfig{
-			Namespace: "gitops-registration-system",
+			Namespace:      "gitops-registration-system",
+			RequestTimeout: "10s",
 		},
 		Security: SecurityConfig{
 			AllowedResourceTypes: []string{
@@ -152,9 +479,18 @@ func getDefaultConfig() *Config {
 				ValidatePermissions:    true,
 				AutoCleanup:            true,
 			},
+			LegacyServiceAccountName:       "gitops",
+			LegacyRoleBindingName:          "gitops-binding",
+			LegacyRole:                     "gitops-role",
+			RequireManagedLabelForMutation: true,
+			ClusterRoleCacheTTL:            "30s",
 		},
 		Registration: RegistrationConfig{
-			AllowNewNamespaces: true,
+			AllowNewNamespaces:          true,
+			DeleteNamespaceOnDeregister: false,
+			IdempotencyKeyTTL:           "1h",
+			DefaultBranch:               "main",
+			UseApplicationSet:           false,
 		},
 		Authorization: AuthorizationConfig{
 			RequiredRole:              "konflux-admin-user-actions",
@@ -171,6 +507,27 @@ func getDefaultConfig() *Config {
 				"persistentvolumeclaims": "10",
 			},
 		},
+		Observability: ObservabilityConfig{
+			Audit: AuditConfig{
+				Output: "stdout",
+				Level:  "info",
+			},
+		},
+		Reconcile: ReconcileConfig{
+			Interval:   "5m",
+			AutoRepair: false,
+		},
+		Notifications: NotificationsConfig{
+			MaxAttempts: 3,
+			Timeout:     "5s",
+		},
+		Batch: BatchConfig{
+			Concurrency: 4,
+			MaxItems:    50,
+		},
+		Upstream: UpstreamConfig{
+			QueueTimeout: "30s",
+		},
 	}
 }
 
@@ -186,10 +543,36 @@ func applyEnvironmentOverrides(cfg *Config) {
 		cfg.Server.Timeout = timeout
 	}
 
+	if shutdownTimeout := os.Getenv("SERVER_SHUTDOWN_TIMEOUT"); shutdownTimeout != "" {
+		cfg.Server.ShutdownTimeout = shutdownTimeout
+	}
+
+	if drainDelay := os.Getenv("SERVER_DRAIN_DELAY"); drainDelay != "" {
+		cfg.Server.DrainDelay = drainDelay
+	}
+
+	if startupTimeout := os.Getenv("SERVER_STARTUP_TIMEOUT"); startupTimeout != "" {
+		cfg.Server.StartupTimeout = startupTimeout
+	}
+
+	if readinessCacheTTL := os.Getenv("SERVER_READINESS_CACHE_TTL"); readinessCacheTTL != "" {
+		cfg.Server.ReadinessCacheTTL = readinessCacheTTL
+	}
+
+	if healthPort := os.Getenv("SERVER_HEALTH_PORT"); healthPort != "" {
+		if p, err := strconv.Atoi(healthPort); err == nil {
+			cfg.Server.HealthPort = p
+		}
+	}
+
 	if argoCDServer := os.Getenv("ARGOCD_SERVER"); argoCDServer != "" {
 		cfg.ArgoCD.Server = argoCDServer
 	}
 
+	if argoCDToken := os.Getenv("ARGOCD_TOKEN"); argoCDToken != "" {
+		cfg.ArgoCD.Token = argoCDToken
+	}
+
 	if argoCDNamespace := os.Getenv("ARGOCD_NAMESPACE"); argoCDNamespace != "" {
 		cfg.ArgoCD.Namespace = argoCDNamespace
 	}
@@ -202,18 +585,131 @@ func applyEnvironmentOverrides(cfg *Config) {
 		cfg.Security.AllowedResourceTypes = strings.Split(allowedResources, ",")
 	}
 
+	if maintenanceMode := os.Getenv("MAINTENANCE_MODE"); maintenanceMode != "" {
+		if enabled, err := strconv.ParseBool(maintenanceMode); err == nil {
+			cfg.Server.MaintenanceMode = enabled
+		}
+	}
+
 	if allowNewNamespaces := os.Getenv("ALLOW_NEW_NAMESPACES"); allowNewNamespaces != "" {
 		if allowed, err := strconv.ParseBool(allowNewNamespaces); err == nil {
 			cfg.Registration.AllowNewNamespaces = allowed
 		}
 	}
 
+	if deleteNamespace := os.Getenv("DELETE_NAMESPACE_ON_DEREGISTER"); deleteNamespace != "" {
+		if enabled, err := strconv.ParseBool(deleteNamespace); err == nil {
+			cfg.Registration.DeleteNamespaceOnDeregister = enabled
+		}
+	}
+
+	if maxPerUser := os.Getenv("REGISTRATION_MAX_PER_USER"); maxPerUser != "" {
+		if limit, err := strconv.Atoi(maxPerUser); err == nil {
+			cfg.Registration.MaxPerUser = limit
+		}
+	}
+
+	if concurrency := os.Getenv("BATCH_CONCURRENCY"); concurrency != "" {
+		if n, err := strconv.Atoi(concurrency); err == nil {
+			cfg.Batch.Concurrency = n
+		}
+	}
+
+	if maxConcurrent := os.Getenv("UPSTREAM_MAX_CONCURRENT_REQUESTS"); maxConcurrent != "" {
+		if n, err := strconv.Atoi(maxConcurrent); err == nil {
+			cfg.Upstream.MaxConcurrentRequests = n
+		}
+	}
+
+	if validateRepoAccess := os.Getenv("VALIDATE_REPO_ACCESS"); validateRepoAccess != "" {
+		if enabled, err := strconv.ParseBool(validateRepoAccess); err == nil {
+			cfg.Registration.ValidateRepoAccess = enabled
+		}
+	}
+
 	if requiredRole := os.Getenv("AUTHORIZATION_REQUIRED_ROLE"); requiredRole != "" {
 		cfg.Authorization.RequiredRole = requiredRole
 	}
+
+	if auditOutput := os.Getenv("AUDIT_LOG_OUTPUT"); auditOutput != "" {
+		cfg.Observability.Audit.Output = auditOutput
+	}
+
+	if auditLevel := os.Getenv("AUDIT_LOG_LEVEL"); auditLevel != "" {
+		cfg.Observability.Audit.Level = auditLevel
+	}
+
+	if webhookURL := os.Getenv("NOTIFICATIONS_WEBHOOK_URL"); webhookURL != "" {
+		cfg.Notifications.WebhookURL = webhookURL
+	}
+
+	if signingSecret := os.Getenv("NOTIFICATIONS_SIGNING_SECRET"); signingSecret != "" {
+		cfg.Notifications.SigningSecret = signingSecret
+	}
+
+	if impersonationEnabled := os.Getenv("IMPERSONATION_ENABLED"); impersonationEnabled != "" {
+		if enabled, err := strconv.ParseBool(impersonationEnabled); err == nil {
+			cfg.Security.Impersonation.Enabled = enabled
+		}
+	}
+
+	if clusterRole := os.Getenv("IMPERSONATION_CLUSTER_ROLE"); clusterRole != "" {
+		cfg.Security.Impersonation.ClusterRole = clusterRole
+	}
+
+	if baseName := os.Getenv("IMPERSONATION_SA_BASE_NAME"); baseName != "" {
+		cfg.Security.Impersonation.ServiceAccountBaseName = baseName
+	}
+
+	if capacityEnabled := os.Getenv("CAPACITY_ENABLED"); capacityEnabled != "" {
+		if enabled, err := strconv.ParseBool(capacityEnabled); err == nil {
+			cfg.Capacity.Enabled = enabled
+		}
+	}
+
+	if maxNamespaces := os.Getenv("CAPACITY_MAX_NAMESPACES"); maxNamespaces != "" {
+		if limit, err := strconv.Atoi(maxNamespaces); err == nil {
+			cfg.Capacity.Limits.MaxNamespaces = limit
+		}
+	}
+}
+
+// loadFromFile loads configuration from a YAML or JSON file, chosen by extension.
+// Files without a recognized extension are parsed as YAML.
+// loadFromPath loads a single config file, or every YAML file in a directory in lexical order.
+// Each file only overrides the settings it contains, so later files act as overlays on earlier ones.
+func loadFromPath(cfg *Config, path string) error {
+	info, err := os.Stat(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return loadFromFile(cfg, path)
+	}
+
+	entries, err := os.ReadDir(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	// ReadDir returns entries sorted by file name
+	loaded := 0
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		if err := loadFromFile(cfg, filepath.Join(path, entry.Name())); err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		loaded++
+	}
+	if loaded == 0 {
+		return fmt.Errorf("no YAML files found in directory")
+	}
+	return nil
 }
 
-// loadFromFile loads configuration from a YAML file
 func loadFromFile(cfg *Config, path string) error {
 	// Validate path to prevent file inclusion vulnerabilities
 	cleanPath := filepath.Clean(path)
@@ -223,30 +719,49 @@ func loadFromFile(cfg *Config, path string) error {
 		return fmt.Errorf("config path contains directory traversal: %s", path)
 	}
 
-	// Validate file extension
-	if ext := filepath.Ext(cleanPath); ext != ".yaml" && ext != ".yml" {
-		return fmt.Errorf("config file must be a YAML file (.yaml or .yml): %s", path)
-	}
-
 	data, err := os.ReadFile(cleanPath) // #nosec G304 -- path is validated above
 	if err != nil {
 		return err
 	}
 
-	return yaml.Unmarshal(data, cfg)
+	switch strings.ToLower(filepath.Ext(cleanPath)) {
+	case ".json":
+		return json.Unmarshal(data, cfg)
+	default:
+		return yaml.Unmarshal(data, cfg)
+	}
+}
+
+// ResourceRestrictionError reports an invalid resource allow or deny list
+type ResourceRestrictionError struct {
+	Field  string // resourceAllowList or resourceDenyList
+	Index  int    // offending entry, or -1 when the lists conflict as a whole
+	Reason string
+}
+
+func (e *ResourceRestrictionError) Error() string {
+	if e.Index < 0 {
+		return e.Reason
+	}
+	return fmt.Sprintf("%s[%d]: %s", e.Field, e.Index, e.Reason)
 }
 
-// validateResourceRestrictions validates service-level resource restrictions
-func validateResourceRestrictions(allowList, denyList []ServiceResourceRestriction) error {
+// ValidateResourceRestrictions validates resource allow and deny lists, returning a *ResourceRestrictionError.
+// It applies to the service-level lists as well as those supplied with a registration request.
+func ValidateResourceRestrictions(allowList, denyList []ServiceResourceRestriction) error {
 	// Ensure only allowList OR denyList is provided, not both
 	if len(allowList) > 0 && len(denyList) > 0 {
-		return fmt.Errorf("cannot specify both resourceAllowList and resourceDenyList; provide only one")
+		return &ResourceRestrictionError{
+			Field:  "resourceDenyList",
+			Index:  -1,
+			Reason: "cannot specify both resourceAllowList and resourceDenyList; provide only one",
+		}
 	}
 
 	// Validate allowList entries
 	for i, resource := range allowList {
 		if resource.Kind == "" {
-			return fmt.Errorf("resourceAllowList[%d]: kind is required", i)
+			return &ResourceRestrictionError{Field: "resourceAllowList", Index: i, Reason: "kind is required"}
 		}
 		// Note: group can be empty for core resources, so we don't validate it
 	}
@@ -254,7 +769,7 @@ func validateResourceRestrictions(allowList, denyList []ServiceResourceRestricti
 	// Validate denyList entries
 	for i, resource := range denyList {
 		if resource.Kind == "" {
-			return fmt.Errorf("resourceDenyList[%d]: kind is required", i)
+			return &ResourceRestrictionError{Field: "resourceDenyList", Index: i, Reason: "kind is required"}
 		}
 		// Note: group can be empty for core resources, so we don't validate it
 	}
@@ -262,6 +777,250 @@ func validateResourceRestrictions(allowList, denyList []ServiceResourceRestricti
 	return nil
 }
 
+// Validate checks the configuration for invalid or contradictory settings,
+// reporting every problem found rather than stopping at the first one
+func (c *Config) Validate() error {
+	var errs []error
+
+	if err := ValidateResourceRestrictions(c.Security.ResourceAllowList, c.Security.ResourceDenyList); err != nil {
+		errs = append(errs, fmt.Errorf("invalid resource restrictions configuration: %w", err))
+	}
+
+	if err := c.ValidateImpersonationConfig(); err != nil {
+		errs = append(errs, err)
+	}
+
+	errs = append(errs, c.validateSecurityConsistency()...)
+
+	if c.Registration.ValidateRepoAccess && (c.ArgoCD.Server == "" || c.ArgoCD.Token == "") {
+		errs = append(errs, fmt.Errorf("argocd.server and argocd.token must be set when registration.validateRepoAccess is enabled"))
+	}
+
+	if c.Registration.MaxPerUser < 0 {
+		errs = append(errs, fmt.Errorf("registration.maxPerUser must not be negative, got %d", c.Registration.MaxPerUser))
+	}
+
+	if c.Batch.Concurrency < 0 {
+		errs = append(errs, fmt.Errorf("batch.concurrency must not be negative, got %d", c.Batch.Concurrency))
+	}
+	if c.Batch.MaxItems < 0 {
+		errs = append(errs, fmt.Errorf("batch.maxItems must not be negative, got %d", c.Batch.MaxItems))
+	}
+
+	if c.Upstream.MaxConcurrentRequests < 0 {
+		errs = append(errs, fmt.Errorf("upstream.maxConcurrentRequests must not be negative, got %d",
+			c.Upstream.MaxConcurrentRequests))
+	}
+	if c.Upstream.QueueTimeout != "" {
+		if d, err := time.ParseDuration(c.Upstream.QueueTimeout); err != nil || d <= 0 {
+			errs = append(errs, fmt.Errorf("upstream.queueTimeout must be a positive duration, got %q", c.Upstream.QueueTimeout))
+		}
+	}
+
+	if c.Capacity.Enabled && c.Capacity.Limits.MaxNamespaces <= 0 {
+		errs = append(errs, fmt.Errorf("capacity.limits.maxNamespaces must be positive when capacity is enabled"))
+	}
+
+	if c.Server.ShutdownTimeout != "" {
+		if d, err := time.ParseDuration(c.Server.ShutdownTimeout); err != nil || d <= 0 {
+			errs = append(errs, fmt.Errorf("server.shutdownTimeout must be a positive duration, got %q", c.Server.ShutdownTimeout))
+		}
+	}
+
+	if c.Server.DrainDelay != "" {
+		if d, err := time.ParseDuration(c.Server.DrainDelay); err != nil || d < 0 {
+			errs = append(errs, fmt.Errorf("server.drainDelay must be a non-negative duration, got %q", c.Server.DrainDelay))
+		}
+	}
+
+	if c.Server.StartupTimeout != "" {
+		if d, err := time.ParseDuration(c.Server.StartupTimeout); err != nil || d <= 0 {
+			errs = append(errs, fmt.Errorf("server.startupTimeout must be a positive duration, got %q", c.Server.StartupTimeout))
+		}
+	}
+
+	if c.Server.ReadinessCacheTTL != "" {
+		if d, err := time.ParseDuration(c.Server.ReadinessCacheTTL); err != nil || d < 0 {
+			errs = append(errs, fmt.Errorf("server.readinessCacheTTL must be a non-negative duration, got %q", c.Server.ReadinessCacheTTL))
+		}
+	}
+
+	if c.Server.HealthPort < 0 || c.Server.HealthPort > 65535 {
+		errs = append(errs, fmt.Errorf("server.healthPort must be between 0 and 65535, got %d", c.Server.HealthPort))
+	} else if c.Server.HealthPort != 0 && c.Server.HealthPort == c.Server.Port {
+		errs = append(errs, fmt.Errorf("server.healthPort must differ from server.port %d", c.Server.Port))
+	}
+
+	if c.Registration.WaitForTerminatingNamespace != "" {
+		if d, err := time.ParseDuration(c.Registration.WaitForTerminatingNamespace); err != nil || d < 0 {
+			errs = append(errs, fmt.Errorf("registration.waitForTerminatingNamespace must be a non-negative duration, got %q",
+				c.Registration.WaitForTerminatingNamespace))
+		}
+	}
+	if c.Registration.WaitForNamespaceActive != "" {
+		if d, err := time.ParseDuration(c.Registration.WaitForNamespaceActive); err != nil || d < 0 {
+			errs = append(errs, fmt.Errorf("registration.waitForNamespaceActive must be a non-negative duration, got %q",
+				c.Registration.WaitForNamespaceActive))
+		}
+	}
+
+	if c.Registration.AllowClusterScopedDestination && len(c.Security.ResourceAllowList) == 0 {
+		errs = append(errs, fmt.Errorf("registration.allowClusterScopedDestination requires security.resourceAllowList"))
+	}
+	for i, pattern := range c.Registration.NamespaceDenyPatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("registration.namespaceDenyPatterns[%d]: invalid pattern %q", i, pattern))
+		}
+	}
+
+	for i, window := range c.ArgoCD.DefaultSyncWindows {
+		if err := ValidateSyncWindow(window.Kind, window.Schedule, window.Duration); err != nil {
+			errs = append(errs, fmt.Errorf("argocd.defaultSyncWindows[%d]: %w", i, err))
+		}
+	}
+	subscribed := make(map[string]bool, len(c.ArgoCD.DefaultNotifications))
+	for i, subscription := range c.ArgoCD.DefaultNotifications {
+		if err := ValidateNotificationSubscription(subscription.Trigger, subscription.Service, subscription.Recipients); err != nil {
+			errs = append(errs, fmt.Errorf("argocd.defaultNotifications[%d]: %w", i, err))
+			continue
+		}
+		key := NotificationSubscriptionKey(subscription.Trigger, subscription.Service)
+		if subscribed[key] {
+			errs = append(errs, fmt.Errorf("argocd.defaultNotifications[%d]: duplicate subscription to %s through %s",
+				i, subscription.Trigger, subscription.Service))
+		}
+		subscribed[key] = true
+	}
+
+	if key := c.Security.RepositoryHashLabelKey; key != "" {
+		if problems := k8svalidation.IsQualifiedName(key); len(problems) > 0 {
+			errs = append(errs, fmt.Errorf("security.repositoryHashLabelKey %q is not a valid label key: %s",
+				key, strings.Join(problems, "; ")))
+		}
+	}
+
+	if c.Security.ClusterRoleCacheTTL != "" {
+		if d, err := time.ParseDuration(c.Security.ClusterRoleCacheTTL); err != nil || d < 0 {
+			errs = append(errs, fmt.Errorf("security.clusterRoleCacheTTL must be a non-negative duration, got %q",
+				c.Security.ClusterRoleCacheTTL))
+		}
+	}
+
+	if length := c.Security.RepositoryHashLength; length != 0 && (length < 8 || length > 63) {
+		errs = append(errs, fmt.Errorf("security.repositoryHashLength must be between 8 and 63, got %d", length))
+	}
+
+	switch c.Security.ConflictScope {
+	case "", ConflictScopeRepo, ConflictScopeRepoPath:
+	default:
+		errs = append(errs, fmt.Errorf("security.conflictScope must be %q or %q, got %q",
+			ConflictScopeRepo, ConflictScopeRepoPath, c.Security.ConflictScope))
+	}
+
+	if role := c.ArgoCD.ProjectRole; role.Name != "" || len(role.Policies) > 0 {
+		errs = append(errs, role.validate()...)
+	}
+
+	errs = append(errs, c.validateTLS()...)
+	errs = append(errs, validateDefaultNamespaceMetadata("defaultNamespaceLabels", c.Tenants.DefaultNamespaceLabels)...)
+	errs = append(errs, validateDefaultNamespaceMetadata("defaultNamespaceAnnotations", c.Tenants.DefaultNamespaceAnnotations)...)
+	errs = append(errs, c.Tenants.validateAllowedAnnotations()...)
+	if c.Tenants.DefaultLimitRange != nil {
+		errs = append(errs, c.Tenants.DefaultLimitRange.validate()...)
+	}
+
+	if c.Observability.Tracing.Enabled && c.Observability.Tracing.OTLPEndpoint == "" {
+		errs = append(errs, fmt.Errorf("observability.tracing.otlpEndpoint must be set when tracing is enabled"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateTLS checks that certificate files are provided together and client auth has a CA to verify against
+func (c *Config) validateTLS() []error {
+	tlsCfg := c.Server.TLS
+
+	var errs []error
+	if (tlsCfg.CertFile == "") != (tlsCfg.KeyFile == "") {
+		errs = append(errs, fmt.Errorf("server.tls.certFile and server.tls.keyFile must be set together"))
+	}
+	if tlsCfg.ClientAuth {
+		if tlsCfg.CertFile == "" {
+			errs = append(errs, fmt.Errorf("server.tls.clientAuth requires server.tls.certFile and server.tls.keyFile"))
+		}
+		if tlsCfg.ClientCAFile == "" {
+			errs = append(errs, fmt.Errorf("server.tls.clientAuth requires server.tls.clientCAFile"))
+		}
+	}
+	return errs
+}
+
+// validateDefaultNamespaceMetadata rejects default keys that would collide with service-managed metadata
+func validateDefaultNamespaceMetadata(field string, metadata map[string]string) []error {
+	var errs []error
+	for key := range metadata {
+		if strings.HasPrefix(key, ReservedMetadataPrefix) {
+			errs = append(errs, fmt.Errorf("tenants.%s: key %s uses the reserved %s prefix", field, key, ReservedMetadataPrefix))
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errs
+}
+
+// validateAllowedAnnotations rejects empty prefixes and default annotations that allowedAnnotationPrefixes
+// would refuse, so the misconfiguration fails at startup rather than on every registration
+func (t TenantsConfig) validateAllowedAnnotations() []error {
+	var errs []error
+	for i, prefix := range t.AllowedAnnotationPrefixes {
+		if strings.TrimSpace(prefix) == "" {
+			errs = append(errs, fmt.Errorf("tenants.allowedAnnotationPrefixes[%d] must not be empty", i))
+		}
+	}
+	var keyErrs []error
+	for key := range t.DefaultNamespaceAnnotations {
+		if !strings.HasPrefix(key, ReservedMetadataPrefix) && !t.AnnotationAllowed(key) {
+			keyErrs = append(keyErrs, fmt.Errorf(
+				"tenants.defaultNamespaceAnnotations: key %s does not match tenants.allowedAnnotationPrefixes", key))
+		}
+	}
+	sort.Slice(keyErrs, func(i, j int) bool { return keyErrs[i].Error() < keyErrs[j].Error() })
+	return append(errs, keyErrs...)
+}
+
+// validate checks that the LimitRange sets at least one default and that every value is a valid quantity
+func (l *LimitRangeConfig) validate() []error {
+	if len(l.Default) == 0 && len(l.DefaultRequest) == 0 {
+		return []error{fmt.Errorf("tenants.defaultLimitRange must set default or defaultRequest")}
+	}
+
+	var errs []error
+	for field, values := range map[string]map[string]string{"default": l.Default, "defaultRequest": l.DefaultRequest} {
+		for name, value := range values {
+			if _, err := resource.ParseQuantity(value); err != nil {
+				errs = append(errs, fmt.Errorf("tenants.defaultLimitRange.%s.%s: invalid quantity %q", field, name, value))
+			}
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errs
+}
+
+// validateSecurityConsistency detects security settings that contradict each other
+func (c *Config) validateSecurityConsistency() []error {
+	if !c.Security.Impersonation.Enabled {
+		return nil
+	}
+
+	var errs []error
+	if !c.Security.EnableServiceAccountImpersonation {
+		errs = append(errs, fmt.Errorf("impersonation.enabled is true but enableServiceAccountImpersonation is false"))
+	}
+	if !c.Security.RequireAppProjectPerTenant {
+		errs = append(errs, fmt.Errorf("impersonation.enabled requires requireAppProjectPerTenant to be true"))
+	}
+	return errs
+}
+
 // ValidateImpersonationConfig validates the impersonation configuration
 func (c *Config) ValidateImpersonationConfig() error {
 	if !c.Security.Impersonation.Enabled {
@@ -278,3 +1037,56 @@ func (c *Config) ValidateImpersonationConfig() error {
 
 	return nil
 }
+
+// Redacted returns a copy of the configuration that is safe to expose over the API or write to logs.
+// Credentials embedded in server and collector URLs, the ArgoCD token and the webhook signing secret are masked.
+func (c *Config) Redacted() *Config {
+	redacted := *c
+
+	redacted.ArgoCD.Server = redactURL(c.ArgoCD.Server)
+	if c.ArgoCD.Token != "" {
+		redacted.ArgoCD.Token = "REDACTED"
+	}
+	if c.ArgoCD.AllowedDestinationClusters != nil {
+		redacted.ArgoCD.AllowedDestinationClusters = make([]string, len(c.ArgoCD.AllowedDestinationClusters))
+		for i, server := range c.ArgoCD.AllowedDestinationClusters {
+			redacted.ArgoCD.AllowedDestinationClusters[i] = redactURL(server)
+		}
+	}
+
+	redacted.Observability.Tracing.OTLPEndpoint = redactURL(c.Observability.Tracing.OTLPEndpoint)
+
+	redacted.Notifications.WebhookURL = redactWebhookURL(c.Notifications.WebhookURL)
+	if c.Notifications.SigningSecret != "" {
+		redacted.Notifications.SigningSecret = "REDACTED"
+	}
+
+	return &redacted
+}
+
+// redactURL masks any userinfo present in a URL, leaving other values untouched
+func redactURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.User == nil {
+		return raw
+	}
+	parsed.User = url.User("REDACTED")
+	return parsed.String()
+}
+
+// redactWebhookURL keeps only the scheme and host of a webhook URL. Incoming-webhook services such as
+// Slack embed the credential in the path or query, so everything else is masked.
+func redactWebhookURL(raw string) string {
+	if raw == "" {
+		return raw
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return "REDACTED"
+	}
+	redacted := &url.URL{Scheme: parsed.Scheme, Host: parsed.Host}
+	if parsed.User != nil || parsed.Path != "" || parsed.RawQuery != "" || parsed.Fragment != "" {
+		redacted.Path = "/REDACTED"
+	}
+	return redacted.String()
+}
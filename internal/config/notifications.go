@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+)
+
+// NotificationSubscribeAnnotationPrefix starts the ArgoCD notifications annotation subscribing an Application
+// to a trigger: notifications.argoproj.io/subscribe.<trigger>.<service>: <recipient>;<recipient>
+const NotificationSubscribeAnnotationPrefix = "notifications.argoproj.io/subscribe."
+
+// NotificationSubscriptionConfig describes an ArgoCD notifications subscription added to tenant Applications
+type NotificationSubscriptionConfig struct {
+	Trigger    string   `yaml:"trigger" json:"trigger"` // e.g. on-health-degraded
+	Service    string   `yaml:"service" json:"service"` // e.g. slack or email, as configured in argocd-notifications-cm
+	Recipients []string `yaml:"recipients,omitempty" json:"recipients,omitempty"`
+}
+
+// notificationNamePattern matches trigger and service names; dots would make the annotation key ambiguous
+var notificationNamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([-_a-zA-Z0-9]*[a-zA-Z0-9])?$`)
+
+// NotificationSubscriptionKey returns the annotation key subscribing an Application to trigger through service
+func NotificationSubscriptionKey(trigger, service string) string {
+	return NotificationSubscribeAnnotationPrefix + trigger + "." + service
+}
+
+// ValidateNotificationSubscription checks that a subscription renders to a valid annotation. Recipients are
+// optional, as services like webhook or github take none, but must not be empty or contain the ; separator.
+func ValidateNotificationSubscription(trigger, service string, recipients []string) error {
+	if !notificationNamePattern.MatchString(trigger) {
+		return fmt.Errorf("notification trigger %q must be alphanumeric with - or _", trigger)
+	}
+	if !notificationNamePattern.MatchString(service) {
+		return fmt.Errorf("notification service %q must be alphanumeric with - or _", service)
+	}
+	if errs := k8svalidation.IsQualifiedName(NotificationSubscriptionKey(trigger, service)); len(errs) > 0 {
+		return fmt.Errorf("notification trigger %q and service %q are too long: %s", trigger, service, strings.Join(errs, "; "))
+	}
+	for i, recipient := range recipients {
+		if strings.TrimSpace(recipient) == "" || strings.Contains(recipient, ";") {
+			return fmt.Errorf("notification recipients[%d] must be non-empty and must not contain ;", i)
+		}
+	}
+	return nil
+}
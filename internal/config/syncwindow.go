@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SyncWindowConfig describes an ArgoCD sync window added to tenant AppProjects
+type SyncWindowConfig struct {
+	Kind         string   `yaml:"kind" json:"kind"`         // allow or deny
+	Schedule     string   `yaml:"schedule" json:"schedule"` // Standard five-field cron expression
+	Duration     string   `yaml:"duration" json:"duration"`
+	Applications []string `yaml:"applications,omitempty" json:"applications,omitempty"`
+}
+
+// cronField is the accepted range of one field of a cron schedule
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // Aliases for min, min+1, ...
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12,
+		names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// cronDescriptors are the predefined schedules ArgoCD accepts in place of five fields
+var cronDescriptors = map[string]bool{
+	"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+	"@daily": true, "@midnight": true, "@hourly": true,
+}
+
+// ValidateSyncWindow checks a sync window the way ArgoCD would when the AppProject is applied
+func ValidateSyncWindow(kind, schedule, duration string) error {
+	if kind != "allow" && kind != "deny" {
+		return fmt.Errorf("sync window kind must be allow or deny, got %q", kind)
+	}
+	if err := ValidateCronSchedule(schedule); err != nil {
+		return fmt.Errorf("invalid sync window schedule %q: %w", schedule, err)
+	}
+	if d, err := time.ParseDuration(duration); err != nil || d <= 0 {
+		return fmt.Errorf("sync window duration must be a positive duration, got %q", duration)
+	}
+	return nil
+}
+
+// ValidateCronSchedule checks a standard five-field cron expression or a predefined @ descriptor
+func ValidateCronSchedule(schedule string) error {
+	if strings.HasPrefix(schedule, "@") {
+		if !cronDescriptors[schedule] {
+			return fmt.Errorf("unknown descriptor %s", schedule)
+		}
+		return nil
+	}
+
+	fields := strings.Fields(schedule)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("expected %d fields, got %d", len(cronFields), len(fields))
+	}
+	for i, value := range fields {
+		for _, part := range strings.Split(value, ",") {
+			if err := cronFields[i].validate(part); err != nil {
+				return fmt.Errorf("%s field: %w", cronFields[i].name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks one comma-separated part of a field: *, a value or a range, with an optional /step
+func (f cronField) validate(part string) error {
+	rangePart, step, hasStep := strings.Cut(part, "/")
+	if hasStep {
+		if n, err := strconv.Atoi(step); err != nil || n <= 0 {
+			return fmt.Errorf("invalid step %q", step)
+		}
+	}
+	if rangePart == "*" || rangePart == "?" {
+		return nil
+	}
+
+	low, high, isRange := strings.Cut(rangePart, "-")
+	start, err := f.value(low)
+	if err != nil {
+		return err
+	}
+	if !isRange {
+		return nil
+	}
+	end, err := f.value(high)
+	if err != nil {
+		return err
+	}
+	if start > end {
+		return fmt.Errorf("range %s is reversed", rangePart)
+	}
+	return nil
+}
+
+// value parses a number or alias within the field's range
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", n, f.min, f.max)
+	}
+	return n, nil
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/audit"
+	"github.com/konflux-ci/gitops-registration-service/internal/notifier"
+	"github.com/konflux-ci/gitops-registration-service/internal/services"
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+)
+
+// Used when batch.concurrency or batch.maxItems are left unset
+const (
+	defaultBatchConcurrency = 4
+	defaultBatchMaxItems    = 50
+)
+
+// CreateRegistrationBatch handles POST /api/v1/registrations/batch
+func (h *RegistrationHandler) CreateRegistrationBatch(w http.ResponseWriter, r *http.Request) {
+	var req types.BatchRegistrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "INVALID_REQUEST", "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+
+	userInfo, err := h.extractUserInfo(r)
+	if err != nil {
+		h.writeErrorResponse(w, "AUTHENTICATION_REQUIRED", "Valid authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	maxItems := h.cfg.Batch.MaxItems
+	if maxItems == 0 {
+		maxItems = defaultBatchMaxItems
+	}
+	if len(req.Registrations) == 0 {
+		h.writeErrorResponse(w, "INVALID_REQUEST", "Batch must contain at least one registration", http.StatusBadRequest)
+		return
+	}
+	if len(req.Registrations) > maxItems {
+		h.writeErrorResponse(w, "INVALID_REQUEST",
+			fmt.Sprintf("Batch contains %d registrations, at most %d are allowed", len(req.Registrations), maxItems),
+			http.StatusBadRequest)
+		return
+	}
+
+	if controlErr := h.services.RegistrationControl.IsNewNamespaceAllowed(r.Context()); controlErr != nil {
+		h.writeErrorResponse(w, "REGISTRATION_DISABLED", controlErr.Error(), http.StatusForbidden)
+		return
+	}
+
+	h.logger.WithField("user", userInfo.Username).Infof("Creating %d registrations in batch", len(req.Registrations))
+
+	// Each worker writes only its own index, so results keep request order without further locking.
+	// Items for the same namespace still serialize on the registration service's namespace lock.
+	results := make([]types.BatchRegistrationResult, len(req.Registrations))
+	forEachBounded(len(req.Registrations), h.cfg.Batch.Concurrency, func(i int) {
+		results[i] = h.createBatchItem(r, i, &req.Registrations[i], userInfo)
+	})
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(types.BatchRegistrationResponse{Results: results}); err != nil {
+		h.logger.WithError(err).Error("Failed to encode batch registration response")
+	}
+}
+
+// createBatchItem registers one batch item and reports its outcome as CreateRegistration would
+func (h *RegistrationHandler) createBatchItem(r *http.Request, index int, req *types.RegistrationRequest,
+	userInfo *types.UserInfo) types.BatchRegistrationResult {
+	result := types.BatchRegistrationResult{Index: index, Namespace: req.Namespace}
+
+	if validationErr := h.services.Registration.ValidateRegistration(r.Context(), req); validationErr != nil {
+		result.Error = validationErrorResponse(validationErr)
+		result.Status = result.Error.Code
+		return result
+	}
+
+	registration, err := h.services.Registration.CreateRegistration(r.Context(), req, userInfo)
+	var duplicate *services.DuplicateRegistrationError
+	if errors.As(err, &duplicate) {
+		result.Status = http.StatusCreated
+		result.Registration = duplicate.Registration
+		result.Registration.SelfLink = registrationPath(duplicate.Registration.ID)
+		return result
+	}
+	if err != nil {
+		h.logger.WithError(err).WithField("namespace", req.Namespace).Error("Failed to create batch registration")
+		audit.Audit(r.Context(), audit.ActionRegistrationCreate, userInfo.Username, req.Namespace, audit.OutcomeFailure)
+		h.notifier.Notify(notifier.Event{
+			Type:      notifier.EventRegistrationFailed,
+			Namespace: req.Namespace,
+			Repo:      req.Repository.URL,
+			Status:    services.StatusFailed,
+		})
+
+		result.Error = registrationErrorResponse(err)
+		result.Status = result.Error.Code
+		return result
+	}
+
+	audit.Audit(r.Context(), audit.ActionRegistrationCreate, userInfo.Username, registration.Namespace, audit.OutcomeSuccess)
+	h.notifyRegistered(registration)
+
+	result.Status = http.StatusCreated
+	result.Registration = registration
+	result.Registration.SelfLink = registrationPath(registration.ID)
+	return result
+}
+
+// forEachBounded calls fn for every index in [0, n) with at most concurrency calls in flight,
+// returning once all calls have finished
+func forEachBounded(n, concurrency int, fn func(i int)) {
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
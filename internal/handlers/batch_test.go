@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/services"
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func batchRequest(t *testing.T, namespaces ...string) *http.Request {
+	t.Helper()
+	batch := types.BatchRegistrationRequest{}
+	for _, ns := range namespaces {
+		batch.Registrations = append(batch.Registrations, types.RegistrationRequest{
+			Repository: types.Repository{URL: "https://github.com/test/" + ns},
+			Namespace:  ns,
+		})
+	}
+	body, err := json.Marshal(batch)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/api/v1/registrations/batch", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer valid-token")
+	return req
+}
+
+func namespaceIs(ns string) interface{} {
+	return mock.MatchedBy(func(req *types.RegistrationRequest) bool { return req.Namespace == ns })
+}
+
+func TestRegistrationHandler_CreateRegistrationBatch_PreservesOrder(t *testing.T) {
+	handler, mocks := setupTestHandler()
+	handler.cfg.Batch.Concurrency = 4
+
+	userInfo := &types.UserInfo{Username: "test-user"}
+	mocks.Authorization.On("ExtractUserInfo", mock.Anything, "valid-token").Return(userInfo, nil)
+	mocks.RegistrationControl.On("IsNewNamespaceAllowed", mock.Anything).Return(nil)
+	mocks.Registration.On("ValidateRegistration", mock.Anything, mock.Anything).Return(nil)
+
+	// Earlier items finish last, so completion order is the reverse of request order
+	var namespaces []string
+	for i := 0; i < 8; i++ {
+		ns := fmt.Sprintf("team-%d", i)
+		namespaces = append(namespaces, ns)
+		delay := time.Duration(8-i) * 5 * time.Millisecond
+		mocks.Registration.On("CreateRegistration", mock.Anything, namespaceIs(ns), userInfo).
+			Run(func(mock.Arguments) { time.Sleep(delay) }).
+			Return(&types.Registration{ID: "reg-" + ns, Namespace: ns}, nil)
+	}
+
+	w := httptest.NewRecorder()
+	handler.CreateRegistrationBatch(w, batchRequest(t, namespaces...))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response types.BatchRegistrationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Results, len(namespaces))
+	for i, result := range response.Results {
+		assert.Equal(t, i, result.Index)
+		assert.Equal(t, namespaces[i], result.Namespace)
+		assert.Equal(t, http.StatusCreated, result.Status)
+		require.NotNil(t, result.Registration)
+		assert.Equal(t, "reg-"+namespaces[i], result.Registration.ID)
+		assert.Equal(t, "/api/v1/registrations/reg-"+namespaces[i], result.Registration.SelfLink)
+		assert.Nil(t, result.Error)
+	}
+	mocks.Registration.AssertExpectations(t)
+}
+
+func TestRegistrationHandler_CreateRegistrationBatch_MixedResults(t *testing.T) {
+	handler, mocks := setupTestHandler()
+
+	userInfo := &types.UserInfo{Username: "test-user"}
+	mocks.Authorization.On("ExtractUserInfo", mock.Anything, "valid-token").Return(userInfo, nil)
+	mocks.RegistrationControl.On("IsNewNamespaceAllowed", mock.Anything).Return(nil)
+	mocks.Registration.On("ValidateRegistration", mock.Anything, namespaceIs("Invalid_NS")).
+		Return(errors.New("invalid namespace name"))
+	mocks.Registration.On("ValidateRegistration", mock.Anything, mock.Anything).Return(nil)
+	mocks.Registration.On("CreateRegistration", mock.Anything, namespaceIs("team-a"), userInfo).
+		Return(&types.Registration{ID: "reg-a", Namespace: "team-a"}, nil)
+	mocks.Registration.On("CreateRegistration", mock.Anything, namespaceIs("team-b"), userInfo).
+		Return((*types.Registration)(nil), &services.NamespaceConflictError{Namespace: "team-b"})
+	mocks.Registration.On("CreateRegistration", mock.Anything, namespaceIs("team-c"), userInfo).
+		Return((*types.Registration)(nil), errors.New("boom"))
+
+	w := httptest.NewRecorder()
+	handler.CreateRegistrationBatch(w, batchRequest(t, "team-a", "team-b", "Invalid_NS", "team-c"))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response types.BatchRegistrationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Results, 4)
+
+	assert.Equal(t, http.StatusCreated, response.Results[0].Status)
+	assert.Equal(t, "reg-a", response.Results[0].Registration.ID)
+
+	assert.Equal(t, http.StatusConflict, response.Results[1].Status)
+	assert.Equal(t, "NAMESPACE_CONFLICT", response.Results[1].Error.Error)
+	assert.Nil(t, response.Results[1].Registration)
+
+	assert.Equal(t, http.StatusBadRequest, response.Results[2].Status)
+	assert.Equal(t, "INVALID_REQUEST", response.Results[2].Error.Error)
+
+	assert.Equal(t, http.StatusInternalServerError, response.Results[3].Status)
+	assert.Equal(t, "REGISTRATION_FAILED", response.Results[3].Error.Error)
+
+	mocks.Registration.AssertNotCalled(t, "CreateRegistration", mock.Anything, namespaceIs("Invalid_NS"), mock.Anything)
+}
+
+func TestRegistrationHandler_CreateRegistrationBatch_Rejected(t *testing.T) {
+	t.Run("no authentication", func(t *testing.T) {
+		handler, _ := setupTestHandler()
+		req := batchRequest(t, "team-a")
+		req.Header.Del("Authorization")
+
+		w := httptest.NewRecorder()
+		handler.CreateRegistrationBatch(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		handler, mocks := setupTestHandler()
+		mocks.Authorization.On("ExtractUserInfo", mock.Anything, "valid-token").
+			Return(&types.UserInfo{Username: "test-user"}, nil)
+
+		w := httptest.NewRecorder()
+		handler.CreateRegistrationBatch(w, batchRequest(t))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("too many items", func(t *testing.T) {
+		handler, mocks := setupTestHandler()
+		handler.cfg.Batch.MaxItems = 2
+		mocks.Authorization.On("ExtractUserInfo", mock.Anything, "valid-token").
+			Return(&types.UserInfo{Username: "test-user"}, nil)
+
+		w := httptest.NewRecorder()
+		handler.CreateRegistrationBatch(w, batchRequest(t, "team-a", "team-b", "team-c"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mocks.Registration.AssertNotCalled(t, "CreateRegistration", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("registration disabled", func(t *testing.T) {
+		handler, mocks := setupTestHandler()
+		mocks.Authorization.On("ExtractUserInfo", mock.Anything, "valid-token").
+			Return(&types.UserInfo{Username: "test-user"}, nil)
+		mocks.RegistrationControl.On("IsNewNamespaceAllowed", mock.Anything).
+			Return(errors.New("new namespace registration is disabled"))
+
+		w := httptest.NewRecorder()
+		handler.CreateRegistrationBatch(w, batchRequest(t, "team-a"))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestForEachBounded(t *testing.T) {
+	const n, concurrency = 20, 3
+
+	var inFlight, maxInFlight int32
+	var mu sync.Mutex
+	seen := make(map[int]int)
+
+	forEachBounded(n, concurrency, func(i int) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			prev := atomic.LoadInt32(&maxInFlight)
+			if current <= prev || atomic.CompareAndSwapInt32(&maxInFlight, prev, current) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+
+		mu.Lock()
+		seen[i]++
+		mu.Unlock()
+	})
+
+	assert.LessOrEqual(t, maxInFlight, int32(concurrency))
+	assert.Len(t, seen, n)
+	for i := 0; i < n; i++ {
+		assert.Equal(t, 1, seen[i], "index %d", i)
+	}
+}
@@ -1,44 +1,65 @@
 package handlers
 
 import (
+	"context"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/konflux-ci/gitops-registration-service/internal/audit"
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/konflux-ci/gitops-registration-service/internal/notifier"
 	"github.com/konflux-ci/gitops-registration-service/internal/services"
 	"github.com/konflux-ci/gitops-registration-service/internal/types"
 	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
-// isNamespaceConflictError checks if the error is a namespace conflict error
-func isNamespaceConflictError(err error) bool {
-	return strings.Contains(err.Error(), "already exists")
-}
-
-// isRepositoryConflictError checks if the error is a repository conflict error
-func isRepositoryConflictError(err error) bool {
-	return strings.Contains(err.Error(), "already registered")
-}
-
 // RegistrationHandler handles registration-related HTTP requests
 type RegistrationHandler struct {
-	services *services.Services
-	logger   *logrus.Logger
+	cfg         *config.Config
+	services    *services.Services
+	logger      *logrus.Logger
+	idempotency *idempotencyCache
+	notifier    notifier.Notifier
 }
 
 // NewRegistrationHandler creates a new registration handler
-func NewRegistrationHandler(services *services.Services, logger *logrus.Logger) *RegistrationHandler {
+func NewRegistrationHandler(cfg *config.Config, services *services.Services, logger *logrus.Logger) *RegistrationHandler {
+	ttl, err := time.ParseDuration(cfg.Registration.IdempotencyKeyTTL)
+	if err != nil {
+		logger.WithError(err).Warnf("Invalid idempotency key TTL, using default %s", defaultIdempotencyKeyTTL)
+		ttl = defaultIdempotencyKeyTTL
+	}
+
 	return &RegistrationHandler{
-		services: services,
-		logger:   logger,
+		cfg:         cfg,
+		services:    services,
+		logger:      logger,
+		idempotency: newIdempotencyCache(ttl),
+		notifier:    notifier.New(cfg.Notifications, logger),
 	}
 }
 
 // CreateRegistration handles POST /api/v1/registrations
 func (h *RegistrationHandler) CreateRegistration(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.writeErrorResponse(w, "INVALID_REQUEST", "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
 	var req types.RegistrationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := json.Unmarshal(body, &req); err != nil {
 		h.writeErrorResponse(w, "INVALID_REQUEST", "Invalid JSON request body", http.StatusBadRequest)
 		return
 	}
@@ -50,9 +71,32 @@ func (h *RegistrationHandler) CreateRegistration(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// Reserve the key so retries replay the original response instead of registering twice
+	idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+	if idempotencyKey != "" {
+		cached, outcome := h.idempotency.reserve(userInfo.Username, idempotencyKey, hashRequestBody(body))
+		switch outcome {
+		case idempotencyReplay:
+			h.logger.WithField("user", userInfo.Username).Info("Replaying registration for idempotency key")
+			w.Header().Set("Idempotent-Replayed", "true")
+			h.writeCreatedRegistration(w, cached)
+			return
+		case idempotencyInFlight:
+			h.writeErrorResponse(w, "IDEMPOTENCY_KEY_IN_USE",
+				"A request with this idempotency key is still being processed", http.StatusConflict)
+			return
+		case idempotencyMismatch:
+			h.writeErrorResponse(w, "IDEMPOTENCY_KEY_REUSED",
+				"Idempotency key was already used with a different request body", http.StatusUnprocessableEntity)
+			return
+		}
+		// Failed requests give the key back so the client can retry with it
+		defer h.idempotency.release(userInfo.Username, idempotencyKey)
+	}
+
 	// Validate request
 	if validationErr := h.services.Registration.ValidateRegistration(r.Context(), &req); validationErr != nil {
-		h.writeErrorResponse(w, "INVALID_REQUEST", validationErr.Error(), http.StatusBadRequest)
+		h.writeError(w, validationErrorResponse(validationErr))
 		return
 	}
 
@@ -65,28 +109,39 @@ func (h *RegistrationHandler) CreateRegistration(w http.ResponseWriter, r *http.
 	h.logger.WithField("user", userInfo.Username).Info("Creating new registration")
 
 	// Create registration
-	registration, err := h.services.Registration.CreateRegistration(r.Context(), &req)
+	registration, err := h.services.Registration.CreateRegistration(r.Context(), &req, userInfo)
+	var duplicate *services.DuplicateRegistrationError
+	if errors.As(err, &duplicate) {
+		h.logger.WithField("namespace", req.Namespace).Info("Registration already exists for this repository, returning it")
+		if idempotencyKey != "" {
+			h.idempotency.complete(userInfo.Username, idempotencyKey, duplicate.Registration)
+		}
+		// Answered like an idempotent replay, so a retried create sees the same response either way
+		h.writeCreatedRegistration(w, duplicate.Registration)
+		return
+	}
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to create registration")
+		audit.Audit(r.Context(), audit.ActionRegistrationCreate, userInfo.Username, req.Namespace, audit.OutcomeFailure)
+		h.notifier.Notify(notifier.Event{
+			Type:      notifier.EventRegistrationFailed,
+			Namespace: req.Namespace,
+			Repo:      req.Repository.URL,
+			Status:    services.StatusFailed,
+		})
 
-		// Check for specific error types to return appropriate status codes
-		if isNamespaceConflictError(err) {
-			h.writeErrorResponse(w, "NAMESPACE_CONFLICT", err.Error(), http.StatusConflict)
-			return
-		}
-		if isRepositoryConflictError(err) {
-			h.writeErrorResponse(w, "REPOSITORY_CONFLICT", err.Error(), http.StatusConflict)
-			return
-		}
-
-		h.writeErrorResponse(w, "REGISTRATION_FAILED", "Failed to create registration", http.StatusInternalServerError)
+		setRetryAfter(w, err)
+		h.writeError(w, registrationErrorResponse(err))
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(registration); err != nil {
-		h.logger.WithError(err).Error("Failed to encode registration response")
+	if idempotencyKey != "" {
+		h.idempotency.complete(userInfo.Username, idempotencyKey, registration)
 	}
+	audit.Audit(r.Context(), audit.ActionRegistrationCreate, userInfo.Username, registration.Namespace, audit.OutcomeSuccess)
+	h.notifyRegistered(registration)
+
+	h.writeCreatedRegistration(w, registration)
 }
 
 // RegisterExistingNamespace handles POST /api/v1/registrations/existing
@@ -99,7 +154,7 @@ func (h *RegistrationHandler) RegisterExistingNamespace(w http.ResponseWriter, r
 
 	// Validate request
 	if err := h.services.Registration.ValidateExistingNamespaceRequest(r.Context(), &req); err != nil {
-		h.writeErrorResponse(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		h.writeError(w, validationErrorResponse(err))
 		return
 	}
 
@@ -118,6 +173,7 @@ func (h *RegistrationHandler) RegisterExistingNamespace(w http.ResponseWriter, r
 			"namespace": req.ExistingNamespace,
 			"error":     authErr,
 		}).Warn("Unauthorized namespace access attempt")
+		audit.Audit(r.Context(), audit.ActionNamespaceAccess, userInfo.Username, req.ExistingNamespace, audit.OutcomeDenied)
 		h.writeErrorResponse(w, "INSUFFICIENT_PERMISSIONS",
 			"Insufficient permissions for target namespace", http.StatusForbidden)
 		return
@@ -127,15 +183,41 @@ func (h *RegistrationHandler) RegisterExistingNamespace(w http.ResponseWriter, r
 	registration, err := h.services.Registration.RegisterExistingNamespace(r.Context(), &req, userInfo)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to register existing namespace")
+		audit.Audit(r.Context(), audit.ActionRegistrationCreate, userInfo.Username, req.ExistingNamespace, audit.OutcomeFailure)
+		h.notifier.Notify(notifier.Event{
+			Type:      notifier.EventRegistrationFailed,
+			Namespace: req.ExistingNamespace,
+			Repo:      req.Repository.URL,
+			Status:    services.StatusFailed,
+		})
+		if h.writeUpstreamTimeoutResponse(w, err) {
+			return
+		}
+		if h.writeRepositoryUnreachableResponse(w, err) {
+			return
+		}
+		var notManagedErr *services.NotManagedError
+		if errors.As(err, &notManagedErr) {
+			h.writeErrorResponseWithDetails(w, "NAMESPACE_NOT_MANAGED", "Namespace is not managed by this service",
+				http.StatusForbidden, map[string]interface{}{"namespace": notManagedErr.Namespace})
+			return
+		}
+		var appConflict *services.ApplicationConflictError
+		if errors.As(err, &appConflict) {
+			h.writeErrorResponseWithDetails(w, "APPLICATION_CONFLICT", err.Error(), http.StatusConflict,
+				map[string]interface{}{
+					"application": appConflict.Application,
+				})
+			return
+		}
 		h.writeErrorResponse(w, "REGISTRATION_FAILED",
 			"Failed to register existing namespace", http.StatusInternalServerError)
 		return
 	}
+	audit.Audit(r.Context(), audit.ActionRegistrationCreate, userInfo.Username, registration.Namespace, audit.OutcomeSuccess)
+	h.notifyRegistered(registration)
 
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(registration); err != nil {
-		h.logger.WithError(err).Error("Failed to encode registration response")
-	}
+	h.writeCreatedRegistration(w, registration)
 }
 
 // ListRegistrations handles GET /api/v1/registrations
@@ -145,10 +227,51 @@ func (h *RegistrationHandler) ListRegistrations(w http.ResponseWriter, r *http.R
 	if namespace := r.URL.Query().Get("namespace"); namespace != "" {
 		filters["namespace"] = namespace
 	}
+	if owner := r.URL.Query().Get("owner"); owner != "" {
+		filters["owner"] = owner
+	}
+	if team := r.URL.Query().Get("team"); team != "" {
+		if err := services.ValidateTeam(team); err != nil {
+			h.writeErrorResponse(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+			return
+		}
+		filters["team"] = team
+	}
+	if org := r.URL.Query().Get("org"); org != "" {
+		if err := services.ValidateRepositoryOrg(org); err != nil {
+			h.writeErrorResponse(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+			return
+		}
+		filters["org"] = org
+	}
+	if value := r.URL.Query().Get("includeArchived"); value != "" {
+		includeArchived, err := strconv.ParseBool(value)
+		if err != nil {
+			h.writeErrorResponse(w, "INVALID_REQUEST", "includeArchived must be true or false", http.StatusBadRequest)
+			return
+		}
+		if includeArchived {
+			filters["includeArchived"] = "true"
+		}
+	}
+	for param := range r.URL.Query() {
+		key, ok := strings.CutPrefix(param, services.MetadataFilterPrefix)
+		if !ok {
+			continue
+		}
+		if err := services.ValidateMetadataKey(key); err != nil {
+			h.writeErrorResponse(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+			return
+		}
+		filters[param] = r.URL.Query().Get(param)
+	}
 
 	registrations, err := h.services.Registration.ListRegistrations(r.Context(), filters)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to list registrations")
+		if h.writeUpstreamTimeoutResponse(w, err) {
+			return
+		}
 		h.writeErrorResponse(w, "LIST_FAILED", "Failed to list registrations", http.StatusInternalServerError)
 		return
 	}
@@ -169,10 +292,134 @@ func (h *RegistrationHandler) GetRegistration(w http.ResponseWriter, r *http.Req
 
 	registration, err := h.services.Registration.GetRegistration(r.Context(), id)
 	if err != nil {
-		h.writeErrorResponse(w, "NOT_FOUND", "Registration not found", http.StatusNotFound)
+		h.writeRegistrationLookupError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(registration); err != nil {
+		h.logger.WithError(err).Error("Failed to encode registration response")
+	}
+}
+
+// UpdateRegistration handles PATCH /api/v1/registrations/{id}. Only the owner or an admin may repoint a
+// registration.
+func (h *RegistrationHandler) UpdateRegistration(w http.ResponseWriter, r *http.Request) {
+	userInfo, err := h.extractUserInfo(r)
+	if err != nil {
+		h.writeErrorResponse(w, "AUTHENTICATION_REQUIRED", "Valid authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		h.writeErrorResponse(w, "INVALID_REQUEST", "Registration ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req types.RegistrationUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeErrorResponse(w, "INVALID_REQUEST", "Invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Branch == "" && req.Path == "" {
+		h.writeErrorResponse(w, "INVALID_REQUEST", "At least one of branch or path is required", http.StatusBadRequest)
+		return
+	}
+	if req.Branch != "" {
+		if err := services.ValidateRevision(req.Branch); err != nil {
+			h.writeErrorResponse(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := services.ValidateManifestPath(req.Path); err != nil {
+		h.writeErrorResponse(w, "INVALID_REQUEST", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	registration, err := h.services.Registration.GetRegistration(r.Context(), id)
+	if err != nil {
+		h.writeRegistrationLookupError(w, err)
+		return
+	}
+	if !h.requireOwnerOrAdmin(w, r, userInfo, registration, audit.ActionRegistrationUpdate) {
+		return
+	}
+
+	// Changing the repository would bypass repository conflict detection
+	if req.RepositoryURL != "" && req.RepositoryURL != registration.Repository.URL {
+		h.writeErrorResponse(w, "REPOSITORY_IMMUTABLE",
+			"Repository URL cannot be changed; create a new registration instead", http.StatusBadRequest)
+		return
+	}
+
+	// An ApplicationSet generates its Applications, so there is no single source to update
+	if h.cfg.Registration.UseApplicationSet {
+		h.writeErrorResponse(w, "UPDATE_UNSUPPORTED",
+			"Registrations deployed through an ApplicationSet cannot be updated", http.StatusConflict)
+		return
+	}
+
+	appName := registration.Status.ArgoCDApplication
+	if appName == "" {
+		// Without a namespace the Application name cannot be derived
+		if registration.Namespace == "" {
+			h.writeErrorResponse(w, "APPLICATION_NOT_FOUND",
+				"Registration has no ArgoCD Application to update", http.StatusNotFound)
+			return
+		}
+		appName = services.TenantApplicationName(h.cfg, registration.Namespace)
+	}
+
+	source := &types.ApplicationSource{
+		TargetRevision: req.Branch,
+		Path:           req.Path,
+	}
+	if err := h.services.ArgoCD.UpdateApplicationSource(r.Context(), appName, source); err != nil {
+		h.logger.WithError(err).WithField("registrationID", id).Error("Failed to update registration")
+		audit.Audit(r.Context(), audit.ActionRegistrationUpdate, userInfo.Username, registration.Namespace, audit.OutcomeFailure)
+		if h.writeUpstreamTimeoutResponse(w, err) {
+			return
+		}
+		var notFoundErr *services.ApplicationNotFoundError
+		if errors.As(err, &notFoundErr) {
+			h.writeErrorResponseWithDetails(w, "APPLICATION_NOT_FOUND", "ArgoCD Application for registration not found",
+				http.StatusNotFound, map[string]interface{}{"application": notFoundErr.Application})
+			return
+		}
+		h.writeErrorResponse(w, "UPDATE_FAILED", "Failed to update registration", http.StatusInternalServerError)
 		return
 	}
 
+	if req.Branch != "" {
+		// The namespace annotation is what GetRegistration reads the branch back from
+		if err := h.services.Kubernetes.UpdateNamespaceMetadata(r.Context(), registration.Namespace, nil,
+			map[string]string{"gitops.io/repository-branch": req.Branch}); err != nil {
+			h.logger.WithError(err).WithField("registrationID", id).Error("Failed to record updated branch")
+			audit.Audit(r.Context(), audit.ActionRegistrationUpdate, userInfo.Username, registration.Namespace, audit.OutcomeFailure)
+			if h.writeUpstreamTimeoutResponse(w, err) {
+				return
+			}
+			h.writeErrorResponse(w, "UPDATE_FAILED", "Failed to update registration", http.StatusInternalServerError)
+			return
+		}
+		registration.Repository.Branch = req.Branch
+
+		// The Application now follows the branch, so a pinned revision no longer applies
+		if registration.Repository.Revision != "" {
+			pinned := services.NamespaceMetadata{Annotations: map[string]string{services.RepositoryRevisionAnnotation: ""}}
+			if err := h.services.Kubernetes.UnclaimNamespace(r.Context(), registration.Namespace, pinned,
+				services.NamespaceMetadata{}); err != nil {
+				h.logger.WithError(err).WithField("registrationID", id).Warn("Failed to clear the pinned revision")
+			} else {
+				registration.Repository.Revision = ""
+			}
+		}
+	}
+	registration.UpdatedAt = time.Now()
+	audit.Audit(r.Context(), audit.ActionRegistrationUpdate, userInfo.Username, registration.Namespace, audit.OutcomeSuccess)
+
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(registration); err != nil {
 		h.logger.WithError(err).Error("Failed to encode registration response")
@@ -181,21 +428,229 @@ func (h *RegistrationHandler) GetRegistration(w http.ResponseWriter, r *http.Req
 
 // DeleteRegistration handles DELETE /api/v1/registrations/{id}
 func (h *RegistrationHandler) DeleteRegistration(w http.ResponseWriter, r *http.Request) {
+	userInfo, err := h.extractUserInfo(r)
+	if err != nil {
+		h.writeErrorResponse(w, "AUTHENTICATION_REQUIRED", "Valid authentication required", http.StatusUnauthorized)
+		return
+	}
+
 	id := chi.URLParam(r, "id")
 	if id == "" {
 		h.writeErrorResponse(w, "INVALID_REQUEST", "Registration ID required", http.StatusBadRequest)
 		return
 	}
 
+	// Look the registration up first so lifecycle events identify the tenant being removed
+	registration, err := h.services.Registration.GetRegistration(r.Context(), id)
+	if err != nil {
+		h.writeRegistrationLookupError(w, err)
+		return
+	}
+
+	h.deleteRegistration(w, r, userInfo, registration)
+}
+
+// DeleteRegistrationByNamespace handles DELETE /api/v1/registrations/by-namespace/{namespace}
+func (h *RegistrationHandler) DeleteRegistrationByNamespace(w http.ResponseWriter, r *http.Request) {
+	userInfo, err := h.extractUserInfo(r)
+	if err != nil {
+		h.writeErrorResponse(w, "AUTHENTICATION_REQUIRED", "Valid authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	if namespace == "" {
+		h.writeErrorResponse(w, "INVALID_REQUEST", "Namespace required", http.StatusBadRequest)
+		return
+	}
+
+	registrations, err := h.services.Registration.ListRegistrations(r.Context(),
+		map[string]string{"namespace": namespace, "includeArchived": "true"})
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to look up registration by namespace")
+		if h.writeUpstreamTimeoutResponse(w, err) {
+			return
+		}
+		h.writeErrorResponse(w, "LIST_FAILED", "Failed to look up registration", http.StatusInternalServerError)
+		return
+	}
+	if len(registrations) == 0 || registrations[0].ID == "" {
+		h.writeErrorResponseWithDetails(w, "NOT_FOUND", "No registration found for namespace",
+			http.StatusNotFound, map[string]interface{}{"namespace": namespace})
+		return
+	}
+
+	h.deleteRegistration(w, r, userInfo, registrations[0])
+}
+
+// deleteRegistration archives a registration that has already been looked up, or removes it when purge=true
+// is given for an archived registration, and reports the outcome. Only the owner or an admin may do either.
+func (h *RegistrationHandler) deleteRegistration(w http.ResponseWriter, r *http.Request, userInfo *types.UserInfo,
+	registration *types.Registration) {
+	id := registration.ID
+
+	purge := false
+	if value := r.URL.Query().Get("purge"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			h.writeErrorResponse(w, "INVALID_REQUEST", "purge must be true or false", http.StatusBadRequest)
+			return
+		}
+		purge = parsed
+	}
+	if !purge {
+		if h.requireOwnerOrAdmin(w, r, userInfo, registration, audit.ActionRegistrationArchive) {
+			h.archiveRegistration(w, r, userInfo, registration)
+		}
+		return
+	}
+	if !h.requireOwnerOrAdmin(w, r, userInfo, registration, audit.ActionRegistrationDelete) {
+		return
+	}
+	if registration.Status.Phase != services.StatusArchived {
+		h.writeErrorResponseWithDetails(w, "REGISTRATION_NOT_ARCHIVED",
+			"Registration must be archived before it is purged; delete it without purge=true first",
+			http.StatusConflict, map[string]interface{}{"phase": registration.Status.Phase})
+		return
+	}
+
+	// Require explicit confirmation when deregistering also deletes the namespace
+	if h.cfg.Registration.DeleteNamespaceOnDeregister {
+		// An unknown namespace can never be confirmed, so an empty value on either side is rejected
+		confirmNamespace := r.URL.Query().Get("confirmNamespace")
+		if registration.Namespace == "" || confirmNamespace != registration.Namespace {
+			h.writeErrorResponse(w, "CONFIRMATION_REQUIRED",
+				"Query parameter confirmNamespace must match the registration namespace", http.StatusBadRequest)
+			return
+		}
+	}
+
 	if err := h.services.Registration.DeleteRegistration(r.Context(), id); err != nil {
 		h.logger.WithError(err).Error("Failed to delete registration")
+		audit.Audit(r.Context(), audit.ActionRegistrationDelete, userInfo.Username, registration.Namespace, audit.OutcomeFailure)
+		h.notifier.Notify(notifier.Event{
+			Type:           notifier.EventDeletionFailed,
+			RegistrationID: id,
+			Namespace:      registration.Namespace,
+			Repo:           registration.Repository.URL,
+			Status:         services.StatusFailed,
+		})
+		if h.writeNotFoundResponse(w, err) || h.writeUpstreamTimeoutResponse(w, err) {
+			return
+		}
+		var notManagedErr *services.NotManagedError
+		if errors.As(err, &notManagedErr) {
+			h.writeErrorResponseWithDetails(w, "NAMESPACE_NOT_MANAGED", "Namespace is not managed by this service",
+				http.StatusForbidden, map[string]interface{}{"namespace": notManagedErr.Namespace})
+			return
+		}
 		h.writeErrorResponse(w, "DELETE_FAILED", "Failed to delete registration", http.StatusInternalServerError)
 		return
 	}
+	audit.Audit(r.Context(), audit.ActionRegistrationDelete, userInfo.Username, registration.Namespace, audit.OutcomeSuccess)
+	h.notifier.Notify(notifier.Event{
+		Type:           notifier.EventRegistrationDeleted,
+		RegistrationID: id,
+		Namespace:      registration.Namespace,
+		Repo:           registration.Repository.URL,
+		Status:         "deleted",
+	})
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// archiveRegistration suspends a registration's Application and marks it archived, so an accidental delete
+// can still be undone with UnarchiveRegistration before the registration is purged
+func (h *RegistrationHandler) archiveRegistration(w http.ResponseWriter, r *http.Request, userInfo *types.UserInfo,
+	registration *types.Registration) {
+	archived, err := h.services.Registration.ArchiveRegistration(r.Context(), registration.ID)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to archive registration")
+		audit.Audit(r.Context(), audit.ActionRegistrationArchive, userInfo.Username, registration.Namespace, audit.OutcomeFailure)
+		if h.writeNotFoundResponse(w, err) || h.writeUpstreamTimeoutResponse(w, err) {
+			return
+		}
+		var notManagedErr *services.NotManagedError
+		if errors.As(err, &notManagedErr) {
+			h.writeErrorResponseWithDetails(w, "NAMESPACE_NOT_MANAGED", "Namespace is not managed by this service",
+				http.StatusForbidden, map[string]interface{}{"namespace": notManagedErr.Namespace})
+			return
+		}
+		h.writeErrorResponse(w, "ARCHIVE_FAILED", "Failed to archive registration", http.StatusInternalServerError)
+		return
+	}
+	audit.Audit(r.Context(), audit.ActionRegistrationArchive, userInfo.Username, registration.Namespace, audit.OutcomeSuccess)
+	h.notifier.Notify(notifier.Event{
+		Type:           notifier.EventRegistrationArchived,
+		RegistrationID: archived.ID,
+		Namespace:      archived.Namespace,
+		Repo:           archived.Repository.URL,
+		Status:         services.StatusArchived,
+	})
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(archived); err != nil {
+		h.logger.WithError(err).Error("Failed to encode archived registration response")
+	}
+}
+
+// UnarchiveRegistration handles POST /api/v1/registrations/{id}/unarchive. Only the owner or an admin may
+// restore an archived registration.
+func (h *RegistrationHandler) UnarchiveRegistration(w http.ResponseWriter, r *http.Request) {
+	userInfo, err := h.extractUserInfo(r)
+	if err != nil {
+		h.writeErrorResponse(w, "AUTHENTICATION_REQUIRED", "Valid authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		h.writeErrorResponse(w, "INVALID_REQUEST", "Registration ID required", http.StatusBadRequest)
+		return
+	}
+
+	registration, err := h.services.Registration.GetRegistration(r.Context(), id)
+	if err != nil {
+		h.writeRegistrationLookupError(w, err)
+		return
+	}
+	if !h.requireOwnerOrAdmin(w, r, userInfo, registration, audit.ActionRegistrationUnarchive) {
+		return
+	}
+
+	unarchived, err := h.services.Registration.UnarchiveRegistration(r.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to unarchive registration")
+		audit.Audit(r.Context(), audit.ActionRegistrationUnarchive, userInfo.Username, registration.Namespace, audit.OutcomeFailure)
+		if h.writeNotFoundResponse(w, err) || h.writeUpstreamTimeoutResponse(w, err) {
+			return
+		}
+		var notManagedErr *services.NotManagedError
+		if errors.As(err, &notManagedErr) {
+			h.writeErrorResponseWithDetails(w, "NAMESPACE_NOT_MANAGED", "Namespace is not managed by this service",
+				http.StatusForbidden, map[string]interface{}{"namespace": notManagedErr.Namespace})
+			return
+		}
+		h.writeErrorResponse(w, "UNARCHIVE_FAILED", "Failed to unarchive registration", http.StatusInternalServerError)
+		return
+	}
+	audit.Audit(r.Context(), audit.ActionRegistrationUnarchive, userInfo.Username, registration.Namespace, audit.OutcomeSuccess)
+	if registration.Status.Phase == services.StatusArchived {
+		h.notifier.Notify(notifier.Event{
+			Type:           notifier.EventRegistrationUnarchived,
+			RegistrationID: unarchived.ID,
+			Namespace:      unarchived.Namespace,
+			Repo:           unarchived.Repository.URL,
+			Status:         unarchived.Status.Phase,
+		})
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(unarchived); err != nil {
+		h.logger.WithError(err).Error("Failed to encode unarchived registration response")
+	}
+}
+
 // GetRegistrationStatus handles GET /api/v1/registrations/{id}/status
 func (h *RegistrationHandler) GetRegistrationStatus(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -206,7 +661,7 @@ func (h *RegistrationHandler) GetRegistrationStatus(w http.ResponseWriter, r *ht
 
 	registration, err := h.services.Registration.GetRegistration(r.Context(), id)
 	if err != nil {
-		h.writeErrorResponse(w, "NOT_FOUND", "Registration not found", http.StatusNotFound)
+		h.writeRegistrationLookupError(w, err)
 		return
 	}
 
@@ -216,55 +671,839 @@ func (h *RegistrationHandler) GetRegistrationStatus(w http.ResponseWriter, r *ht
 	}
 }
 
-// SyncRegistration handles POST /api/v1/registrations/{id}/sync
-func (h *RegistrationHandler) SyncRegistration(w http.ResponseWriter, r *http.Request) {
+// GetRegistrationResources handles GET /api/v1/registrations/{id}/resources
+func (h *RegistrationHandler) GetRegistrationResources(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
 		h.writeErrorResponse(w, "INVALID_REQUEST", "Registration ID required", http.StatusBadRequest)
 		return
 	}
 
-	// TODO: Implement sync trigger logic
-	h.logger.WithField("id", id).Info("Sync triggered for registration (stub)")
+	registration, err := h.services.Registration.GetRegistration(r.Context(), id)
+	if err != nil {
+		h.writeRegistrationLookupError(w, err)
+		return
+	}
 
-	response := map[string]interface{}{
-		"message": "Sync triggered successfully",
-		"id":      id,
+	application := registration.Status.ArgoCDApplication
+	if application == "" {
+		application = services.TenantApplicationName(h.cfg, registration.Namespace)
+	}
+
+	resources, err := h.services.ArgoCD.GetApplicationResources(r.Context(), application)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			h.writeErrorResponse(w, "NOT_FOUND", "Application not found for registration", http.StatusNotFound)
+			return
+		}
+		h.logger.WithError(err).WithField("application", application).Error("Failed to get application resources")
+		if h.writeUpstreamTimeoutResponse(w, err) {
+			return
+		}
+		h.writeErrorResponse(w, "LIST_FAILED", "Failed to list application resources", http.StatusInternalServerError)
+		return
 	}
 
 	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.WithError(err).Error("Failed to encode sync response")
+	if err := json.NewEncoder(w).Encode(resources); err != nil {
+		h.logger.WithError(err).Error("Failed to encode application resources response")
 	}
 }
 
-// Helper methods
+// GetRegistrationHistory handles GET /api/v1/registrations/{id}/history, returning the Application's recorded
+// syncs newest first. ?limit= returns only the most recent entries.
+func (h *RegistrationHandler) GetRegistrationHistory(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		h.writeErrorResponse(w, "INVALID_REQUEST", "Registration ID required", http.StatusBadRequest)
+		return
+	}
 
-// extractUserInfo extracts user information from request context/headers
-func (h *RegistrationHandler) extractUserInfo(r *http.Request) (*types.UserInfo, error) {
-	// Extract Authorization header
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		return nil, http.ErrNoCookie
+	limit := 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			h.writeErrorResponse(w, "INVALID_REQUEST", "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
 	}
 
-	// Extract Bearer token
-	token := strings.TrimPrefix(authHeader, "Bearer ")
-	if token == authHeader {
-		return nil, http.ErrNoCookie
+	registration, err := h.services.Registration.GetRegistration(r.Context(), id)
+	if err != nil {
+		h.writeRegistrationLookupError(w, err)
+		return
 	}
 
-	return h.services.Authorization.ExtractUserInfo(r.Context(), token)
+	application := registration.Status.ArgoCDApplication
+	if application == "" {
+		application = services.TenantApplicationName(h.cfg, registration.Namespace)
+	}
+
+	history, err := h.services.ArgoCD.GetApplicationHistory(r.Context(), application)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			h.writeErrorResponse(w, "NOT_FOUND", "Application not found for registration", http.StatusNotFound)
+			return
+		}
+		h.logger.WithError(err).WithField("application", application).Error("Failed to get application history")
+		if h.writeUpstreamTimeoutResponse(w, err) {
+			return
+		}
+		h.writeErrorResponse(w, "LIST_FAILED", "Failed to list application history", http.StatusInternalServerError)
+		return
+	}
+	if limit > 0 && len(history) > limit {
+		history = history[:limit]
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(history); err != nil {
+		h.logger.WithError(err).Error("Failed to encode application history response")
+	}
 }
 
-// writeErrorResponse writes a standardized error response
-func (h *RegistrationHandler) writeErrorResponse(w http.ResponseWriter, errorCode, message string, statusCode int) {
-	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(types.ErrorResponse{
+// GetRegistrationEvents handles GET /api/v1/registrations/{id}/events
+func (h *RegistrationHandler) GetRegistrationEvents(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		h.writeErrorResponse(w, "INVALID_REQUEST", "Registration ID required", http.StatusBadRequest)
+		return
+	}
+
+	registration, err := h.services.Registration.GetRegistration(r.Context(), id)
+	if err != nil {
+		h.writeRegistrationLookupError(w, err)
+		return
+	}
+
+	events, err := h.services.Kubernetes.ListEvents(r.Context(), registration.Namespace)
+	if err != nil {
+		h.logger.WithError(err).WithField("namespace", registration.Namespace).Error("Failed to list registration events")
+		if h.writeUpstreamTimeoutResponse(w, err) {
+			return
+		}
+		h.writeErrorResponse(w, "LIST_FAILED", "Failed to list registration events", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(events); err != nil {
+		h.logger.WithError(err).Error("Failed to encode registration events response")
+	}
+}
+
+// SyncRegistration handles POST /api/v1/registrations/{id}/sync
+func (h *RegistrationHandler) SyncRegistration(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		h.writeErrorResponse(w, "INVALID_REQUEST", "Registration ID required", http.StatusBadRequest)
+		return
+	}
+
+	// TODO: Implement sync trigger logic
+	h.logger.WithField("id", id).Info("Sync triggered for registration (stub)")
+
+	response := map[string]interface{}{
+		"message": "Sync triggered successfully",
+		"id":      id,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.WithError(err).Error("Failed to encode sync response")
+	}
+}
+
+// RefreshRegistration handles POST /api/v1/registrations/{id}/refresh, asking ArgoCD to re-read the
+// registration's repository without syncing. The refresh is hard unless ?hard=false is given.
+func (h *RegistrationHandler) RefreshRegistration(w http.ResponseWriter, r *http.Request) {
+	userInfo, err := h.extractUserInfo(r)
+	if err != nil {
+		h.writeErrorResponse(w, "AUTHENTICATION_REQUIRED", "Valid authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		h.writeErrorResponse(w, "INVALID_REQUEST", "Registration ID required", http.StatusBadRequest)
+		return
+	}
+
+	hard := true
+	if value := r.URL.Query().Get("hard"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			h.writeErrorResponse(w, "INVALID_REQUEST", "hard must be true or false", http.StatusBadRequest)
+			return
+		}
+		hard = parsed
+	}
+
+	registration, err := h.services.Registration.GetRegistration(r.Context(), id)
+	if err != nil {
+		h.writeRegistrationLookupError(w, err)
+		return
+	}
+	if !h.requireOwnerOrAdmin(w, r, userInfo, registration, audit.ActionRegistrationRefresh) {
+		return
+	}
+
+	application := registration.Status.ArgoCDApplication
+	if application == "" {
+		application = services.TenantApplicationName(h.cfg, registration.Namespace)
+	}
+
+	if err := h.services.ArgoCD.RefreshApplication(r.Context(), application, hard); err != nil {
+		h.logger.WithError(err).WithField("application", application).Error("Failed to refresh application")
+		if h.writeUpstreamTimeoutResponse(w, err) {
+			return
+		}
+		var notFoundErr *services.ApplicationNotFoundError
+		if errors.As(err, &notFoundErr) {
+			h.writeErrorResponseWithDetails(w, "APPLICATION_NOT_FOUND", "ArgoCD Application for registration not found",
+				http.StatusNotFound, map[string]interface{}{"application": notFoundErr.Application})
+			return
+		}
+		h.writeErrorResponse(w, "REFRESH_FAILED", "Failed to refresh registration", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"message":     "Refresh requested",
+		"id":          id,
+		"application": application,
+		"hard":        hard,
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.WithError(err).Error("Failed to encode refresh response")
+	}
+}
+
+// ReauthorizeRegistration handles POST /api/v1/registrations/{id}/reauthorize
+func (h *RegistrationHandler) ReauthorizeRegistration(w http.ResponseWriter, r *http.Request) {
+	userInfo, err := h.extractUserInfo(r)
+	if err != nil {
+		h.writeErrorResponse(w, "AUTHENTICATION_REQUIRED", "Valid authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if !h.services.Authorization.IsAdminUser(userInfo) {
+		h.logger.WithField("user", userInfo.Username).Warn("Non-admin user attempted to reauthorize a registration")
+		audit.Audit(r.Context(), audit.ActionRegistrationReauth, userInfo.Username, id, audit.OutcomeDenied)
+		h.writeErrorResponse(w, "FORBIDDEN", "Admin privileges required", http.StatusForbidden)
+		return
+	}
+
+	if id == "" {
+		h.writeErrorResponse(w, "INVALID_REQUEST", "Registration ID required", http.StatusBadRequest)
+		return
+	}
+
+	registration, err := h.services.Registration.GetRegistration(r.Context(), id)
+	if err != nil {
+		h.writeRegistrationLookupError(w, err)
+		return
+	}
+	if registration.Owner == "" {
+		h.writeErrorResponseWithDetails(w, "OWNER_UNKNOWN", "Registration has no recorded owner",
+			http.StatusUnprocessableEntity, map[string]interface{}{"namespace": registration.Namespace})
+		return
+	}
+
+	result := &types.ReauthorizationResult{
+		RegistrationID: registration.ID,
+		Namespace:      registration.Namespace,
+		Owner:          registration.Owner,
+		Allowed:        true,
+		CheckedAt:      time.Now(),
+	}
+
+	// The check answers for the recorded owner, not the admin calling the endpoint
+	owner := &types.UserInfo{Username: registration.Owner}
+	if authErr := h.services.Authorization.ValidateNamespaceAccess(r.Context(), owner, registration.Namespace); authErr != nil {
+		if h.writeUpstreamTimeoutResponse(w, authErr) {
+			return
+		}
+		result.Allowed = false
+		result.Reason = authErr.Error()
+	}
+
+	outcome := audit.OutcomeSuccess
+	if !result.Allowed {
+		outcome = audit.OutcomeDenied
+	}
+	h.logger.WithFields(logrus.Fields{
+		"user":      userInfo.Username,
+		"owner":     registration.Owner,
+		"namespace": registration.Namespace,
+		"allowed":   result.Allowed,
+	}).Info("Re-checked namespace access for registration owner")
+	audit.Audit(r.Context(), audit.ActionRegistrationReauth, userInfo.Username, registration.Namespace, outcome)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.WithError(err).Error("Failed to encode reauthorization response")
+	}
+}
+
+// ListTenants handles GET /api/v1/tenants
+func (h *RegistrationHandler) ListTenants(w http.ResponseWriter, r *http.Request) {
+	userInfo, err := h.extractUserInfo(r)
+	if err != nil {
+		h.writeErrorResponse(w, "AUTHENTICATION_REQUIRED", "Valid authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	if !h.services.Authorization.IsAdminUser(userInfo) {
+		h.logger.WithField("user", userInfo.Username).Warn("Non-admin user attempted to list tenants")
+		audit.Audit(r.Context(), audit.ActionTenantsList, userInfo.Username, "", audit.OutcomeDenied)
+		h.writeErrorResponse(w, "FORBIDDEN", "Admin privileges required", http.StatusForbidden)
+		return
+	}
+
+	audit.Audit(r.Context(), audit.ActionTenantsList, userInfo.Username, "", audit.OutcomeSuccess)
+	healthFilter := r.URL.Query().Get("health")
+
+	namespaces, err := h.services.Kubernetes.ListManagedNamespaces(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list managed namespaces")
+		if h.writeUpstreamTimeoutResponse(w, err) {
+			return
+		}
+		h.writeErrorResponse(w, "LIST_FAILED", "Failed to list tenants", http.StatusInternalServerError)
+		return
+	}
+
+	statuses, err := h.services.ArgoCD.ListApplicationStatuses(r.Context())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list application statuses")
+		if h.writeUpstreamTimeoutResponse(w, err) {
+			return
+		}
+		h.writeErrorResponse(w, "LIST_FAILED", "Failed to list tenants", http.StatusInternalServerError)
+		return
+	}
+
+	tenants := make([]*types.TenantStatus, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		tenant := buildTenantStatus(namespace, services.TenantApplicationName(h.cfg, namespace), statuses)
+		if healthFilter != "" && tenant.Health != healthFilter {
+			continue
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(tenants); err != nil {
+		h.logger.WithError(err).Error("Failed to encode tenants response")
+	}
+}
+
+// GetConfig handles GET /api/v1/config
+func (h *RegistrationHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	userInfo, err := h.extractUserInfo(r)
+	if err != nil {
+		h.writeErrorResponse(w, "AUTHENTICATION_REQUIRED", "Valid authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	if !h.services.Authorization.IsAdminUser(userInfo) {
+		h.logger.WithField("user", userInfo.Username).Warn("Non-admin user attempted to read configuration")
+		audit.Audit(r.Context(), audit.ActionConfigRead, userInfo.Username, "", audit.OutcomeDenied)
+		h.writeErrorResponse(w, "FORBIDDEN", "Admin privileges required", http.StatusForbidden)
+		return
+	}
+
+	audit.Audit(r.Context(), audit.ActionConfigRead, userInfo.Username, "", audit.OutcomeSuccess)
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(h.cfg.Redacted()); err != nil {
+		h.logger.WithError(err).Error("Failed to encode config response")
+	}
+}
+
+// DeleteAppProjectByRepoHash handles DELETE /api/v1/appprojects?repoHash=<hash>
+func (h *RegistrationHandler) DeleteAppProjectByRepoHash(w http.ResponseWriter, r *http.Request) {
+	userInfo, err := h.extractUserInfo(r)
+	if err != nil {
+		h.writeErrorResponse(w, "AUTHENTICATION_REQUIRED", "Valid authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	if !h.services.Authorization.IsAdminUser(userInfo) {
+		h.logger.WithField("user", userInfo.Username).Warn("Non-admin user attempted to delete an AppProject")
+		audit.Audit(r.Context(), audit.ActionAppProjectDelete, userInfo.Username, "", audit.OutcomeDenied)
+		h.writeErrorResponse(w, "FORBIDDEN", "Admin privileges required", http.StatusForbidden)
+		return
+	}
+
+	repoHash := r.URL.Query().Get("repoHash")
+	if repoHash == "" {
+		h.writeErrorResponse(w, "INVALID_REQUEST", "Query parameter repoHash is required", http.StatusBadRequest)
+		return
+	}
+
+	project, err := h.services.ArgoCD.DeleteAppProjectByRepoHash(r.Context(), repoHash)
+	if err != nil {
+		h.logger.WithError(err).WithField("repoHash", repoHash).Error("Failed to delete AppProject")
+		audit.Audit(r.Context(), audit.ActionAppProjectDelete, userInfo.Username, repoHash, audit.OutcomeFailure)
+		if h.writeUpstreamTimeoutResponse(w, err) {
+			return
+		}
+		h.writeErrorResponse(w, "DELETE_FAILED", "Failed to delete AppProject", http.StatusInternalServerError)
+		return
+	}
+	if project == nil {
+		h.writeErrorResponse(w, "NOT_FOUND", "No AppProject found for repository hash", http.StatusNotFound)
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"user":       userInfo.Username,
+		"appProject": project.Name,
+		"repoHash":   repoHash,
+	}).Info("Force-deleted AppProject")
+	audit.Audit(r.Context(), audit.ActionAppProjectDelete, userInfo.Username, project.Name, audit.OutcomeSuccess)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(project); err != nil {
+		h.logger.WithError(err).Error("Failed to encode AppProject response")
+	}
+}
+
+// BackfillRepoHashLabels handles POST /api/v1/appprojects/backfill-repo-hash-labels
+func (h *RegistrationHandler) BackfillRepoHashLabels(w http.ResponseWriter, r *http.Request) {
+	userInfo, err := h.extractUserInfo(r)
+	if err != nil {
+		h.writeErrorResponse(w, "AUTHENTICATION_REQUIRED", "Valid authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	if !h.services.Authorization.IsAdminUser(userInfo) {
+		h.logger.WithField("user", userInfo.Username).Warn("Non-admin user attempted to backfill AppProject labels")
+		audit.Audit(r.Context(), audit.ActionAppProjectBackfill, userInfo.Username, "", audit.OutcomeDenied)
+		h.writeErrorResponse(w, "FORBIDDEN", "Admin privileges required", http.StatusForbidden)
+		return
+	}
+
+	updated, err := services.BackfillRepoHashLabels(r.Context(), h.services.ArgoCD, h.cfg, h.logger)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to backfill repository hash labels")
+		audit.Audit(r.Context(), audit.ActionAppProjectBackfill, userInfo.Username, "", audit.OutcomeFailure)
+		if h.writeUpstreamTimeoutResponse(w, err) {
+			return
+		}
+		h.writeErrorResponseWithDetails(w, "BACKFILL_FAILED", "Failed to label some AppProjects",
+			http.StatusInternalServerError, map[string]interface{}{"updated": updated})
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"user":    userInfo.Username,
+		"updated": updated,
+	}).Info("Backfilled repository hash labels")
+	audit.Audit(r.Context(), audit.ActionAppProjectBackfill, userInfo.Username, "", audit.OutcomeSuccess)
+
+	response := map[string]interface{}{
+		"updated": updated,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.WithError(err).Error("Failed to encode backfill response")
+	}
+}
+
+// ListRepositoryHashCollisions handles GET /api/v1/diagnostics/hash-collisions
+func (h *RegistrationHandler) ListRepositoryHashCollisions(w http.ResponseWriter, r *http.Request) {
+	userInfo, err := h.extractUserInfo(r)
+	if err != nil {
+		h.writeErrorResponse(w, "AUTHENTICATION_REQUIRED", "Valid authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	if !h.services.Authorization.IsAdminUser(userInfo) {
+		h.logger.WithField("user", userInfo.Username).Warn("Non-admin user attempted to list repository hash collisions")
+		audit.Audit(r.Context(), audit.ActionDiagnosticsRead, userInfo.Username, "", audit.OutcomeDenied)
+		h.writeErrorResponse(w, "FORBIDDEN", "Admin privileges required", http.StatusForbidden)
+		return
+	}
+
+	collisions, err := services.FindRepositoryHashCollisions(r.Context(), h.services.ArgoCD, h.cfg)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list repository hash collisions")
+		if h.writeUpstreamTimeoutResponse(w, err) {
+			return
+		}
+		h.writeErrorResponse(w, "DIAGNOSTICS_FAILED", "Failed to list repository hash collisions",
+			http.StatusInternalServerError)
+		return
+	}
+
+	audit.Audit(r.Context(), audit.ActionDiagnosticsRead, userInfo.Username, "", audit.OutcomeSuccess)
+	response := map[string]interface{}{
+		"collisions": collisions,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.WithError(err).Error("Failed to encode hash collisions response")
+	}
+}
+
+// Helper methods
+
+// buildTenantStatus joins a managed namespace with the status of its ArgoCD Application
+func buildTenantStatus(namespace, application string, statuses map[string]*types.ApplicationStatus) *types.TenantStatus {
+	tenant := &types.TenantStatus{
+		Namespace:   namespace,
+		Application: application,
+		Health:      "Unknown",
+		Sync:        "Unknown",
+	}
+
+	status, ok := statuses[tenant.Application]
+	if !ok {
+		tenant.Message = fmt.Sprintf("application %s not found", tenant.Application)
+		return tenant
+	}
+
+	tenant.Health = status.Health
+	tenant.Sync = status.Sync
+	tenant.Message = status.Message
+	return tenant
+}
+
+// writeCreatedRegistration responds 201 with a registration, pointing Location and selfLink at it
+func (h *RegistrationHandler) writeCreatedRegistration(w http.ResponseWriter, registration *types.Registration) {
+	registration.SelfLink = registrationPath(registration.ID)
+	w.Header().Set("Location", registration.SelfLink)
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(registration); err != nil {
+		h.logger.WithError(err).Error("Failed to encode registration response")
+	}
+}
+
+// registrationPath returns the API path of a registration
+func registrationPath(id string) string {
+	return "/api/v1/registrations/" + url.PathEscape(id)
+}
+
+// notifyRegistered publishes a success event for a completed registration
+func (h *RegistrationHandler) notifyRegistered(registration *types.Registration) {
+	h.notifier.Notify(notifier.Event{
+		Type:           notifier.EventRegistrationSucceeded,
+		RegistrationID: registration.ID,
+		Namespace:      registration.Namespace,
+		Repo:           registration.Repository.URL,
+		Status:         registration.Status.Phase,
+	})
+}
+
+// requireOwnerOrAdmin reports whether userInfo may change registration: only its recorded owner and admins
+// may. Otherwise the denial is audited under action and a 403 is written.
+func (h *RegistrationHandler) requireOwnerOrAdmin(w http.ResponseWriter, r *http.Request, userInfo *types.UserInfo,
+	registration *types.Registration, action string) bool {
+	if (registration.Owner != "" && registration.Owner == userInfo.Username) || h.services.Authorization.IsAdminUser(userInfo) {
+		return true
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"user":      userInfo.Username,
+		"owner":     registration.Owner,
+		"namespace": registration.Namespace,
+	}).Warn("User attempted to change a registration they do not own")
+	audit.Audit(r.Context(), action, userInfo.Username, registration.Namespace, audit.OutcomeDenied)
+	h.writeErrorResponse(w, "FORBIDDEN", "Only the registration owner or an admin can change it", http.StatusForbidden)
+	return false
+}
+
+// extractUserInfo extracts user information from request context/headers
+func (h *RegistrationHandler) extractUserInfo(r *http.Request) (*types.UserInfo, error) {
+	var userInfo *types.UserInfo
+	var err error
+
+	// Extract Authorization header
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		// Fall back to a verified client certificate when mTLS is enabled
+		cert := h.verifiedClientCertificate(r)
+		if cert == nil {
+			return nil, http.ErrNoCookie
+		}
+		userInfo, err = h.services.Authorization.ExtractUserInfoFromCertificate(r.Context(), cert)
+	} else {
+		// Extract Bearer token
+		token := strings.TrimPrefix(authHeader, "Bearer ")
+		if token == authHeader {
+			return nil, http.ErrNoCookie
+		}
+		userInfo, err = h.services.Authorization.ExtractUserInfo(r.Context(), token)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	recordRequestUser(r.Context(), userInfo.Username)
+	return userInfo, nil
+}
+
+// verifiedClientCertificate returns the client certificate verified during the TLS handshake,
+// or nil if client certificate authentication is disabled or none was presented
+func (h *RegistrationHandler) verifiedClientCertificate(r *http.Request) *x509.Certificate {
+	if !h.cfg.Server.TLS.ClientAuth || r.TLS == nil {
+		return nil
+	}
+	if len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return r.TLS.VerifiedChains[0][0]
+}
+
+type requestUserKey struct{}
+
+// WithRequestUser returns a context in which handlers record the authenticated user,
+// along with a function reporting the recorded username once the request completes
+func WithRequestUser(ctx context.Context) (context.Context, func() string) {
+	username := new(string)
+	return context.WithValue(ctx, requestUserKey{}, username), func() string { return *username }
+}
+
+// recordRequestUser stores the username in a context prepared by WithRequestUser
+func recordRequestUser(ctx context.Context, username string) {
+	if recorded, ok := ctx.Value(requestUserKey{}).(*string); ok {
+		*recorded = username
+	}
+}
+
+// writeRegistrationLookupError writes the response for a failed registration lookup:
+// 404 for an unknown ID, 504 for a timed out upstream call and 500 otherwise
+func (h *RegistrationHandler) writeRegistrationLookupError(w http.ResponseWriter, err error) {
+	if h.writeNotFoundResponse(w, err) || h.writeUpstreamTimeoutResponse(w, err) {
+		return
+	}
+	h.logger.WithError(err).Error("Failed to look up registration")
+	h.writeErrorResponse(w, "LOOKUP_FAILED", "Failed to look up registration", http.StatusInternalServerError)
+}
+
+// writeNotFoundResponse writes a 404 naming the missing resource when err is a NotFoundError
+func (h *RegistrationHandler) writeNotFoundResponse(w http.ResponseWriter, err error) bool {
+	var notFound *services.NotFoundError
+	if !errors.As(err, &notFound) {
+		return false
+	}
+
+	h.writeErrorResponseWithDetails(w, "NOT_FOUND", notFound.Error(), http.StatusNotFound,
+		map[string]interface{}{
+			"resource": notFound.Resource,
+			"id":       notFound.ID,
+		})
+	return true
+}
+
+// validationErrorResponse maps a rejected registration request to the error response returned to the client
+func validationErrorResponse(err error) *types.ErrorResponse {
+	var restrictionErr *config.ResourceRestrictionError
+	if errors.As(err, &restrictionErr) {
+		return newErrorResponse("INVALID_RESOURCE_RESTRICTIONS", err.Error(), http.StatusUnprocessableEntity,
+			map[string]interface{}{
+				"field": restrictionErr.Field,
+				"index": restrictionErr.Index,
+			})
+	}
+	var forbiddenErr *services.NamespaceForbiddenError
+	if errors.As(err, &forbiddenErr) {
+		return newErrorResponse("NAMESPACE_FORBIDDEN", err.Error(), http.StatusForbidden,
+			map[string]interface{}{
+				"namespace": forbiddenErr.Namespace,
+				"pattern":   forbiddenErr.Pattern,
+			})
+	}
+	var urlErr *services.InvalidRepositoryURLError
+	if errors.As(err, &urlErr) {
+		return newErrorResponse("INVALID_REPOSITORY_URL", err.Error(), http.StatusBadRequest,
+			map[string]interface{}{
+				"url": urlErr.URL,
+			})
+	}
+	var annotationNotAllowed *services.AnnotationNotAllowedError
+	if errors.As(err, &annotationNotAllowed) {
+		return newErrorResponse("ANNOTATION_NOT_ALLOWED", err.Error(), http.StatusUnprocessableEntity,
+			map[string]interface{}{
+				"annotation": annotationNotAllowed.Key,
+			})
+	}
+	return newErrorResponse("INVALID_REQUEST", err.Error(), http.StatusBadRequest, nil)
+}
+
+// registrationErrorResponse maps a failed registration to the error response returned to the client
+func registrationErrorResponse(err error) *types.ErrorResponse {
+	// Check for specific error types to return appropriate status codes
+	if resp := upstreamTimeoutResponse(err); resp != nil {
+		return resp
+	}
+	var roleNotFound *services.ClusterRoleNotFoundError
+	if errors.As(err, &roleNotFound) {
+		return newErrorResponse("CLUSTER_ROLE_NOT_FOUND", err.Error(), http.StatusUnprocessableEntity,
+			map[string]interface{}{
+				"clusterRole": roleNotFound.ClusterRole,
+			})
+	}
+	var roleTooPrivileged *services.ClusterRoleTooPrivilegedError
+	if errors.As(err, &roleTooPrivileged) {
+		return newErrorResponse("CLUSTER_ROLE_TOO_PRIVILEGED", err.Error(), http.StatusUnprocessableEntity,
+			map[string]interface{}{
+				"clusterRole": roleTooPrivileged.ClusterRole,
+			})
+	}
+	var serviceAccountNotFound *services.ServiceAccountNotFoundError
+	if errors.As(err, &serviceAccountNotFound) {
+		return newErrorResponse("SERVICE_ACCOUNT_NOT_FOUND", err.Error(), http.StatusUnprocessableEntity,
+			map[string]interface{}{
+				"namespace":      serviceAccountNotFound.Namespace,
+				"serviceAccount": serviceAccountNotFound.ServiceAccount,
+			})
+	}
+	if resp := repositoryUnreachableResponse(err); resp != nil {
+		return resp
+	}
+	var quotaExceeded *services.UserQuotaExceededError
+	if errors.As(err, &quotaExceeded) {
+		return newErrorResponse("USER_QUOTA_EXCEEDED", err.Error(), http.StatusTooManyRequests,
+			map[string]interface{}{
+				"limit": quotaExceeded.Limit,
+			})
+	}
+	var clusterNotFound *services.DestinationClusterNotFoundError
+	if errors.As(err, &clusterNotFound) {
+		return newErrorResponse("DESTINATION_CLUSTER_NOT_FOUND", err.Error(), http.StatusUnprocessableEntity,
+			map[string]interface{}{
+				"destinationCluster": clusterNotFound.Cluster,
+			})
+	}
+	var appConflict *services.ApplicationConflictError
+	if errors.As(err, &appConflict) {
+		return newErrorResponse("APPLICATION_CONFLICT", err.Error(), http.StatusConflict,
+			map[string]interface{}{
+				"application": appConflict.Application,
+			})
+	}
+	var namespaceTerminating *services.NamespaceTerminatingError
+	if errors.As(err, &namespaceTerminating) {
+		return newErrorResponse("NAMESPACE_TERMINATING", err.Error(), http.StatusConflict,
+			map[string]interface{}{
+				"namespace":         namespaceTerminating.Namespace,
+				"retryAfterSeconds": retryAfterSeconds(namespaceTerminating.RetryAfter),
+			})
+	}
+	var namespaceConflict *services.NamespaceConflictError
+	if errors.As(err, &namespaceConflict) {
+		return newErrorResponse("NAMESPACE_CONFLICT", err.Error(), http.StatusConflict, nil)
+	}
+	var repoConflict *services.RepositoryConflictError
+	if errors.As(err, &repoConflict) {
+		return newErrorResponse("REPOSITORY_CONFLICT", err.Error(), http.StatusConflict,
+			map[string]interface{}{
+				"repository":           repoConflict.RepoURL,
+				"conflictingNamespace": repoConflict.ConflictingNamespace,
+				"appProject":           repoConflict.AppProject,
+			})
+	}
+
+	return newErrorResponse("REGISTRATION_FAILED", "Failed to create registration", http.StatusInternalServerError, nil)
+}
+
+// setRetryAfter sets the Retry-After header when err tells the client when a retry can succeed
+func setRetryAfter(w http.ResponseWriter, err error) {
+	var namespaceTerminating *services.NamespaceTerminatingError
+	if errors.As(err, &namespaceTerminating) {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(namespaceTerminating.RetryAfter)))
+	}
+}
+
+// retryAfterSeconds rounds a retry delay up to whole seconds, as Retry-After requires
+func retryAfterSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// upstreamTimeoutResponse builds a 504 when err was caused by a Kubernetes or ArgoCD call timing out, or returns nil
+func upstreamTimeoutResponse(err error) *types.ErrorResponse {
+	var timeoutErr *services.UpstreamTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		return nil
+	}
+
+	return newErrorResponse("UPSTREAM_TIMEOUT", timeoutErr.Error(), http.StatusGatewayTimeout,
+		map[string]interface{}{
+			"service":   timeoutErr.Service,
+			"operation": timeoutErr.Operation,
+		})
+}
+
+// repositoryUnreachableResponse builds a 422 when ArgoCD could not reach the repository being registered, or returns nil
+func repositoryUnreachableResponse(err error) *types.ErrorResponse {
+	var unreachable *services.RepositoryUnreachableError
+	if !errors.As(err, &unreachable) {
+		return nil
+	}
+
+	return newErrorResponse("REPOSITORY_UNREACHABLE", unreachable.Error(), http.StatusUnprocessableEntity,
+		map[string]interface{}{
+			"repository": unreachable.Repository,
+			"reason":     unreachable.Reason,
+		})
+}
+
+// writeUpstreamTimeoutResponse writes a 504 when err was caused by a Kubernetes or ArgoCD call timing out
+func (h *RegistrationHandler) writeUpstreamTimeoutResponse(w http.ResponseWriter, err error) bool {
+	resp := upstreamTimeoutResponse(err)
+	if resp == nil {
+		return false
+	}
+
+	h.writeError(w, resp)
+	return true
+}
+
+// writeRepositoryUnreachableResponse writes a 422 when ArgoCD could not reach the repository being registered
+func (h *RegistrationHandler) writeRepositoryUnreachableResponse(w http.ResponseWriter, err error) bool {
+	resp := repositoryUnreachableResponse(err)
+	if resp == nil {
+		return false
+	}
+
+	h.writeError(w, resp)
+	return true
+}
+
+// newErrorResponse builds a standardized error response
+func newErrorResponse(errorCode, message string, statusCode int, details map[string]interface{}) *types.ErrorResponse {
+	return &types.ErrorResponse{
 		Error:   errorCode,
 		Message: message,
+		Details: details,
 		Code:    statusCode,
-	}); err != nil {
+	}
+}
+
+// writeErrorResponse writes a standardized error response
+func (h *RegistrationHandler) writeErrorResponse(w http.ResponseWriter, errorCode, message string, statusCode int) {
+	h.writeErrorResponseWithDetails(w, errorCode, message, statusCode, nil)
+}
+
+// writeErrorResponseWithDetails writes a standardized error response carrying additional details
+func (h *RegistrationHandler) writeErrorResponseWithDetails(w http.ResponseWriter, errorCode, message string,
+	statusCode int, details map[string]interface{}) {
+	h.writeError(w, newErrorResponse(errorCode, message, statusCode, details))
+}
+
+// writeError writes a prepared error response using its code as the HTTP status
+func (h *RegistrationHandler) writeError(w http.ResponseWriter, resp *types.ErrorResponse) {
+	w.WriteHeader(resp.Code)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		h.logger.WithError(err).Error("Failed to encode error response")
 	}
 }
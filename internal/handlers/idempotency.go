@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+)
+
+// IdempotencyKeyHeader is the request header clients set to make POST retries safe
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// defaultIdempotencyKeyTTL is used when the configured TTL is missing or invalid
+const defaultIdempotencyKeyTTL = time.Hour
+
+// idempotencyOutcome describes how a request carrying an idempotency key must be handled
+type idempotencyOutcome int
+
+const (
+	// idempotencyReserved means the key is new and the caller now owns it
+	idempotencyReserved idempotencyOutcome = iota
+	// idempotencyReplay means the key completed with the same body and its response must be replayed
+	idempotencyReplay
+	// idempotencyInFlight means another request with the key is still being processed
+	idempotencyInFlight
+	// idempotencyMismatch means the key was already used with a different request body
+	idempotencyMismatch
+)
+
+// idempotencyEntry holds a reserved key and, once the request succeeded, its registration response
+type idempotencyEntry struct {
+	bodyHash     string
+	registration *types.Registration // nil while the request is in flight
+	expiresAt    time.Time
+}
+
+// idempotencyCache remembers registrations by user-scoped idempotency key
+type idempotencyCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]idempotencyEntry
+	now     func() time.Time
+}
+
+// newIdempotencyCache creates an empty cache whose entries expire after ttl
+func newIdempotencyCache(ttl time.Duration) *idempotencyCache {
+	return &idempotencyCache{
+		ttl:     ttl,
+		entries: make(map[string]idempotencyEntry),
+		now:     time.Now,
+	}
+}
+
+// reserve claims the key for a request with the given body hash. When the key completed earlier
+// with the same body, the cached registration is returned for replay.
+func (c *idempotencyCache) reserve(username, key, bodyHash string) (*types.Registration, idempotencyOutcome) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.pruneLocked(now)
+
+	cacheKey := idempotencyCacheKey(username, key)
+	if entry, ok := c.entries[cacheKey]; ok {
+		switch {
+		case entry.bodyHash != bodyHash:
+			return nil, idempotencyMismatch
+		case entry.registration == nil:
+			return nil, idempotencyInFlight
+		default:
+			return entry.registration, idempotencyReplay
+		}
+	}
+
+	c.entries[cacheKey] = idempotencyEntry{
+		bodyHash:  bodyHash,
+		expiresAt: now.Add(c.ttl),
+	}
+	return nil, idempotencyReserved
+}
+
+// complete stores the registration created for a reserved key
+func (c *idempotencyCache) complete(username, key string, registration *types.Registration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cacheKey := idempotencyCacheKey(username, key)
+	entry, ok := c.entries[cacheKey]
+	if !ok {
+		return
+	}
+	entry.registration = registration
+	entry.expiresAt = c.now().Add(c.ttl)
+	c.entries[cacheKey] = entry
+}
+
+// release drops a reservation that did not complete so the request can be retried with the same key
+func (c *idempotencyCache) release(username, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cacheKey := idempotencyCacheKey(username, key)
+	if entry, ok := c.entries[cacheKey]; ok && entry.registration == nil {
+		delete(c.entries, cacheKey)
+	}
+}
+
+// pruneLocked removes expired entries; c.mu must be held
+func (c *idempotencyCache) pruneLocked(now time.Time) {
+	for cacheKey, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, cacheKey)
+		}
+	}
+}
+
+// idempotencyCacheKey scopes idempotency keys per user
+func idempotencyCacheKey(username, key string) string {
+	return username + "\x00" + key
+}
+
+// hashRequestBody fingerprints a request body so a reused key with a different body can be detected
+func hashRequestBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyCache(t *testing.T) {
+	registration := &types.Registration{ID: "test-reg-123"}
+
+	t.Run("returns cached registration for same user, key and body", func(t *testing.T) {
+		cache := newIdempotencyCache(time.Hour)
+		_, outcome := cache.reserve("alice", "key-1", "body-a")
+		assert.Equal(t, idempotencyReserved, outcome)
+		cache.complete("alice", "key-1", registration)
+
+		cached, outcome := cache.reserve("alice", "key-1", "body-a")
+		assert.Equal(t, idempotencyReplay, outcome)
+		assert.Equal(t, "test-reg-123", cached.ID)
+	})
+
+	t.Run("keys are scoped per user", func(t *testing.T) {
+		cache := newIdempotencyCache(time.Hour)
+		cache.reserve("alice", "key-1", "body-a")
+		cache.complete("alice", "key-1", registration)
+
+		_, outcome := cache.reserve("bob", "key-1", "body-a")
+		assert.Equal(t, idempotencyReserved, outcome)
+	})
+
+	t.Run("concurrent use of a key is reported as in flight", func(t *testing.T) {
+		cache := newIdempotencyCache(time.Hour)
+		cache.reserve("alice", "key-1", "body-a")
+
+		_, outcome := cache.reserve("alice", "key-1", "body-a")
+		assert.Equal(t, idempotencyInFlight, outcome)
+	})
+
+	t.Run("reuse with a different body is a mismatch", func(t *testing.T) {
+		cache := newIdempotencyCache(time.Hour)
+		cache.reserve("alice", "key-1", "body-a")
+		cache.complete("alice", "key-1", registration)
+
+		_, outcome := cache.reserve("alice", "key-1", "body-b")
+		assert.Equal(t, idempotencyMismatch, outcome)
+	})
+
+	t.Run("released reservations can be retried", func(t *testing.T) {
+		cache := newIdempotencyCache(time.Hour)
+		cache.reserve("alice", "key-1", "body-a")
+		cache.release("alice", "key-1")
+
+		_, outcome := cache.reserve("alice", "key-1", "body-b")
+		assert.Equal(t, idempotencyReserved, outcome)
+	})
+
+	t.Run("release keeps completed entries", func(t *testing.T) {
+		cache := newIdempotencyCache(time.Hour)
+		cache.reserve("alice", "key-1", "body-a")
+		cache.complete("alice", "key-1", registration)
+		cache.release("alice", "key-1")
+
+		_, outcome := cache.reserve("alice", "key-1", "body-a")
+		assert.Equal(t, idempotencyReplay, outcome)
+	})
+
+	t.Run("entries expire after TTL", func(t *testing.T) {
+		now := time.Now()
+		cache := newIdempotencyCache(time.Minute)
+		cache.now = func() time.Time { return now }
+		cache.reserve("alice", "key-1", "body-a")
+		cache.complete("alice", "key-1", registration)
+
+		cache.now = func() time.Time { return now.Add(2 * time.Minute) }
+		_, outcome := cache.reserve("alice", "key-2", "body-a")
+		assert.Equal(t, idempotencyReserved, outcome)
+		assert.Len(t, cache.entries, 1, "expired entries are pruned")
+	})
+}
@@ -0,0 +1,31 @@
+// Package metrics holds Prometheus collectors shared across the service
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// ManagedNamespaces reports the namespaces currently managed by the service, excluding terminating ones
+var ManagedNamespaces = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "gitops_managed_namespaces",
+	Help: "Number of namespaces managed by the GitOps registration service",
+})
+
+// CapacityUtilization reports managed namespaces as a fraction of capacity.limits.maxNamespaces
+var CapacityUtilization = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "gitops_capacity_utilization_ratio",
+	Help: "Managed namespaces divided by the configured maximum, or 0 when no maximum is set",
+})
+
+func init() {
+	prometheus.MustRegister(ManagedNamespaces, CapacityUtilization)
+}
+
+// SetCapacity updates the capacity gauges from the managed namespace count and configured maximum
+func SetCapacity(managed, maxNamespaces int) {
+	ManagedNamespaces.Set(float64(managed))
+
+	if maxNamespaces <= 0 {
+		CapacityUtilization.Set(0)
+		return
+	}
+	CapacityUtilization.Set(float64(managed) / float64(maxNamespaces))
+}
@@ -0,0 +1,22 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetCapacity(t *testing.T) {
+	SetCapacity(25, 100)
+	assert.Equal(t, 25.0, testutil.ToFloat64(ManagedNamespaces))
+	assert.Equal(t, 0.25, testutil.ToFloat64(CapacityUtilization))
+
+	// Over capacity is reported as is so alerts can fire on ratios above 1
+	SetCapacity(120, 100)
+	assert.Equal(t, 1.2, testutil.ToFloat64(CapacityUtilization))
+
+	SetCapacity(7, 0)
+	assert.Equal(t, 7.0, testutil.ToFloat64(ManagedNamespaces))
+	assert.Equal(t, 0.0, testutil.ToFloat64(CapacityUtilization))
+}
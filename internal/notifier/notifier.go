@@ -0,0 +1,184 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// SignatureHeader carries the hex encoded HMAC-SHA256 of the request body when a signing secret is configured
+const SignatureHeader = "X-Gitops-Signature"
+
+const (
+	defaultMaxAttempts = 3
+	defaultTimeout     = 5 * time.Second
+	defaultBackoff     = time.Second
+)
+
+// EventType identifies a registration lifecycle event
+type EventType string
+
+const (
+	// EventRegistrationSucceeded is sent after a tenant is registered
+	EventRegistrationSucceeded EventType = "registration.succeeded"
+	// EventRegistrationFailed is sent when a registration could not be completed
+	EventRegistrationFailed EventType = "registration.failed"
+	// EventRegistrationArchived is sent after a registration is archived, ahead of its removal
+	EventRegistrationArchived EventType = "registration.archived"
+	// EventRegistrationUnarchived is sent after an archived registration is restored
+	EventRegistrationUnarchived EventType = "registration.unarchived"
+	// EventRegistrationDeleted is sent after a registration is removed
+	EventRegistrationDeleted EventType = "registration.deleted"
+	// EventDeletionFailed is sent when a registration could not be removed
+	EventDeletionFailed EventType = "registration.deletion_failed"
+)
+
+// Event is the JSON payload delivered to the webhook
+type Event struct {
+	Type           EventType `json:"type"`
+	RegistrationID string    `json:"registrationId,omitempty"`
+	Namespace      string    `json:"namespace,omitempty"`
+	Repo           string    `json:"repo,omitempty"`
+	Status         string    `json:"status"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// Notifier publishes registration lifecycle events without blocking the caller
+type Notifier interface {
+	Notify(event Event)
+}
+
+// New returns a WebhookNotifier, or a notifier that drops events when no webhook URL is configured
+func New(cfg config.NotificationsConfig, logger *logrus.Logger) Notifier {
+	if cfg.WebhookURL == "" {
+		return noopNotifier{}
+	}
+	return NewWebhookNotifier(cfg, logger)
+}
+
+type noopNotifier struct{}
+
+func (noopNotifier) Notify(Event) {}
+
+// WebhookNotifier POSTs events to a configured URL with bounded retries
+type WebhookNotifier struct {
+	url         string
+	secret      string
+	maxAttempts int
+	backoff     time.Duration
+	client      *http.Client
+	logger      *logrus.Logger
+}
+
+// NewWebhookNotifier creates a webhook notifier from configuration, falling back to defaults for unset values
+func NewWebhookNotifier(cfg config.NotificationsConfig, logger *logrus.Logger) *WebhookNotifier {
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	timeout := defaultTimeout
+	if cfg.Timeout != "" {
+		parsed, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || parsed <= 0 {
+			logger.WithField("timeout", cfg.Timeout).Warnf("Invalid notification timeout, using default %s", defaultTimeout)
+		} else {
+			timeout = parsed
+		}
+	}
+
+	return &WebhookNotifier{
+		url:         cfg.WebhookURL,
+		secret:      cfg.SigningSecret,
+		maxAttempts: maxAttempts,
+		backoff:     defaultBackoff,
+		client:      &http.Client{Timeout: timeout},
+		logger:      logger,
+	}
+}
+
+// Notify delivers the event in the background so request handling is never delayed
+func (n *WebhookNotifier) Notify(event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	go func() {
+		if err := n.deliver(context.Background(), event); err != nil {
+			n.logger.WithError(err).WithFields(logrus.Fields{
+				"type":           event.Type,
+				"registrationID": event.RegistrationID,
+				"namespace":      event.Namespace,
+			}).Warn("Failed to deliver registration notification")
+		}
+	}()
+}
+
+// deliver sends the event, retrying failed attempts with linear backoff
+func (n *WebhookNotifier) deliver(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= n.maxAttempts; attempt++ {
+		if lastErr = n.send(ctx, body); lastErr == nil {
+			return nil
+		}
+		if attempt < n.maxAttempts {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(n.backoff * time.Duration(attempt)):
+			}
+		}
+	}
+
+	return fmt.Errorf("notification not delivered after %d attempts: %w", n.maxAttempts, lastErr)
+}
+
+func (n *WebhookNotifier) send(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.secret != "" {
+		req.Header.Set(SignatureHeader, Sign(n.secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		// Drop the URL from transport errors, since webhook URLs often embed a credential
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("webhook request failed: %w", urlErr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the signature header value for body, in the form "sha256=<hex digest>"
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,178 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedRequest struct {
+	body      []byte
+	signature string
+}
+
+func newTestLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	return logger
+}
+
+func TestNew_DisabledWithoutURL(t *testing.T) {
+	n := New(config.NotificationsConfig{}, newTestLogger())
+	assert.IsType(t, noopNotifier{}, n)
+
+	// Dropping events must not panic
+	n.Notify(Event{Type: EventRegistrationSucceeded})
+
+	assert.IsType(t, &WebhookNotifier{}, New(config.NotificationsConfig{WebhookURL: "http://example.com"}, newTestLogger()))
+}
+
+func TestNewWebhookNotifier_Defaults(t *testing.T) {
+	n := NewWebhookNotifier(config.NotificationsConfig{WebhookURL: "http://example.com", Timeout: "soon"}, newTestLogger())
+	assert.Equal(t, defaultMaxAttempts, n.maxAttempts)
+	assert.Equal(t, defaultTimeout, n.client.Timeout)
+
+	n = NewWebhookNotifier(config.NotificationsConfig{WebhookURL: "http://example.com", MaxAttempts: 5, Timeout: "2s"}, newTestLogger())
+	assert.Equal(t, 5, n.maxAttempts)
+	assert.Equal(t, 2*time.Second, n.client.Timeout)
+}
+
+func TestWebhookNotifier_Notify_PayloadAndSignature(t *testing.T) {
+	received := make(chan receivedRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		received <- receivedRequest{body: body, signature: r.Header.Get(SignatureHeader)}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(config.NotificationsConfig{WebhookURL: server.URL, SigningSecret: "s3cret"}, newTestLogger())
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	n.Notify(Event{
+		Type:           EventRegistrationSucceeded,
+		RegistrationID: "reg-123",
+		Namespace:      "team-a",
+		Repo:           "https://github.com/example/team-a",
+		Status:         "active",
+		Timestamp:      timestamp,
+	})
+
+	var req receivedRequest
+	select {
+	case req = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal(req.body, &payload))
+	assert.Equal(t, "registration.succeeded", payload["type"])
+	assert.Equal(t, "reg-123", payload["registrationId"])
+	assert.Equal(t, "team-a", payload["namespace"])
+	assert.Equal(t, "https://github.com/example/team-a", payload["repo"])
+	assert.Equal(t, "active", payload["status"])
+	assert.Equal(t, "2024-01-02T03:04:05Z", payload["timestamp"])
+
+	assert.Equal(t, Sign("s3cret", req.body), req.signature)
+}
+
+func TestWebhookNotifier_Notify_SetsTimestamp(t *testing.T) {
+	received := make(chan Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(config.NotificationsConfig{WebhookURL: server.URL}, newTestLogger())
+	n.Notify(Event{Type: EventRegistrationDeleted, RegistrationID: "reg-123", Status: "deleted"})
+
+	select {
+	case event := <-received:
+		assert.False(t, event.Timestamp.IsZero())
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+}
+
+func TestWebhookNotifier_Deliver_Unsigned(t *testing.T) {
+	var signature atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature.Store(r.Header.Get(SignatureHeader))
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(config.NotificationsConfig{WebhookURL: server.URL}, newTestLogger())
+	require.NoError(t, n.deliver(context.Background(), Event{Type: EventRegistrationFailed}))
+	assert.Equal(t, "", signature.Load())
+}
+
+func TestWebhookNotifier_Deliver_Retries(t *testing.T) {
+	t.Run("Succeeds after transient failures", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+
+		n := NewWebhookNotifier(config.NotificationsConfig{WebhookURL: server.URL, MaxAttempts: 3}, newTestLogger())
+		n.backoff = time.Millisecond
+
+		require.NoError(t, n.deliver(context.Background(), Event{Type: EventRegistrationSucceeded}))
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Gives up after max attempts", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		n := NewWebhookNotifier(config.NotificationsConfig{WebhookURL: server.URL, MaxAttempts: 2}, newTestLogger())
+		n.backoff = time.Millisecond
+
+		err := n.deliver(context.Background(), Event{Type: EventRegistrationSucceeded})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "after 2 attempts")
+		assert.Contains(t, err.Error(), "status 500")
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Transport errors do not expose the webhook URL", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		server.Close()
+
+		n := NewWebhookNotifier(config.NotificationsConfig{
+			WebhookURL:  server.URL + "/services/T000/B000/XXXX",
+			MaxAttempts: 1,
+		}, newTestLogger())
+
+		err := n.deliver(context.Background(), Event{Type: EventRegistrationSucceeded})
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "XXXX")
+	})
+}
+
+func TestSign(t *testing.T) {
+	// Well-known HMAC-SHA256 test vector
+	assert.Equal(t,
+		"sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		Sign("key", []byte("The quick brown fox jumps over the lazy dog")))
+}
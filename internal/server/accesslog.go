@@ -0,0 +1,65 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/konflux-ci/gitops-registration-service/internal/handlers"
+	"github.com/sirupsen/logrus"
+)
+
+// accessLog returns middleware that writes one structured log entry per request
+func accessLog(cfg config.AccessLogConfig, logger *logrus.Logger) func(http.Handler) http.Handler {
+	level := logrus.InfoLevel
+	if cfg.Level != "" {
+		parsed, err := logrus.ParseLevel(cfg.Level)
+		if err != nil {
+			logger.WithField("level", cfg.Level).Warn("Invalid access log level, using info")
+		} else {
+			level = parsed
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isExcludedPath(r.URL.Path, cfg.ExcludePaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			ctx, user := handlers.WithRequestUser(r.Context())
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			logger.WithFields(logrus.Fields{
+				"method":    r.Method,
+				"path":      r.URL.Path,
+				"status":    status,
+				"bytes":     ww.BytesWritten(),
+				"latencyMs": float64(time.Since(start).Microseconds()) / 1000,
+				"user":      user(),
+				"requestID": middleware.GetReqID(ctx),
+			}).Log(level, "HTTP request")
+		})
+	}
+}
+
+// isExcludedPath reports whether path starts with any of the excluded prefixes
+func isExcludedPath(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
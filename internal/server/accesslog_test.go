@@ -0,0 +1,122 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/konflux-ci/gitops-registration-service/internal/services"
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAccessLogServer(accessLog config.AccessLogConfig) (*Server, *MockAuthorizationService, *logtest.Hook) {
+	logger, hook := logtest.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	mockAuth := &MockAuthorizationService{}
+	server := &Server{
+		config: &config.Config{
+			Server: config.ServerConfig{Timeout: "30s", AccessLog: accessLog},
+		},
+		logger: logger,
+		router: chi.NewRouter(),
+		services: &services.Services{
+			Kubernetes:          &MockKubernetesService{},
+			ArgoCD:              &MockArgoCDService{},
+			Registration:        &MockRegistrationService{},
+			RegistrationControl: &MockRegistrationControlService{},
+			Authorization:       mockAuth,
+		},
+	}
+	server.setupMiddleware()
+	server.setupRoutes()
+
+	return server, mockAuth, hook
+}
+
+// accessLogEntries returns the access log entries captured by the hook
+func accessLogEntries(hook *logtest.Hook) []logrus.Entry {
+	var entries []logrus.Entry
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "HTTP request" {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries
+}
+
+func TestAccessLog_LogsRequestFields(t *testing.T) {
+	server, mockAuth, hook := setupAccessLogServer(config.AccessLogConfig{Level: "info"})
+
+	adminUser := &types.UserInfo{Username: "admin"}
+	mockAuth.On("ExtractUserInfo", mock.Anything, "valid-token").Return(adminUser, nil)
+	mockAuth.On("IsAdminUser", adminUser).Return(true)
+
+	req := httptest.NewRequest("GET", "/api/v1/config", http.NoBody)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	req.Header.Set("X-Request-Id", "req-123")
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	entries := accessLogEntries(hook)
+	require.Len(t, entries, 1)
+
+	entry := entries[0]
+	assert.Equal(t, logrus.InfoLevel, entry.Level)
+	assert.Equal(t, "GET", entry.Data["method"])
+	assert.Equal(t, "/api/v1/config", entry.Data["path"])
+	assert.Equal(t, http.StatusOK, entry.Data["status"])
+	assert.Equal(t, w.Body.Len(), entry.Data["bytes"])
+	assert.Contains(t, entry.Data, "latencyMs")
+	assert.Equal(t, "admin", entry.Data["user"])
+	assert.Equal(t, "req-123", entry.Data["requestID"])
+}
+
+func TestAccessLog_UnauthenticatedRequest(t *testing.T) {
+	server, _, hook := setupAccessLogServer(config.AccessLogConfig{Level: "debug"})
+
+	req := httptest.NewRequest("GET", "/api/v1/config", http.NoBody)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+
+	entries := accessLogEntries(hook)
+	require.Len(t, entries, 1)
+	assert.Equal(t, logrus.DebugLevel, entries[0].Level)
+	assert.Equal(t, http.StatusUnauthorized, entries[0].Data["status"])
+	assert.Equal(t, "", entries[0].Data["user"])
+	assert.NotEmpty(t, entries[0].Data["requestID"])
+}
+
+func TestAccessLog_ExcludedPaths(t *testing.T) {
+	server, _, hook := setupAccessLogServer(config.AccessLogConfig{
+		ExcludePaths: []string{"/health/", "/metrics"},
+	})
+
+	for _, path := range []string{"/health/live", "/metrics"} {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest("GET", path, http.NoBody))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	assert.Empty(t, accessLogEntries(hook))
+
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest("GET", "/openapi.json", http.NoBody))
+	assert.Len(t, accessLogEntries(hook), 1)
+}
+
+func TestIsExcludedPath(t *testing.T) {
+	prefixes := []string{"/health/", "/metrics"}
+
+	assert.True(t, isExcludedPath("/health/ready", prefixes))
+	assert.True(t, isExcludedPath("/metrics", prefixes))
+	assert.False(t, isExcludedPath("/api/v1/registrations", prefixes))
+	assert.False(t, isExcludedPath("/health/live", nil))
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/cors"
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/konflux-ci/gitops-registration-service/internal/handlers"
+)
+
+// Used when server.cors leaves origins or methods unset
+var (
+	defaultCORSOrigins = []string{"*"}
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+)
+
+// corsMiddleware returns middleware applying the configured CORS policy.
+// Requests from an origin that is not allowed get no Access-Control-Allow-Origin header.
+func corsMiddleware(cfg config.CORSConfig) func(http.Handler) http.Handler {
+	origins := cfg.AllowedOrigins
+	if len(origins) == 0 {
+		origins = defaultCORSOrigins
+	}
+	methods := cfg.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+
+	return cors.Handler(cors.Options{
+		AllowedOrigins:   origins,
+		AllowedMethods:   methods,
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", handlers.IdempotencyKeyHeader},
+		ExposedHeaders:   []string{"Link", "Idempotent-Replayed"},
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           300,
+	})
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func corsPreflight(t *testing.T, cfg config.CORSConfig, origin, method string) http.Header {
+	t.Helper()
+	handler := corsMiddleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("OPTIONS", "/api/v1/registrations", http.NoBody)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w.Header()
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	restricted := config.CORSConfig{
+		AllowedOrigins: []string{"https://console.example.com", "https://*.apps.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+	}
+
+	t.Run("allowed origin", func(t *testing.T) {
+		headers := corsPreflight(t, restricted, "https://console.example.com", "POST")
+		assert.Equal(t, "https://console.example.com", headers.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "POST", headers.Get("Access-Control-Allow-Methods"))
+		assert.Empty(t, headers.Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("wildcard origin", func(t *testing.T) {
+		headers := corsPreflight(t, restricted, "https://team.apps.example.com", "GET")
+		assert.Equal(t, "https://team.apps.example.com", headers.Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("disallowed origin", func(t *testing.T) {
+		headers := corsPreflight(t, restricted, "https://evil.example.org", "POST")
+		assert.Empty(t, headers.Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("disallowed method", func(t *testing.T) {
+		headers := corsPreflight(t, restricted, "https://console.example.com", "DELETE")
+		assert.Empty(t, headers.Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("credentials mode", func(t *testing.T) {
+		withCredentials := restricted
+		withCredentials.AllowCredentials = true
+
+		headers := corsPreflight(t, withCredentials, "https://console.example.com", "POST")
+		assert.Equal(t, "https://console.example.com", headers.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", headers.Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("unset keeps the permissive defaults", func(t *testing.T) {
+		headers := corsPreflight(t, config.CORSConfig{}, "http://example.com", "DELETE")
+		assert.Equal(t, "*", headers.Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "DELETE", headers.Get("Access-Control-Allow-Methods"))
+	})
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+)
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// ShutdownTimeout returns the configured grace period for in-flight requests, falling back to 30s
+func (s *Server) ShutdownTimeout() time.Duration {
+	timeout, err := time.ParseDuration(s.config.Server.ShutdownTimeout)
+	if err != nil || timeout <= 0 {
+		s.logger.WithField("shutdownTimeout", s.config.Server.ShutdownTimeout).
+			Warnf("Invalid shutdown timeout, using default %s", defaultShutdownTimeout)
+		return defaultShutdownTimeout
+	}
+	return timeout
+}
+
+// DrainDelay returns how long readiness reports draining before the listener is closed, giving load
+// balancers time to stop routing to this instance. Empty or invalid values disable the delay.
+func (s *Server) DrainDelay() time.Duration {
+	if s.config == nil || s.config.Server.DrainDelay == "" {
+		return 0
+	}
+	delay, err := time.ParseDuration(s.config.Server.DrainDelay)
+	if err != nil || delay < 0 {
+		s.logger.WithField("drainDelay", s.config.Server.DrainDelay).Warn("Invalid drain delay, closing the listener immediately")
+		return 0
+	}
+	return delay
+}
+
+// rejectWhileDraining returns 503 for new write requests once shutdown has begun, so in-flight
+// registrations can finish without new ones starting. Reads, including health probes, are still served.
+func (s *Server) rejectWhileDraining(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.draining.Load() || isReadOnlyMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Connection", "close")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		response := types.ErrorResponse{
+			Error:   "SHUTTING_DOWN",
+			Message: "Service is shutting down, retry the request against another instance",
+			Code:    http.StatusServiceUnavailable,
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			s.logger.WithError(err).Error("Failed to encode shutdown response")
+		}
+	})
+}
+
+// rejectInMaintenance returns 503 for write requests while server.maintenanceMode is enabled, freezing
+// registrations, deletions and syncs. Reads and health probes are still served.
+func (s *Server) rejectInMaintenance(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.config.Server.MaintenanceMode || isReadOnlyMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		response := types.ErrorResponse{
+			Error:   "MAINTENANCE_MODE",
+			Message: "Service is in maintenance mode, changes are not accepted until it ends",
+			Code:    http.StatusServiceUnavailable,
+		}
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			s.logger.WithError(err).Error("Failed to encode maintenance mode response")
+		}
+	})
+}
+
+func isReadOnlyMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
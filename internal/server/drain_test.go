@@ -0,0 +1,157 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRejectWhileDraining(t *testing.T) {
+	server, _, _ := setupTestServer()
+	server.draining.Store(true)
+
+	t.Run("New registrations are rejected", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"namespace":"team-a","repository":{"url":"https://github.com/test/repo"}}`)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/registrations", body))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "close", w.Header().Get("Connection"))
+
+		var response types.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "SHUTTING_DOWN", response.Error)
+	})
+
+	t.Run("Deletions are rejected", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest("DELETE", "/api/v1/registrations/reg-123", http.NoBody))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+
+	t.Run("Health checks are still served", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest("GET", "/health/live", http.NoBody))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestRejectWhileDraining_NotDraining(t *testing.T) {
+	server, _, _ := setupTestServer()
+
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/registrations", bytes.NewBufferString(`{}`)))
+	assert.NotEqual(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestRejectInMaintenance(t *testing.T) {
+	server, _, _ := setupTestServer()
+	server.config.Server.MaintenanceMode = true
+
+	mutations := []struct {
+		method string
+		path   string
+	}{
+		{"POST", "/api/v1/registrations"},
+		{"POST", "/api/v1/registrations/existing"},
+		{"DELETE", "/api/v1/registrations/reg-123"},
+		{"POST", "/api/v1/registrations/reg-123/sync"},
+	}
+	for _, m := range mutations {
+		t.Run(m.method+" "+m.path+" is rejected", func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, httptest.NewRequest(m.method, m.path, bytes.NewBufferString(`{}`)))
+
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+			var response types.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "MAINTENANCE_MODE", response.Error)
+		})
+	}
+
+	t.Run("Reads are still served", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest("GET", "/health/live", http.NoBody))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/registrations/reg-123", http.NoBody))
+		assert.NotEqual(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
+
+func TestServer_Shutdown_StartsDraining(t *testing.T) {
+	server, _, _ := setupTestServer()
+	server.server = &http.Server{Handler: server.router, ReadHeaderTimeout: time.Second}
+
+	require.NoError(t, server.Shutdown(context.Background()))
+	assert.True(t, server.draining.Load())
+}
+
+func TestServer_Shutdown_DrainDelay(t *testing.T) {
+	t.Run("Readiness reports draining before the listener closes", func(t *testing.T) {
+		server, _, _ := setupTestServer()
+		server.config.Server.DrainDelay = "200ms"
+		server.server = &http.Server{Handler: server.router, ReadHeaderTimeout: time.Second}
+
+		done := make(chan error, 1)
+		start := time.Now()
+		go func() { done <- server.Shutdown(context.Background()) }()
+
+		require.Eventually(t, server.draining.Load, time.Second, time.Millisecond)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest("GET", "/health/ready", http.NoBody))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), "draining")
+
+		require.NoError(t, <-done)
+		assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	})
+
+	t.Run("An expired context cuts the delay short", func(t *testing.T) {
+		server, _, _ := setupTestServer()
+		server.config.Server.DrainDelay = "1h"
+		server.server = &http.Server{Handler: server.router, ReadHeaderTimeout: time.Second}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		start := time.Now()
+		_ = server.Shutdown(ctx)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
+func TestServer_DrainDelay(t *testing.T) {
+	server, _, _ := setupTestServer()
+
+	server.config.Server.DrainDelay = "5s"
+	assert.Equal(t, 5*time.Second, server.DrainDelay())
+
+	server.config.Server.DrainDelay = ""
+	assert.Equal(t, time.Duration(0), server.DrainDelay())
+
+	server.config.Server.DrainDelay = "later"
+	assert.Equal(t, time.Duration(0), server.DrainDelay())
+}
+
+func TestServer_ShutdownTimeout(t *testing.T) {
+	server, _, _ := setupTestServer()
+
+	server.config.Server.ShutdownTimeout = "45s"
+	assert.Equal(t, 45*time.Second, server.ShutdownTimeout())
+
+	server.config.Server.ShutdownTimeout = ""
+	assert.Equal(t, defaultShutdownTimeout, server.ShutdownTimeout())
+
+	server.config.Server.ShutdownTimeout = "soon"
+	assert.Equal(t, defaultShutdownTimeout, server.ShutdownTimeout())
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTestServerWithHealthPort returns a test server serving probes and metrics on a separate health server
+func setupTestServerWithHealthPort(t *testing.T) (*Server, *MockKubernetesService, *MockArgoCDService) {
+	server, mockK8s, mockArgoCD := setupTestServer()
+	server.config.Server.Port = freePort(t)
+	server.config.Server.HealthPort = freePort(t)
+
+	server.router = chi.NewRouter()
+	server.setupMiddleware()
+	server.setupRoutes()
+	server.server = &http.Server{
+		Addr:              fmt.Sprintf(":%d", server.config.Server.Port),
+		Handler:           server.router,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	server.health = server.newHealthServer()
+	return server, mockK8s, mockArgoCD
+}
+
+// freePort returns a TCP port that was available when it was checked
+func freePort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestServer_HealthPort_Routes(t *testing.T) {
+	server, _, _ := setupTestServerWithHealthPort(t)
+
+	t.Run("Probes and metrics are served on the health server", func(t *testing.T) {
+		for _, path := range []string{"/health/live", "/metrics"} {
+			w := httptest.NewRecorder()
+			server.health.Handler.ServeHTTP(w, httptest.NewRequest("GET", path, http.NoBody))
+			assert.Equal(t, http.StatusOK, w.Code, path)
+		}
+	})
+
+	t.Run("The API is absent from the health server", func(t *testing.T) {
+		for _, path := range []string{"/api/v1/registrations", "/openapi.json", "/version"} {
+			w := httptest.NewRecorder()
+			server.health.Handler.ServeHTTP(w, httptest.NewRequest("GET", path, http.NoBody))
+			assert.Equal(t, http.StatusNotFound, w.Code, path)
+		}
+	})
+
+	t.Run("Probes and metrics are absent from the API port", func(t *testing.T) {
+		for _, path := range []string{"/health/live", "/health/ready", "/metrics"} {
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, httptest.NewRequest("GET", path, http.NoBody))
+			assert.Equal(t, http.StatusNotFound, w.Code, path)
+		}
+	})
+}
+
+func TestServer_HealthPort_StartAndShutdown(t *testing.T) {
+	server, mockK8s, mockArgoCD := setupTestServerWithHealthPort(t)
+	mockK8s.On("HealthCheck", mock.Anything).Return(nil)
+	mockArgoCD.On("HealthCheck", mock.Anything).Return(nil)
+
+	// Start only returns early on a listener error, which shows up as the health server never answering
+	go func() { _ = server.Start(context.Background()) }()
+
+	healthURL := fmt.Sprintf("http://127.0.0.1:%d", server.config.Server.HealthPort)
+	apiURL := fmt.Sprintf("http://127.0.0.1:%d", server.config.Server.Port)
+	get := func(url string) (int, error) {
+		resp, err := http.Get(url)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+
+	require.Eventually(t, func() bool {
+		code, err := get(healthURL + "/health/ready")
+		return err == nil && code == http.StatusOK
+	}, 5*time.Second, 20*time.Millisecond)
+
+	code, err := get(healthURL + "/api/v1/registrations")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, err = get(apiURL + "/health/live")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, server.Shutdown(shutdownCtx))
+
+	_, err = get(healthURL + "/health/live")
+	assert.Error(t, err, "health server should stop on shutdown")
+	_, err = get(apiURL + "/api/v1/registrations")
+	assert.Error(t, err, "API server should stop on shutdown")
+}
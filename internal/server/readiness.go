@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// maxReadinessBackoff caps how long a failing dependency check is reused, unless the TTL itself is longer
+const maxReadinessBackoff = 30 * time.Second
+
+// readinessCache holds the last readiness dependency check so frequent probes do not reach the
+// upstreams on every request, and a failing upstream is checked less often the longer it fails
+type readinessCache struct {
+	mu        sync.Mutex
+	err       error
+	expiresAt time.Time
+	failures  int // Consecutive failed checks
+
+	now func() time.Time // Overridden in tests
+}
+
+// ReadinessCacheTTL returns how long a passing readiness check is reused; empty or invalid values disable caching
+func (s *Server) ReadinessCacheTTL() time.Duration {
+	if s.config.Server.ReadinessCacheTTL == "" {
+		return 0
+	}
+	ttl, err := time.ParseDuration(s.config.Server.ReadinessCacheTTL)
+	if err != nil || ttl < 0 {
+		s.logger.WithField("readinessCacheTTL", s.config.Server.ReadinessCacheTTL).
+			Warn("Invalid readiness cache TTL, checking dependencies on every probe")
+		return 0
+	}
+	return ttl
+}
+
+// checkReadiness returns the result of checkDependencies, reusing a recent result while it is still valid
+func (s *Server) checkReadiness(ctx context.Context) error {
+	ttl := s.ReadinessCacheTTL()
+	if ttl <= 0 {
+		return s.checkDependencies(ctx)
+	}
+
+	// Holding the lock during the check lets concurrent probes share one upstream call
+	c := &s.readiness
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	if now().Before(c.expiresAt) {
+		return c.err
+	}
+
+	c.err = s.checkDependencies(ctx)
+	if c.err == nil {
+		c.failures = 0
+		c.expiresAt = now().Add(ttl)
+		return nil
+	}
+	c.failures++
+	c.expiresAt = now().Add(readinessBackoff(ttl, c.failures))
+	return c.err
+}
+
+// readinessBackoff returns how long the result of the given consecutive failed check is reused: the TTL
+// doubled per earlier failure up to maxReadinessBackoff, plus up to 20% jitter so replicas do not probe a
+// recovering dependency in lockstep
+func readinessBackoff(ttl time.Duration, failures int) time.Duration {
+	limit := maxReadinessBackoff
+	if ttl > limit {
+		limit = ttl
+	}
+
+	backoff := ttl
+	for i := 1; i < failures && backoff < limit; i++ {
+		backoff *= 2
+	}
+	if backoff > limit {
+		backoff = limit
+	}
+	return backoff + time.Duration(rand.Int63n(int64(backoff)/5+1))
+}
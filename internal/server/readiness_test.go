@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckReadiness_ReusesResultWithinTTL(t *testing.T) {
+	server, mockK8s, mockArgoCD := setupTestServer()
+	server.config.Server.ReadinessCacheTTL = "10s"
+	now := time.Now()
+	server.readiness.now = func() time.Time { return now }
+
+	mockK8s.On("HealthCheck", mock.Anything).Return(nil)
+	mockArgoCD.On("HealthCheck", mock.Anything).Return(nil)
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		server.healthReady(w, httptest.NewRequest("GET", "/health/ready", http.NoBody))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	mockK8s.AssertNumberOfCalls(t, "HealthCheck", 1)
+	mockArgoCD.AssertNumberOfCalls(t, "HealthCheck", 1)
+
+	now = now.Add(11 * time.Second)
+	require.NoError(t, server.checkReadiness(context.Background()))
+	mockK8s.AssertNumberOfCalls(t, "HealthCheck", 2)
+	mockArgoCD.AssertNumberOfCalls(t, "HealthCheck", 2)
+}
+
+func TestCheckReadiness_BacksOffWhileFailing(t *testing.T) {
+	server, mockK8s, _ := setupTestServer()
+	server.config.Server.ReadinessCacheTTL = "1s"
+	now := time.Now()
+	server.readiness.now = func() time.Time { return now }
+
+	mockK8s.On("HealthCheck", mock.Anything).Return(assert.AnError)
+
+	require.Error(t, server.checkReadiness(context.Background()))
+	mockK8s.AssertNumberOfCalls(t, "HealthCheck", 1)
+
+	// The first failure is reused for the TTL plus jitter, the second for about twice as long
+	now = now.Add(1300 * time.Millisecond)
+	require.Error(t, server.checkReadiness(context.Background()))
+	mockK8s.AssertNumberOfCalls(t, "HealthCheck", 2)
+
+	now = now.Add(1300 * time.Millisecond)
+	require.Error(t, server.checkReadiness(context.Background()))
+	mockK8s.AssertNumberOfCalls(t, "HealthCheck", 2)
+
+	now = now.Add(1300 * time.Millisecond)
+	require.Error(t, server.checkReadiness(context.Background()))
+	mockK8s.AssertNumberOfCalls(t, "HealthCheck", 3)
+}
+
+func TestCheckReadiness_CachingDisabled(t *testing.T) {
+	server, mockK8s, mockArgoCD := setupTestServer()
+
+	mockK8s.On("HealthCheck", mock.Anything).Return(nil)
+	mockArgoCD.On("HealthCheck", mock.Anything).Return(nil)
+
+	require.NoError(t, server.checkReadiness(context.Background()))
+	require.NoError(t, server.checkReadiness(context.Background()))
+	mockK8s.AssertNumberOfCalls(t, "HealthCheck", 2)
+}
+
+func TestReadinessBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		ttl      time.Duration
+		failures int
+		min      time.Duration
+	}{
+		{name: "First failure", ttl: time.Second, failures: 1, min: time.Second},
+		{name: "Doubles per failure", ttl: time.Second, failures: 3, min: 4 * time.Second},
+		{name: "Capped", ttl: time.Second, failures: 20, min: maxReadinessBackoff},
+		{name: "TTL above the cap", ttl: time.Minute, failures: 3, min: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				backoff := readinessBackoff(tt.ttl, tt.failures)
+				assert.GreaterOrEqual(t, backoff, tt.min)
+				assert.LessOrEqual(t, backoff, tt.min+tt.min/5)
+			}
+		})
+	}
+}
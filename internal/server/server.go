@@ -3,27 +3,40 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
+	"github.com/konflux-ci/gitops-registration-service/internal/api"
 	"github.com/konflux-ci/gitops-registration-service/internal/config"
 	"github.com/konflux-ci/gitops-registration-service/internal/handlers"
 	"github.com/konflux-ci/gitops-registration-service/internal/services"
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/konflux-ci/gitops-registration-service/internal/version"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 )
 
 // Server represents the HTTP server
 type Server struct {
-	config   *config.Config
-	logger   *logrus.Logger
-	router   *chi.Mux
-	server   *http.Server
-	services *services.Services
+	config     *config.Config
+	logger     *logrus.Logger
+	router     *chi.Mux
+	server     *http.Server
+	health     *http.Server // Serves probes and metrics when server.healthPort is set
+	routeIndex *chi.Mux     // Every route of router without subrouters, used to build the Allow header of a 405
+	services   *services.Services
+	reconciler *services.Reconciler
+	draining   atomic.Bool // Set once shutdown begins
+	starting   atomic.Bool // Set until the first dependency check passes
+	readiness  readinessCache
+
+	startupRetryInterval time.Duration
 }
 
 // New creates a new server instance
@@ -55,18 +68,46 @@ func New(cfg *config.Config, logger *logrus.Logger) (*Server, error) {
 			}
 		}
 
+		// Tenants would act as cluster admins through their service accounts
+		if validation.HasClusterAdmin {
+			return nil, fmt.Errorf("ClusterRole %s grants cluster-admin equivalent permissions and cannot be used for impersonation",
+				cfg.Security.Impersonation.ClusterRole)
+		}
+
 		logger.Infof("ClusterRole %s validated successfully for impersonation", cfg.Security.Impersonation.ClusterRole)
 	}
 
+	// Misspelled resource restrictions are silently ignored by ArgoCD, so surface them at startup
+	if err := services.ValidateResourceRestrictionKinds(context.Background(), svc.Kubernetes, cfg, logger); err != nil {
+		return nil, err
+	}
+
+	// A misconfigured ArgoCD namespace cannot recover at runtime, so refuse to start.
+	// Other health check failures are left to the readiness probe.
+	if err := svc.ArgoCD.HealthCheck(context.Background()); err != nil {
+		var missingErr *services.ArgoCDNamespaceMissingError
+		if errors.As(err, &missingErr) {
+			return nil, err
+		}
+		logger.WithError(err).Warn("ArgoCD health check failed at startup")
+	}
+
+	tlsConfig, err := buildTLSConfig(cfg.Server.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure TLS: %w", err)
+	}
+
 	// Create router
 	router := chi.NewRouter()
 
 	s := &Server{
-		config:   cfg,
-		logger:   logger,
-		router:   router,
-		services: svc,
+		config:     cfg,
+		logger:     logger,
+		router:     router,
+		services:   svc,
+		reconciler: services.NewReconciler(cfg, svc.Kubernetes, svc.ArgoCD, logger),
 	}
+	s.starting.Store(true)
 
 	// Setup middleware
 	s.setupMiddleware()
@@ -79,6 +120,10 @@ func New(cfg *config.Config, logger *logrus.Logger) (*Server, error) {
 		Addr:              fmt.Sprintf(":%d", cfg.Server.Port),
 		Handler:           router,
 		ReadHeaderTimeout: 30 * time.Second, // Prevent Slowloris attacks
+		TLSConfig:         tlsConfig,
+	}
+	if cfg.Server.HealthPort != 0 {
+		s.health = s.newHealthServer()
 	}
 
 	return s, nil
@@ -88,10 +133,37 @@ func New(cfg *config.Config, logger *logrus.Logger) (*Server, error) {
 func (s *Server) Start(ctx context.Context) error {
 	s.logger.WithField("port", s.config.Server.Port).Info("Starting HTTP server")
 
+	// Detect drift between registrations and cluster state in the background
+	if s.reconciler != nil {
+		go s.reconciler.Run(ctx)
+	}
+
 	// Start server in a goroutine
-	errChan := make(chan error, 1)
+	errChan := make(chan error, 3)
 	go func() {
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if s.server.TLSConfig != nil {
+			err = s.server.ListenAndServeTLS(s.config.Server.TLS.CertFile, s.config.Server.TLS.KeyFile)
+		} else {
+			err = s.server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			errChan <- err
+		}
+	}()
+
+	if s.health != nil {
+		s.logger.WithField("port", s.config.Server.HealthPort).Info("Starting health server")
+		go func() {
+			if err := s.health.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				errChan <- fmt.Errorf("health server: %w", err)
+			}
+		}()
+	}
+
+	// Report ready only once dependencies have answered, while liveness is already served
+	go func() {
+		if err := s.awaitDependencies(ctx); err != nil && ctx.Err() == nil {
 			errChan <- err
 		}
 	}()
@@ -99,16 +171,36 @@ func (s *Server) Start(ctx context.Context) error {
 	// Wait for context cancellation or server error
 	select {
 	case <-ctx.Done():
-		return s.server.Shutdown(ctx)
+		return s.Shutdown(ctx)
 	case err := <-errChan:
 		return err
 	}
 }
 
-// Shutdown gracefully shuts down the server
+// Shutdown stops accepting new write requests, reports draining from readiness for the drain delay,
+// then waits for in-flight requests until ctx expires. The delay counts toward ctx's deadline.
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.draining.Store(true)
+
+	if delay := s.DrainDelay(); delay > 0 {
+		s.logger.WithField("drainDelay", delay).Info("Draining before shutting down HTTP server")
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
 	s.logger.Info("Shutting down HTTP server")
-	return s.server.Shutdown(ctx)
+	err := s.server.Shutdown(ctx)
+
+	// Probes keep answering until the API has stopped
+	if s.health != nil {
+		s.logger.Info("Shutting down health server")
+		err = errors.Join(err, s.health.Shutdown(ctx))
+	}
+	return err
 }
 
 // setupMiddleware configures middleware for the router
@@ -116,15 +208,18 @@ func (s *Server) setupMiddleware() {
 	// Request ID middleware
 	s.router.Use(middleware.RequestID)
 
-	// Structured logging middleware
-	s.router.Use(middleware.RequestLogger(&middleware.DefaultLogFormatter{
-		Logger:  s.logger,
-		NoColor: true,
-	}))
+	// Structured access logging middleware
+	s.router.Use(accessLog(s.config.Server.AccessLog, s.logger))
 
 	// Recovery middleware
 	s.router.Use(middleware.Recoverer)
 
+	// Reject new write requests while shutting down
+	s.router.Use(s.rejectWhileDraining)
+
+	// Reject write requests while changes are frozen for maintenance
+	s.router.Use(s.rejectInMaintenance)
+
 	// Timeout middleware
 	timeout, err := time.ParseDuration(s.config.Server.Timeout)
 	if err != nil {
@@ -134,14 +229,7 @@ func (s *Server) setupMiddleware() {
 	s.router.Use(middleware.Timeout(timeout))
 
 	// CORS middleware
-	s.router.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: true,
-		MaxAge:           300,
-	}))
+	s.router.Use(corsMiddleware(s.config.Server.CORS))
 
 	// Content-Type middleware
 	s.router.Use(middleware.SetHeader("Content-Type", "application/json"))
@@ -149,32 +237,138 @@ func (s *Server) setupMiddleware() {
 
 // setupRoutes configures API routes
 func (s *Server) setupRoutes() {
-	// Health check endpoints
-	s.router.Get("/health/live", s.healthLive)
-	s.router.Get("/health/ready", s.healthReady)
+	// Probes and metrics move to the health server when server.healthPort is set
+	if s.config.Server.HealthPort == 0 {
+		s.setupHealthRoutes(s.router)
+	}
+
+	// Build metadata
+	s.router.Get("/version", s.buildVersion)
 
-	// Metrics endpoint
-	s.router.Handle("/metrics", promhttp.Handler())
+	// API contract
+	s.router.Get("/openapi.json", s.openAPISpec)
 
 	// API routes
 	s.router.Route("/api/v1", func(r chi.Router) {
 		// Registration handlers
-		registrationHandler := handlers.NewRegistrationHandler(s.services, s.logger)
+		registrationHandler := handlers.NewRegistrationHandler(s.config, s.services, s.logger)
 
 		r.Route("/registrations", func(r chi.Router) {
 			r.Post("/", registrationHandler.CreateRegistration)
 			r.Get("/", registrationHandler.ListRegistrations)
+			r.Post("/batch", registrationHandler.CreateRegistrationBatch)
 			r.Post("/existing", registrationHandler.RegisterExistingNamespace)
+			r.Delete("/by-namespace/{namespace}", registrationHandler.DeleteRegistrationByNamespace)
 
 			r.Route("/{id}", func(r chi.Router) {
 				r.Get("/", registrationHandler.GetRegistration)
+				r.Patch("/", registrationHandler.UpdateRegistration)
 				r.Delete("/", registrationHandler.DeleteRegistration)
 				r.Get("/status", registrationHandler.GetRegistrationStatus)
+				r.Get("/resources", registrationHandler.GetRegistrationResources)
+				r.Get("/history", registrationHandler.GetRegistrationHistory)
+				r.Get("/events", registrationHandler.GetRegistrationEvents)
 				r.Post("/sync", registrationHandler.SyncRegistration)
+				r.Post("/refresh", registrationHandler.RefreshRegistration)
+				r.Post("/reauthorize", registrationHandler.ReauthorizeRegistration)
+				r.Post("/unarchive", registrationHandler.UnarchiveRegistration)
 			})
 		})
 
+		r.Get("/tenants", registrationHandler.ListTenants)
+		r.Get("/config", registrationHandler.GetConfig)
+		r.Delete("/appprojects", registrationHandler.DeleteAppProjectByRepoHash)
+		r.Post("/appprojects/backfill-repo-hash-labels", registrationHandler.BackfillRepoHashLabels)
+		r.Get("/diagnostics/hash-collisions", registrationHandler.ListRepositoryHashCollisions)
+
+	})
+
+	// Replaces chi's empty 405 body; subrouters inherit it
+	s.routeIndex = newRouteIndex(s.router)
+	s.router.MethodNotAllowed(s.methodNotAllowed)
+}
+
+// routeMethods are the methods routes are registered with, checked to build the Allow header of a 405
+var routeMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// newRouteIndex copies every route of router into a router without subrouters. chi matches the root path of
+// a subrouter, e.g. /api/v1/registrations/{id}, for any method, so methods are matched against the copy.
+func newRouteIndex(router chi.Routes) *chi.Mux {
+	index := chi.NewRouter()
+	noop := func(http.ResponseWriter, *http.Request) {}
+	_ = chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		index.MethodFunc(method, trimTrailingSlash(route), noop)
+		return nil
 	})
+	return index
+}
+
+// trimTrailingSlash drops the trailing slash subrouter root routes are registered with
+func trimTrailingSlash(path string) string {
+	if len(path) > 1 {
+		return strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+// methodNotAllowed returns a 405 whose Allow header lists the methods the requested path supports
+func (s *Server) methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	var allowed []string
+	for _, method := range routeMethods {
+		if s.routeIndex.Match(chi.NewRouteContext(), method, trimTrailingSlash(r.URL.Path)) {
+			allowed = append(allowed, method)
+		}
+	}
+
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusMethodNotAllowed)
+	response := types.ErrorResponse{
+		Error:   "METHOD_NOT_ALLOWED",
+		Message: fmt.Sprintf("Method %s is not allowed on %s", r.Method, r.URL.Path),
+		Details: map[string]interface{}{"allowed": allowed},
+		Code:    http.StatusMethodNotAllowed,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.WithError(err).Error("Failed to encode method not allowed response")
+	}
+}
+
+// setupHealthRoutes configures the health check and metrics endpoints
+func (s *Server) setupHealthRoutes(r chi.Router) {
+	r.Get("/health/live", s.healthLive)
+	r.Get("/health/ready", s.healthReady)
+	r.Handle("/metrics", promhttp.Handler())
+}
+
+// newHealthServer creates the plain HTTP server for server.healthPort. It skips the API middleware so
+// probes are not subject to access logging, draining or maintenance rejection.
+func (s *Server) newHealthServer() *http.Server {
+	router := chi.NewRouter()
+	router.Use(middleware.Recoverer)
+	router.Use(middleware.SetHeader("Content-Type", "application/json"))
+	s.setupHealthRoutes(router)
+
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", s.config.Server.HealthPort),
+		Handler:           router,
+		ReadHeaderTimeout: 30 * time.Second, // Prevent Slowloris attacks
+	}
+}
+
+// openAPISpec serves the OpenAPI document describing the HTTP API
+func (s *Server) openAPISpec(w http.ResponseWriter, r *http.Request) {
+	spec, err := api.SpecJSON()
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to encode OpenAPI spec")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(spec); err != nil {
+		s.logger.WithError(err).Error("Failed to write OpenAPI spec")
+	}
 }
 
 // healthLive handles liveness probe requests
@@ -191,10 +385,37 @@ func (s *Server) healthLive(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// buildVersion reports the build metadata of the running service
+func (s *Server) buildVersion(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(version.Current()); err != nil {
+		s.logger.WithError(err).Error("Failed to encode version response")
+	}
+}
+
 // healthReady handles readiness probe requests
 func (s *Server) healthReady(w http.ResponseWriter, r *http.Request) {
-	// Check dependencies
-	if err := s.checkDependencies(r.Context()); err != nil {
+	// Fail readiness while draining so traffic moves to other instances before the listener closes
+	if s.draining.Load() {
+		response := map[string]interface{}{
+			"status":    "draining",
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		}
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			s.logger.WithError(err).Error("Failed to encode draining response")
+		}
+		return
+	}
+
+	if s.starting.Load() {
+		s.writeStartingResponse(w)
+		return
+	}
+
+	// Check dependencies, reusing a recent result within server.readinessCacheTTL
+	if err := s.checkReadiness(r.Context()); err != nil {
 		s.logger.WithError(err).Error("Readiness check failed")
 
 		response := map[string]interface{}{
@@ -2,11 +2,14 @@ package server
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +17,7 @@ import (
 	"github.com/konflux-ci/gitops-registration-service/internal/config"
 	"github.com/konflux-ci/gitops-registration-service/internal/services"
 	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/konflux-ci/gitops-registration-service/internal/version"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -70,11 +74,25 @@ func (m *MockKubernetesService) UpdateNamespaceMetadata(ctx context.Context, nam
 	return nil
 }
 
+func (m *MockKubernetesService) ClaimNamespace(ctx context.Context, name string, labels, annotations map[string]string) error {
+	args := m.Called(ctx, name, labels, annotations)
+	return args.Error(0)
+}
+
+func (m *MockKubernetesService) UnclaimNamespace(ctx context.Context, name string, claimed, original services.NamespaceMetadata) error {
+	args := m.Called(ctx, name, claimed, original)
+	return args.Error(0)
+}
+
 func (m *MockKubernetesService) CreateRoleBinding(ctx context.Context, namespace, name, role, serviceAccount string) error {
 	args := m.Called(ctx, namespace, name, role, serviceAccount)
 	return args.Error(0)
 }
 
+func (m *MockKubernetesService) CreateLimitRange(ctx context.Context, namespace string, spec *types.LimitRangeSpec) error {
+	return nil
+}
+
 func (m *MockKubernetesService) CheckAppProjectConflict(ctx context.Context, repositoryHash string) (bool, error) {
 	return false, nil
 }
@@ -87,6 +105,18 @@ func (m *MockKubernetesService) CreateServiceAccountWithGenerateName(ctx context
 	return "mock-sa-12345", nil
 }
 
+func (m *MockKubernetesService) HasNamespaceRole(
+	ctx context.Context, userInfo *types.UserInfo, namespace, clusterRole string,
+) (bool, error) {
+	args := m.Called(ctx, userInfo, namespace, clusterRole)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockKubernetesService) ResourceKindExists(ctx context.Context, group, kind string) (bool, error) {
+	args := m.Called(ctx, group, kind)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockKubernetesService) ValidateClusterRole(ctx context.Context, name string) (*services.ClusterRoleValidation, error) {
 	return &services.ClusterRoleValidation{
 		Exists:               true,
@@ -98,6 +128,88 @@ func (m *MockKubernetesService) ValidateClusterRole(ctx context.Context, name st
 	}, nil
 }
 
+func (m *MockKubernetesService) ListManagedNamespaces(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockKubernetesService) ListTerminatingNamespaces(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockKubernetesService) CountManagedNamespaces(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockKubernetesService) CountNamespacesByOwner(ctx context.Context, owner string) (int, error) {
+	args := m.Called(ctx, owner)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockKubernetesService) ListManagedNamespaceInfo(ctx context.Context, selector string) ([]services.NamespaceInfo, error) {
+	args := m.Called(ctx, selector)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]services.NamespaceInfo), args.Error(1)
+}
+
+func (m *MockKubernetesService) ListEvents(ctx context.Context, namespace string) ([]types.RegistrationEvent, error) {
+	args := m.Called(ctx, namespace)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]types.RegistrationEvent), args.Error(1)
+}
+
+func (m *MockKubernetesService) AddNamespaceFinalizer(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockKubernetesService) RemoveNamespaceFinalizer(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockKubernetesService) GetNamespaceUID(ctx context.Context, name string) (string, error) {
+	args := m.Called(ctx, name)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockKubernetesService) GetNamespaceMetadata(ctx context.Context, name string) (*services.NamespaceInfo, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).(*services.NamespaceInfo), args.Error(1)
+}
+
+func (m *MockKubernetesService) DeleteServiceAccount(ctx context.Context, namespace, name string) error {
+	args := m.Called(ctx, namespace, name)
+	return args.Error(0)
+}
+
+func (m *MockKubernetesService) DeleteRoleBinding(ctx context.Context, namespace, name string) error {
+	args := m.Called(ctx, namespace, name)
+	return args.Error(0)
+}
+
+func (m *MockKubernetesService) ServiceAccountExists(ctx context.Context, namespace, name string) (bool, error) {
+	args := m.Called(ctx, namespace, name)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockKubernetesService) RoleBindingExists(ctx context.Context, namespace, name string) (bool, error) {
+	args := m.Called(ctx, namespace, name)
+	return args.Bool(0), args.Error(1)
+}
+
 type MockArgoCDService struct {
 	mock.Mock
 }
@@ -117,6 +229,16 @@ func (m *MockArgoCDService) DeleteAppProject(ctx context.Context, name string) e
 	return args.Error(0)
 }
 
+func (m *MockArgoCDService) AppProjectExists(ctx context.Context, name string) (bool, error) {
+	args := m.Called(ctx, name)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockArgoCDService) AdoptAppProject(ctx context.Context, project *types.AppProject) error {
+	args := m.Called(ctx, project)
+	return args.Error(0)
+}
+
 func (m *MockArgoCDService) CreateApplication(ctx context.Context, app *types.Application) error {
 	args := m.Called(ctx, app)
 	return args.Error(0)
@@ -132,17 +254,129 @@ func (m *MockArgoCDService) GetApplicationStatus(ctx context.Context, name strin
 	return args.Get(0).(*types.ApplicationStatus), args.Error(1)
 }
 
+func (m *MockArgoCDService) ListApplicationStatuses(ctx context.Context) (map[string]*types.ApplicationStatus, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*types.ApplicationStatus), args.Error(1)
+}
+
+func (m *MockArgoCDService) ResolveClusterServer(ctx context.Context, name string) (string, error) {
+	args := m.Called(ctx, name)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockArgoCDService) TestRepositoryConnection(ctx context.Context, repoURL, credsSecret string) error {
+	args := m.Called(ctx, repoURL, credsSecret)
+	return args.Error(0)
+}
+
 func (m *MockArgoCDService) CheckAppProjectConflict(ctx context.Context, repositoryHash string) (bool, error) {
 	return false, nil
 }
 
+func (m *MockArgoCDService) FindAppProjectByRepoHash(ctx context.Context, repositoryHash string) (*types.AppProject, error) {
+	args := m.Called(ctx, repositoryHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.AppProject), args.Error(1)
+}
+
+func (m *MockArgoCDService) UpdateApplicationSource(ctx context.Context, name string, source *types.ApplicationSource) error {
+	args := m.Called(ctx, name, source)
+	return args.Error(0)
+}
+
+func (m *MockArgoCDService) ListManagedAppProjects(ctx context.Context) ([]types.AppProject, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]types.AppProject), args.Error(1)
+}
+
+func (m *MockArgoCDService) ApplicationExists(ctx context.Context, name string) (bool, error) {
+	args := m.Called(ctx, name)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockArgoCDService) CreateApplicationSet(ctx context.Context, appSet *types.ApplicationSet) error {
+	args := m.Called(ctx, appSet)
+	return args.Error(0)
+}
+
+func (m *MockArgoCDService) DeleteApplicationSet(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockArgoCDService) ApplicationSetExists(ctx context.Context, name string) (bool, error) {
+	args := m.Called(ctx, name)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockArgoCDService) DeleteAppProjectByRepoHash(ctx context.Context, repositoryHash string) (*types.AppProject, error) {
+	args := m.Called(ctx, repositoryHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.AppProject), args.Error(1)
+}
+
+func (m *MockArgoCDService) RefreshApplication(ctx context.Context, name string, hard bool) error {
+	args := m.Called(ctx, name, hard)
+	return args.Error(0)
+}
+
+func (m *MockArgoCDService) SuspendApplication(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockArgoCDService) SuspendApplicationSet(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockArgoCDService) ResumeApplication(ctx context.Context, name string, automated *types.ApplicationSyncPolicyAutomated) error {
+	args := m.Called(ctx, name, automated)
+	return args.Error(0)
+}
+
+func (m *MockArgoCDService) ResumeApplicationSet(ctx context.Context, name string, automated *types.ApplicationSyncPolicyAutomated) error {
+	args := m.Called(ctx, name, automated)
+	return args.Error(0)
+}
+
+func (m *MockArgoCDService) GetApplicationResources(ctx context.Context, name string) ([]types.ApplicationResource, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]types.ApplicationResource), args.Error(1)
+}
+
+func (m *MockArgoCDService) GetApplicationHistory(ctx context.Context, name string) ([]types.ApplicationHistoryEntry, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]types.ApplicationHistoryEntry), args.Error(1)
+}
+
+func (m *MockArgoCDService) EnsureRepoHashLabel(ctx context.Context, name, repoURL string) (bool, error) {
+	args := m.Called(ctx, name, repoURL)
+	return args.Bool(0), args.Error(1)
+}
+
 // Mock other services as needed
 type MockRegistrationService struct {
 	mock.Mock
 }
 
-func (m *MockRegistrationService) CreateRegistration(ctx context.Context, req *types.RegistrationRequest) (*types.Registration, error) {
-	args := m.Called(ctx, req)
+func (m *MockRegistrationService) CreateRegistration(
+	ctx context.Context, req *types.RegistrationRequest, userInfo *types.UserInfo,
+) (*types.Registration, error) {
+	args := m.Called(ctx, req, userInfo)
 	return args.Get(0).(*types.Registration), args.Error(1)
 }
 
@@ -164,6 +398,22 @@ func (m *MockRegistrationService) DeleteRegistration(ctx context.Context, id str
 	return args.Error(0)
 }
 
+func (m *MockRegistrationService) ArchiveRegistration(ctx context.Context, id string) (*types.Registration, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.Registration), args.Error(1)
+}
+
+func (m *MockRegistrationService) UnarchiveRegistration(ctx context.Context, id string) (*types.Registration, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.Registration), args.Error(1)
+}
+
 func (m *MockRegistrationService) RegisterExistingNamespace(ctx context.Context, req *types.ExistingNamespaceRequest, userInfo *types.UserInfo) (*types.Registration, error) {
 	args := m.Called(ctx, req, userInfo)
 	return args.Get(0).(*types.Registration), args.Error(1)
@@ -212,6 +462,14 @@ func (m *MockAuthorizationService) IsAdminUser(userInfo *types.UserInfo) bool {
 	return args.Bool(0)
 }
 
+func (m *MockAuthorizationService) ExtractUserInfoFromCertificate(ctx context.Context, cert *x509.Certificate) (*types.UserInfo, error) {
+	args := m.Called(ctx, cert)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.UserInfo), args.Error(1)
+}
+
 func setupTestServer() (*Server, *MockKubernetesService, *MockArgoCDService) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.FatalLevel) // Suppress logs during tests
@@ -276,6 +534,31 @@ func TestHealthLive_Success(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "gitops-registration-service")
 }
 
+func TestBuildVersion(t *testing.T) {
+	server, _, _ := setupTestServer()
+
+	originalVersion, originalCommit, originalDate := version.Version, version.GitCommit, version.BuildDate
+	t.Cleanup(func() {
+		version.Version, version.GitCommit, version.BuildDate = originalVersion, originalCommit, originalDate
+	})
+	version.Version, version.GitCommit, version.BuildDate = "", "", ""
+
+	// Served without credentials, like the health endpoints
+	req := httptest.NewRequest("GET", "/version", http.NoBody)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response, 4)
+	assert.Equal(t, "dev", response["version"])
+	assert.Equal(t, "unknown", response["gitCommit"])
+	assert.Equal(t, "unknown", response["buildDate"])
+	assert.Equal(t, runtime.Version(), response["goVersion"])
+}
+
 func TestHealthReady_Success(t *testing.T) {
 	server, mockK8s, mockArgoCD := setupTestServer()
 
@@ -446,6 +729,44 @@ func TestSetupRoutes(t *testing.T) {
 	}
 }
 
+func TestServer_MethodNotAllowed(t *testing.T) {
+	server, _, _ := setupTestServer()
+
+	tests := []struct {
+		method string
+		path   string
+		allow  string
+	}{
+		{"PUT", "/api/v1/registrations/reg-1/status", "GET"},
+		{"PUT", "/api/v1/registrations/reg-1", "GET, PATCH, DELETE"},
+		{"PUT", "/api/v1/registrations/reg-1/", "GET, PATCH, DELETE"},
+		{"DELETE", "/api/v1/registrations", "GET, POST"},
+		{"POST", "/version", "GET"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, http.NoBody))
+
+			assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+			assert.Equal(t, tt.allow, w.Header().Get("Allow"))
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+			var response types.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "METHOD_NOT_ALLOWED", response.Error)
+			assert.Equal(t, http.StatusMethodNotAllowed, response.Code)
+		})
+	}
+
+	t.Run("Unknown paths are still 404", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest("PUT", "/api/v1/unknown", http.NoBody))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
 func TestServer_MiddlewareOrder(t *testing.T) {
 	server, _, _ := setupTestServer()
 
@@ -471,6 +792,20 @@ func TestServer_MiddlewareOrder(t *testing.T) {
 	assert.NotEmpty(t, w.Header().Get("Access-Control-Allow-Origin"))
 }
 
+func TestServer_CORSAllowsIdempotencyKey(t *testing.T) {
+	server, _, _ := setupTestServer()
+
+	req := httptest.NewRequest("OPTIONS", "/api/v1/registrations", http.NoBody)
+	req.Header.Set("Origin", "http://example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	req.Header.Set("Access-Control-Request-Headers", "Idempotency-Key")
+	w := httptest.NewRecorder()
+
+	server.router.ServeHTTP(w, req)
+
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "Idempotency-Key")
+}
+
 func TestServer_HealthEndpoints_Isolated(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.FatalLevel)
@@ -1180,3 +1515,41 @@ func TestServer_Start_Comprehensive_Coverage(t *testing.T) {
 		}
 	})
 }
+
+func TestServer_OpenAPISpec(t *testing.T) {
+	server, _, _ := setupTestServer()
+
+	req := httptest.NewRequest("GET", "/openapi.json", http.NoBody)
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var spec struct {
+		OpenAPI string                            `json:"openapi"`
+		Paths   map[string]map[string]interface{} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Equal(t, "3.0.3", spec.OpenAPI)
+
+	// Every route registered on the router must be documented
+	err := chi.Walk(server.router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		path := route
+		if len(path) > 1 {
+			path = strings.TrimSuffix(path, "/")
+		}
+
+		operations, ok := spec.Paths[path]
+		if !assert.True(t, ok, "route %s is missing from the OpenAPI spec", path) {
+			return nil
+		}
+
+		// /metrics is mounted for every method, only GET is documented
+		if path != "/metrics" {
+			assert.Contains(t, operations, strings.ToLower(method), "%s %s is missing from the OpenAPI spec", method, path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
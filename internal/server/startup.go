@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// defaultStartupTimeout bounds the initial dependency check when none is configured
+const defaultStartupTimeout = 60 * time.Second
+
+// defaultStartupRetryInterval is the pause between initial dependency checks
+const defaultStartupRetryInterval = 2 * time.Second
+
+// StartupTimeout returns how long the initial dependency check may retry, falling back to 60s
+func (s *Server) StartupTimeout() time.Duration {
+	timeout, err := time.ParseDuration(s.config.Server.StartupTimeout)
+	if err != nil || timeout <= 0 {
+		s.logger.WithField("startupTimeout", s.config.Server.StartupTimeout).
+			Warnf("Invalid startup timeout, using default %s", defaultStartupTimeout)
+		return defaultStartupTimeout
+	}
+	return timeout
+}
+
+// awaitDependencies retries the dependency check until it passes or the startup timeout expires.
+// Readiness reports starting until the first check passes; liveness is served throughout.
+func (s *Server) awaitDependencies(ctx context.Context) error {
+	timeout := s.StartupTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	interval := s.startupRetryInterval
+	if interval <= 0 {
+		interval = defaultStartupRetryInterval
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := s.checkDependencies(ctx)
+		if err == nil {
+			s.starting.Store(false)
+			s.logger.WithField("attempts", attempt).Info("Dependencies available, reporting ready")
+			return nil
+		}
+		s.logger.WithError(err).WithField("attempt", attempt).Warn("Dependencies not available yet")
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("dependencies not available after %s: %w", timeout, err)
+		}
+	}
+}
+
+// writeStartingResponse reports that the initial dependency check has not passed yet
+func (s *Server) writeStartingResponse(w http.ResponseWriter) {
+	response := map[string]interface{}{
+		"status":    "starting",
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	}
+
+	w.WriteHeader(http.StatusServiceUnavailable)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.WithError(err).Error("Failed to encode starting response")
+	}
+}
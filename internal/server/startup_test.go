@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_AwaitDependencies(t *testing.T) {
+	t.Run("Readiness flips once dependencies become healthy", func(t *testing.T) {
+		server, mockK8s, mockArgoCD := setupTestServer()
+		server.starting.Store(true)
+		server.startupRetryInterval = time.Millisecond
+
+		mockK8s.On("HealthCheck", mock.Anything).Return(assert.AnError).Twice()
+		mockK8s.On("HealthCheck", mock.Anything).Return(nil)
+		mockArgoCD.On("HealthCheck", mock.Anything).Return(nil)
+
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest("GET", "/health/ready", http.NoBody))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), "starting")
+
+		w = httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest("GET", "/health/live", http.NoBody))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		require.NoError(t, server.awaitDependencies(context.Background()))
+		assert.False(t, server.starting.Load())
+		mockK8s.AssertNumberOfCalls(t, "HealthCheck", 3)
+
+		w = httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest("GET", "/health/ready", http.NoBody))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "ready")
+	})
+
+	t.Run("Startup fails when dependencies stay unavailable", func(t *testing.T) {
+		server, mockK8s, _ := setupTestServer()
+		server.starting.Store(true)
+		server.startupRetryInterval = time.Millisecond
+		server.config.Server.StartupTimeout = "50ms"
+
+		mockK8s.On("HealthCheck", mock.Anything).Return(assert.AnError)
+
+		err := server.awaitDependencies(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "dependencies not available after 50ms")
+		assert.True(t, server.starting.Load())
+	})
+}
+
+func TestServer_StartupTimeout(t *testing.T) {
+	server, _, _ := setupTestServer()
+
+	server.config.Server.StartupTimeout = "90s"
+	assert.Equal(t, 90*time.Second, server.StartupTimeout())
+
+	server.config.Server.StartupTimeout = ""
+	assert.Equal(t, defaultStartupTimeout, server.StartupTimeout())
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+)
+
+// buildTLSConfig returns the TLS settings for serving HTTPS, or nil when no certificate is configured.
+// With client auth enabled, certificates are verified against the client CA when presented so that
+// callers without one can still authenticate with a Bearer token.
+func buildTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	if cfg.CertFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if !cfg.ClientAuth {
+		return tlsConfig, nil
+	}
+
+	caPEM, err := os.ReadFile(filepath.Clean(cfg.ClientCAFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("client CA file %s contains no PEM certificates", cfg.ClientCAFile)
+	}
+
+	tlsConfig.ClientCAs = clientCAs
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	return tlsConfig, nil
+}
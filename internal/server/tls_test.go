@@ -0,0 +1,99 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCA generates a self-signed CA certificate and writes it as PEM into dir
+func writeTestCA(t *testing.T, dir string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-client-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	return path
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	t.Run("disabled without certificate", func(t *testing.T) {
+		tlsConfig, err := buildTLSConfig(config.TLSConfig{})
+		require.NoError(t, err)
+		assert.Nil(t, tlsConfig)
+	})
+
+	t.Run("server TLS without client auth", func(t *testing.T) {
+		tlsConfig, err := buildTLSConfig(config.TLSConfig{CertFile: "tls.crt", KeyFile: "tls.key"})
+		require.NoError(t, err)
+		require.NotNil(t, tlsConfig)
+		assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+		assert.Equal(t, tls.NoClientCert, tlsConfig.ClientAuth)
+		assert.Nil(t, tlsConfig.ClientCAs)
+	})
+
+	t.Run("client auth verifies presented certificates", func(t *testing.T) {
+		caFile := writeTestCA(t, t.TempDir())
+
+		tlsConfig, err := buildTLSConfig(config.TLSConfig{
+			CertFile:     "tls.crt",
+			KeyFile:      "tls.key",
+			ClientCAFile: caFile,
+			ClientAuth:   true,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, tlsConfig)
+		assert.Equal(t, tls.VerifyClientCertIfGiven, tlsConfig.ClientAuth)
+		assert.NotNil(t, tlsConfig.ClientCAs)
+	})
+
+	t.Run("missing client CA file", func(t *testing.T) {
+		_, err := buildTLSConfig(config.TLSConfig{
+			CertFile:     "tls.crt",
+			KeyFile:      "tls.key",
+			ClientCAFile: filepath.Join(t.TempDir(), "missing.pem"),
+			ClientAuth:   true,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read client CA file")
+	})
+
+	t.Run("client CA file without certificates", func(t *testing.T) {
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+
+		_, err := buildTLSConfig(config.TLSConfig{
+			CertFile:     "tls.crt",
+			KeyFile:      "tls.key",
+			ClientCAFile: caFile,
+			ClientAuth:   true,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "contains no PEM certificates")
+	})
+}
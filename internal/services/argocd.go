@@ -2,27 +2,72 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/konflux-ci/gitops-registration-service/internal/config"
 	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/konflux-ci/gitops-registration-service/internal/version"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 )
 
+// ApplicationRefreshAnnotation requests a normal or hard refresh of an ArgoCD Application
+const ApplicationRefreshAnnotation = "argocd.argoproj.io/refresh"
+
+// ApplicationNotFoundError is returned when an operation targets an ArgoCD Application that does not exist
+type ApplicationNotFoundError struct {
+	Application string
+}
+
+func (e *ApplicationNotFoundError) Error() string {
+	return fmt.Sprintf("application %s not found", e.Application)
+}
+
+// RepositoryUnreachableError is returned when ArgoCD cannot connect to a repository with its credentials
+type RepositoryUnreachableError struct {
+	Repository string
+	Reason     string
+}
+
+func (e *RepositoryUnreachableError) Error() string {
+	return fmt.Sprintf("repository %s is not reachable by ArgoCD: %s", e.Repository, e.Reason)
+}
+
+// ArgoCDNamespaceMissingError is returned when the namespace ArgoCD is configured to run in does not exist
+type ArgoCDNamespaceMissingError struct {
+	Namespace string
+}
+
+func (e *ArgoCDNamespaceMissingError) Error() string {
+	return fmt.Sprintf("ArgoCD namespace %s does not exist, check the argocd.namespace setting (ARGOCD_NAMESPACE)", e.Namespace)
+}
+
 // argoCDService is the real implementation of ArgoCDService
 type argoCDService struct {
-	client    dynamic.Interface
-	cfg       *config.Config
-	logger    *logrus.Logger
-	namespace string
+	client     dynamic.Interface
+	discovery  discovery.DiscoveryInterface
+	cfg        *config.Config
+	logger     *logrus.Logger
+	namespace  string
+	httpClient *http.Client
 }
 
+// appProjectPageSize is the number of AppProjects requested per list call
+const appProjectPageSize int64 = 100
+
 // ArgoCD CRD GroupVersionResources
 var (
 	appProjectGVR = schema.GroupVersionResource{
@@ -36,8 +81,30 @@ var (
 		Version:  "v1alpha1",
 		Resource: "applications",
 	}
+
+	applicationSetGVR = schema.GroupVersionResource{
+		Group:    "argoproj.io",
+		Version:  "v1alpha1",
+		Resource: "applicationsets",
+	}
+
+	secretGVR = schema.GroupVersionResource{
+		Version:  "v1",
+		Resource: "secrets",
+	}
+
+	namespaceGVR = schema.GroupVersionResource{
+		Version:  "v1",
+		Resource: "namespaces",
+	}
 )
 
+// clusterSecretSelector selects the Secrets ArgoCD uses to register destination clusters
+const clusterSecretSelector = "argocd.argoproj.io/secret-type=cluster"
+
+// InClusterName is the name ArgoCD gives the cluster it runs in
+const InClusterName = "in-cluster"
+
 // NewArgoCDServiceReal creates a new real ArgoCDService implementation
 func NewArgoCDServiceReal(cfg *config.Config, logger *logrus.Logger) (ArgoCDService, error) {
 	factory := &InClusterArgoCDFactory{}
@@ -58,11 +125,25 @@ func NewArgoCDServiceWithFactory(cfg *config.Config, logger *logrus.Logger, fact
 		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
 	}
 
+	// Create discovery client using factory
+	discoveryClient, err := factory.CreateDiscoveryClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	// ArgoCD is typically installed in the argocd namespace
+	namespace := "argocd"
+	if cfg != nil && cfg.ArgoCD.Namespace != "" {
+		namespace = cfg.ArgoCD.Namespace
+	}
+
 	return &argoCDService{
-		client:    client,
-		cfg:       cfg,
-		logger:    logger,
-		namespace: "argocd", // ArgoCD is typically installed in the argocd namespace
+		client:     client,
+		discovery:  discoveryClient,
+		cfg:        cfg,
+		logger:     logger,
+		namespace:  namespace,
+		httpClient: &http.Client{},
 	}, nil
 }
 
@@ -78,6 +159,9 @@ func (a *argoCDService) CreateAppProject(ctx context.Context, project *types.App
 			a.logger.WithField("project", project.Name).Info("AppProject already exists")
 			return nil
 		}
+		if isNamespaceNotFound(err) {
+			return &ArgoCDNamespaceMissingError{Namespace: a.namespace}
+		}
 		return fmt.Errorf("failed to create AppProject %s: %w", project.Name, err)
 	}
 
@@ -87,30 +171,61 @@ func (a *argoCDService) CreateAppProject(ctx context.Context, project *types.App
 
 // buildProjectSpec creates the spec section for an AppProject
 func (a *argoCDService) buildProjectSpec(project *types.AppProject) map[string]interface{} {
+	destinations := make([]interface{}, len(project.Destinations))
+	for i, destination := range project.Destinations {
+		destinations[i] = map[string]interface{}{
+			"namespace": destination.Namespace,
+			"server":    destination.Server,
+		}
+	}
+
 	spec := map[string]interface{}{
-		"sourceRepos": project.SourceRepos,
-		"destinations": []interface{}{
-			map[string]interface{}{
-				"namespace": project.Destinations[0].Namespace,
-				"server":    project.Destinations[0].Server,
-			},
-		},
-		"roles": []interface{}{
-			map[string]interface{}{
-				"name": "tenant-role",
-				"policies": []string{
-					fmt.Sprintf("p, proj:%s:tenant-role, applications, sync, %s/*, allow", project.Name, project.Name),
-					fmt.Sprintf("p, proj:%s:tenant-role, applications, get, %s/*, allow", project.Name, project.Name),
-					fmt.Sprintf("p, proj:%s:tenant-role, applications, update, %s/*, allow", project.Name, project.Name),
-				},
-			},
-		},
+		"sourceRepos":  project.SourceRepos,
+		"destinations": destinations,
+		"roles":        []interface{}{a.buildProjectRole(project.Name)},
 	}
 
 	a.addResourceRestrictions(spec, project)
+	if len(project.SyncWindows) > 0 {
+		spec["syncWindows"] = convertSyncWindows(project.SyncWindows)
+	}
 	return spec
 }
 
+// convertSyncWindows renders sync windows in the shape of the AppProject spec.syncWindows field
+func convertSyncWindows(windows []types.SyncWindow) []interface{} {
+	result := make([]interface{}, len(windows))
+	for i, window := range windows {
+		entry := map[string]interface{}{
+			"kind":     window.Kind,
+			"schedule": window.Schedule,
+			"duration": window.Duration,
+		}
+		if len(window.Applications) > 0 {
+			applications := make([]interface{}, len(window.Applications))
+			for j, application := range window.Applications {
+				applications[j] = application
+			}
+			entry["applications"] = applications
+		}
+		result[i] = entry
+	}
+	return result
+}
+
+// buildProjectRole renders the configured project role, falling back to the built-in tenant-role
+func (a *argoCDService) buildProjectRole(projectName string) map[string]interface{} {
+	role := config.DefaultProjectRole()
+	if a.cfg != nil && a.cfg.ArgoCD.ProjectRole.Name != "" {
+		role = a.cfg.ArgoCD.ProjectRole
+	}
+
+	return map[string]interface{}{
+		"name":     role.Name,
+		"policies": role.RenderPolicies(projectName),
+	}
+}
+
 // addResourceRestrictions adds resource allow/deny lists to the project spec
 func (a *argoCDService) addResourceRestrictions(spec map[string]interface{}, project *types.AppProject) {
 	switch {
@@ -156,7 +271,7 @@ func (a *argoCDService) buildDefaultResourceWhitelist() []interface{} {
 
 // buildAppProjectResource creates the full AppProject unstructured resource
 func (a *argoCDService) buildAppProjectResource(project *types.AppProject, spec map[string]interface{}) *unstructured.Unstructured {
-	return &unstructured.Unstructured{
+	resource := &unstructured.Unstructured{
 		Object: map[string]interface{}{
 			"apiVersion": "argoproj.io/v1alpha1",
 			"kind":       "AppProject",
@@ -168,10 +283,13 @@ func (a *argoCDService) buildAppProjectResource(project *types.AppProject, spec
 					"app.kubernetes.io/managed-by": "gitops-registration-service",
 					"gitops.io/tenant":             project.Destinations[0].Namespace,
 				},
+				"annotations": createdByAnnotations(),
 			},
 			"spec": spec,
 		},
 	}
+	setOwnerReferences(resource, project.OwnerReferences)
+	return resource
 }
 
 func (a *argoCDService) convertResourceListToInterface(resources []types.AppProjectResource) []interface{} {
@@ -209,8 +327,33 @@ func (a *argoCDService) DeleteAppProject(ctx context.Context, name string) error
 func (a *argoCDService) CreateApplication(ctx context.Context, app *types.Application) error {
 	a.logger.WithField("application", app.Name).Info("Creating ArgoCD Application")
 
-	// Build Application resource - no kustomize needed since namespaces match
-	application := &unstructured.Unstructured{
+	application := a.buildApplicationResource(app)
+
+	_, err := a.client.Resource(applicationGVR).Namespace(a.namespace).Create(ctx, application, metav1.CreateOptions{})
+	if err != nil {
+		if errors.IsAlreadyExists(err) {
+			a.logger.WithField("application", app.Name).Info("Application already exists")
+			return nil
+		}
+		return fmt.Errorf("failed to create Application %s: %w", app.Name, err)
+	}
+
+	a.logger.WithField("application", app.Name).Info("Successfully created ArgoCD Application")
+	return nil
+}
+
+// buildApplicationResource creates the full Application unstructured resource
+func (a *argoCDService) buildApplicationResource(app *types.Application) *unstructured.Unstructured {
+	syncPolicy := buildSyncPolicyMap(app.SyncPolicy)
+	source := map[string]interface{}{
+		"repoURL":        app.Source.RepoURL,
+		"targetRevision": app.Source.TargetRevision,
+		"path":           app.Source.Path,
+	}
+	setSourceTools(source, app.Source.Helm, app.Source.Kustomize)
+
+	// No kustomize needed since namespaces match
+	resource := &unstructured.Unstructured{
 		Object: map[string]interface{}{
 			"apiVersion": "argoproj.io/v1alpha1",
 			"kind":       "Application",
@@ -222,50 +365,409 @@ func (a *argoCDService) CreateApplication(ctx context.Context, app *types.Applic
 					"app.kubernetes.io/managed-by": "gitops-registration-service",
 					"gitops.io/tenant":             app.Destination.Namespace,
 				},
+				"annotations": withNotificationSubscriptions(createdByAnnotations(), app.Notifications),
 			},
 			"spec": map[string]interface{}{
 				"project": app.Project,
-				"source": map[string]interface{}{
-					"repoURL":        app.Source.RepoURL,
-					"targetRevision": app.Source.TargetRevision,
-					"path":           app.Source.Path,
-				},
+				"source":  source,
 				"destination": map[string]interface{}{
 					"server":    app.Destination.Server,
 					"namespace": app.Destination.Namespace,
 				},
-				"syncPolicy": map[string]interface{}{
-					"automated": map[string]interface{}{
-						"prune":    true,
-						"selfHeal": true,
-					},
-					"syncOptions": []interface{}{
-						"CreateNamespace=false", // We create namespaces separately
-						"PrunePropagationPolicy=background",
-						"PruneLast=true",
-					},
-				},
+				"syncPolicy": syncPolicy,
 			},
 		},
 	}
+	setOwnerReferences(resource, app.OwnerReferences)
+	return resource
+}
 
-	_, err := a.client.Resource(applicationGVR).Namespace(a.namespace).Create(ctx, application, metav1.CreateOptions{})
+// setSourceTools adds the Helm or Kustomize overrides of an Application source, leaving empty fields unset
+func setSourceTools(source map[string]interface{}, helm *types.ApplicationSourceHelm,
+	kustomize *types.ApplicationSourceKustomize) {
+	if helm != nil {
+		helmSpec := map[string]interface{}{}
+		if helm.Values != "" {
+			helmSpec["values"] = helm.Values
+		}
+		if len(helm.Parameters) > 0 {
+			parameters := make([]interface{}, 0, len(helm.Parameters))
+			for _, parameter := range helm.Parameters {
+				parameters = append(parameters, map[string]interface{}{
+					"name":  parameter.Name,
+					"value": parameter.Value,
+				})
+			}
+			helmSpec["parameters"] = parameters
+		}
+		source["helm"] = helmSpec
+	}
+
+	if kustomize != nil {
+		kustomizeSpec := map[string]interface{}{}
+		if kustomize.NamePrefix != "" {
+			kustomizeSpec["namePrefix"] = kustomize.NamePrefix
+		}
+		if len(kustomize.Images) > 0 {
+			images := make([]interface{}, 0, len(kustomize.Images))
+			for _, image := range kustomize.Images {
+				images = append(images, image)
+			}
+			kustomizeSpec["images"] = images
+		}
+		source["kustomize"] = kustomizeSpec
+	}
+}
+
+// buildSyncPolicyMap returns the sync policy shared by generated Applications; without an automated
+// policy the Application is only synced on request
+func buildSyncPolicyMap(policy types.ApplicationSyncPolicy) map[string]interface{} {
+	syncPolicy := map[string]interface{}{
+		"syncOptions": []interface{}{
+			"CreateNamespace=false", // We create namespaces separately
+			"PrunePropagationPolicy=background",
+			"PruneLast=true",
+		},
+	}
+	if automated := policy.Automated; automated != nil {
+		syncPolicy["automated"] = map[string]interface{}{
+			"prune":    automated.Prune,
+			"selfHeal": automated.SelfHeal,
+		}
+	}
+	if retry := policy.Retry; retry != nil {
+		syncPolicy["retry"] = buildRetryPolicy(retry)
+	}
+	return syncPolicy
+}
+
+// CreateApplicationSet creates an ArgoCD ApplicationSet using a git directory generator
+func (a *argoCDService) CreateApplicationSet(ctx context.Context, appSet *types.ApplicationSet) error {
+	a.logger.WithField("applicationSet", appSet.Name).Info("Creating ArgoCD ApplicationSet")
+
+	resource := a.buildApplicationSetResource(appSet)
+
+	_, err := a.client.Resource(applicationSetGVR).Namespace(a.namespace).Create(ctx, resource, metav1.CreateOptions{})
 	if err != nil {
 		if errors.IsAlreadyExists(err) {
-			a.logger.WithField("application", app.Name).Info("Application already exists")
+			a.logger.WithField("applicationSet", appSet.Name).Info("ApplicationSet already exists")
 			return nil
 		}
-		return fmt.Errorf("failed to create Application %s: %w", app.Name, err)
+		return fmt.Errorf("failed to create ApplicationSet %s: %w", appSet.Name, err)
 	}
 
-	a.logger.WithField("application", app.Name).Info("Successfully created ArgoCD Application")
+	a.logger.WithField("applicationSet", appSet.Name).Info("Successfully created ArgoCD ApplicationSet")
 	return nil
 }
 
+// buildApplicationSetResource creates the full ApplicationSet unstructured resource.
+// Each matched directory becomes an Application named after the tenant and directory.
+func (a *argoCDService) buildApplicationSetResource(appSet *types.ApplicationSet) *unstructured.Unstructured {
+	source := map[string]interface{}{
+		"repoURL":        appSet.RepoURL,
+		"targetRevision": appSet.TargetRevision,
+		"path":           "{{path}}",
+	}
+	setSourceTools(source, appSet.Helm, appSet.Kustomize)
+
+	resource := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "ApplicationSet",
+			"metadata": map[string]interface{}{
+				"name":      appSet.Name,
+				"namespace": a.namespace,
+				"labels": map[string]interface{}{
+					"gitops.io/managed-by":         "gitops-registration-service",
+					"app.kubernetes.io/managed-by": "gitops-registration-service",
+					"gitops.io/tenant":             appSet.Destination.Namespace,
+				},
+				"annotations": createdByAnnotations(),
+			},
+			"spec": map[string]interface{}{
+				"generators": []interface{}{
+					map[string]interface{}{
+						"git": map[string]interface{}{
+							"repoURL":  appSet.RepoURL,
+							"revision": appSet.TargetRevision,
+							"directories": []interface{}{
+								map[string]interface{}{"path": appSet.DirectoryPath},
+							},
+						},
+					},
+				},
+				"template": map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"name": fmt.Sprintf("%s-{{path.basename}}", appSet.Destination.Namespace),
+						"labels": map[string]interface{}{
+							"gitops.io/managed-by": "gitops-registration-service",
+							"gitops.io/tenant":     appSet.Destination.Namespace,
+						},
+						"annotations": withNotificationSubscriptions(createdByAnnotations(), appSet.Notifications),
+					},
+					"spec": map[string]interface{}{
+						"project": appSet.Project,
+						"source":  source,
+						"destination": map[string]interface{}{
+							"server":    appSet.Destination.Server,
+							"namespace": appSet.Destination.Namespace,
+						},
+						"syncPolicy": buildSyncPolicyMap(appSet.SyncPolicy),
+					},
+				},
+			},
+		},
+	}
+	setOwnerReferences(resource, appSet.OwnerReferences)
+	return resource
+}
+
+// createdByAnnotations records the version of this service on the resources it creates
+func createdByAnnotations() map[string]interface{} {
+	return map[string]interface{}{
+		CreatedByVersionAnnotation: version.Get(),
+	}
+}
+
+// withNotificationSubscriptions adds a notifications.argoproj.io/subscribe.<trigger>.<service> annotation per
+// subscription, listing its recipients separated by ;
+func withNotificationSubscriptions(annotations map[string]interface{},
+	subscriptions []types.NotificationSubscription) map[string]interface{} {
+	for _, subscription := range subscriptions {
+		key := config.NotificationSubscriptionKey(subscription.Trigger, subscription.Service)
+		annotations[key] = strings.Join(subscription.Recipients, ";")
+	}
+	return annotations
+}
+
+// setOwnerReferences sets metadata.ownerReferences so the resource is garbage collected with its owner
+func setOwnerReferences(resource *unstructured.Unstructured, owners []types.OwnerReference) {
+	if len(owners) == 0 {
+		return
+	}
+
+	references := make([]metav1.OwnerReference, 0, len(owners))
+	for _, owner := range owners {
+		references = append(references, metav1.OwnerReference{
+			APIVersion: owner.APIVersion,
+			Kind:       owner.Kind,
+			Name:       owner.Name,
+			UID:        k8stypes.UID(owner.UID),
+		})
+	}
+	resource.SetOwnerReferences(references)
+}
+
+// buildRetryPolicy converts a sync retry policy into its unstructured form
+func buildRetryPolicy(retry *types.ApplicationSyncPolicyRetry) map[string]interface{} {
+	policy := map[string]interface{}{
+		"limit": retry.Limit,
+	}
+	if retry.Backoff != nil {
+		backoff := map[string]interface{}{}
+		if retry.Backoff.Duration != "" {
+			backoff["duration"] = retry.Backoff.Duration
+		}
+		if retry.Backoff.Factor != 0 {
+			backoff["factor"] = retry.Backoff.Factor
+		}
+		if retry.Backoff.MaxDuration != "" {
+			backoff["maxDuration"] = retry.Backoff.MaxDuration
+		}
+		policy["backoff"] = backoff
+	}
+	return policy
+}
+
 func (a *argoCDService) DeleteApplication(ctx context.Context, name string) error {
 	return a.deleteResource(ctx, name, "Application", applicationGVR)
 }
 
+// DeleteApplicationSet removes an ApplicationSet; ArgoCD deletes the Applications it generated
+func (a *argoCDService) DeleteApplicationSet(ctx context.Context, name string) error {
+	return a.deleteResource(ctx, name, "ApplicationSet", applicationSetGVR)
+}
+
+// TenantApplicationName returns the name of the Application, or of the ApplicationSet when
+// registration.useApplicationSet is enabled, that the service creates for a tenant namespace
+func TenantApplicationName(cfg *config.Config, namespace string) string {
+	if cfg != nil && cfg.Registration.UseApplicationSet {
+		return fmt.Sprintf("%s-appset", namespace)
+	}
+	return fmt.Sprintf("%s-app", namespace)
+}
+
+// deleteTenantApplication removes the Application or ApplicationSet created for a tenant namespace
+func deleteTenantApplication(ctx context.Context, argocd ArgoCDService, cfg *config.Config, namespace string) error {
+	name := TenantApplicationName(cfg, namespace)
+	if cfg != nil && cfg.Registration.UseApplicationSet {
+		return argocd.DeleteApplicationSet(ctx, name)
+	}
+	return argocd.DeleteApplication(ctx, name)
+}
+
+// UpdateApplicationSource updates the target revision and path of an existing Application.
+// Empty fields in source are left unchanged; the repository URL is never modified.
+func (a *argoCDService) UpdateApplicationSource(ctx context.Context, name string, source *types.ApplicationSource) error {
+	a.logger.WithField("application", name).Info("Updating ArgoCD Application source")
+
+	app, err := a.client.Resource(applicationGVR).Namespace(a.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return &ApplicationNotFoundError{Application: name}
+		}
+		return fmt.Errorf("failed to get Application %s: %w", name, err)
+	}
+
+	if source.TargetRevision != "" {
+		if err := unstructured.SetNestedField(app.Object, source.TargetRevision, "spec", "source", "targetRevision"); err != nil {
+			return fmt.Errorf("failed to set target revision on Application %s: %w", name, err)
+		}
+	}
+	if source.Path != "" {
+		if err := unstructured.SetNestedField(app.Object, source.Path, "spec", "source", "path"); err != nil {
+			return fmt.Errorf("failed to set path on Application %s: %w", name, err)
+		}
+	}
+
+	_, err = a.client.Resource(applicationGVR).Namespace(a.namespace).Update(ctx, app, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update Application %s: %w", name, err)
+	}
+
+	a.logger.WithField("application", name).Info("Successfully updated ArgoCD Application source")
+	return nil
+}
+
+// RefreshApplication asks ArgoCD to re-read an Application's source without syncing it, by setting the
+// refresh annotation ArgoCD removes once done. A hard refresh also bypasses the manifest cache.
+func (a *argoCDService) RefreshApplication(ctx context.Context, name string, hard bool) error {
+	refresh := "normal"
+	if hard {
+		refresh = "hard"
+	}
+	a.logger.WithFields(logrus.Fields{"application": name, "refresh": refresh}).Info("Refreshing ArgoCD Application")
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{ApplicationRefreshAnnotation: refresh},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build refresh patch for Application %s: %w", name, err)
+	}
+
+	_, err = a.client.Resource(applicationGVR).Namespace(a.namespace).Patch(ctx, name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return &ApplicationNotFoundError{Application: name}
+		}
+		return fmt.Errorf("failed to refresh Application %s: %w", name, err)
+	}
+	return nil
+}
+
+// SuspendApplication stops ArgoCD from syncing an Application by removing its automated sync policy. The
+// deployed resources are left as they are.
+func (a *argoCDService) SuspendApplication(ctx context.Context, name string) error {
+	a.logger.WithField("application", name).Info("Suspending ArgoCD Application")
+	return a.patchAutomatedSync(ctx, "Application", applicationGVR, name, nil, "spec", "syncPolicy", "automated")
+}
+
+// SuspendApplicationSet removes the automated sync policy from an ApplicationSet's template, which the
+// ApplicationSet controller then rolls out to every Application it generated
+func (a *argoCDService) SuspendApplicationSet(ctx context.Context, name string) error {
+	a.logger.WithField("applicationSet", name).Info("Suspending ArgoCD ApplicationSet")
+	return a.patchAutomatedSync(ctx, "ApplicationSet", applicationSetGVR, name, nil,
+		"spec", "template", "spec", "syncPolicy", "automated")
+}
+
+// ResumeApplication restores the automated sync policy of an Application suspended by SuspendApplication
+func (a *argoCDService) ResumeApplication(ctx context.Context, name string, automated *types.ApplicationSyncPolicyAutomated) error {
+	a.logger.WithField("application", name).Info("Resuming ArgoCD Application")
+	return a.patchAutomatedSync(ctx, "Application", applicationGVR, name, automated, "spec", "syncPolicy", "automated")
+}
+
+// ResumeApplicationSet restores the automated sync policy of an ApplicationSet suspended by
+// SuspendApplicationSet
+func (a *argoCDService) ResumeApplicationSet(ctx context.Context, name string, automated *types.ApplicationSyncPolicyAutomated) error {
+	a.logger.WithField("applicationSet", name).Info("Resuming ArgoCD ApplicationSet")
+	return a.patchAutomatedSync(ctx, "ApplicationSet", applicationSetGVR, name, automated,
+		"spec", "template", "spec", "syncPolicy", "automated")
+}
+
+// patchAutomatedSync merge-patches the automated sync policy found at path, removing it when automated is nil
+func (a *argoCDService) patchAutomatedSync(ctx context.Context, resourceType string, gvr schema.GroupVersionResource,
+	name string, automated *types.ApplicationSyncPolicyAutomated, path ...string) error {
+	var value interface{}
+	if automated != nil {
+		value = map[string]interface{}{"prune": automated.Prune, "selfHeal": automated.SelfHeal}
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		value = map[string]interface{}{path[i]: value}
+	}
+
+	patch, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to build sync policy patch for %s %s: %w", resourceType, name, err)
+	}
+
+	_, err = a.client.Resource(gvr).Namespace(a.namespace).Patch(ctx, name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return &ApplicationNotFoundError{Application: name}
+		}
+		return fmt.Errorf("failed to update sync policy of %s %s: %w", resourceType, name, err)
+	}
+	return nil
+}
+
+// suspendTenantApplication suspends the Application or ApplicationSet created for a tenant namespace
+func suspendTenantApplication(ctx context.Context, argocd ArgoCDService, cfg *config.Config, namespace string) error {
+	name := TenantApplicationName(cfg, namespace)
+	if cfg != nil && cfg.Registration.UseApplicationSet {
+		return argocd.SuspendApplicationSet(ctx, name)
+	}
+	return argocd.SuspendApplication(ctx, name)
+}
+
+// resumeTenantApplication restores automated sync on the Application or ApplicationSet created for a tenant
+// namespace
+func resumeTenantApplication(ctx context.Context, argocd ArgoCDService, cfg *config.Config, namespace string,
+	automated *types.ApplicationSyncPolicyAutomated) error {
+	name := TenantApplicationName(cfg, namespace)
+	if cfg != nil && cfg.Registration.UseApplicationSet {
+		return argocd.ResumeApplicationSet(ctx, name, automated)
+	}
+	return argocd.ResumeApplication(ctx, name, automated)
+}
+
+// ApplicationExists reports whether an ArgoCD Application with the given name exists
+func (a *argoCDService) ApplicationExists(ctx context.Context, name string) (bool, error) {
+	return a.resourceExists(ctx, name, "Application", applicationGVR)
+}
+
+// AppProjectExists reports whether an ArgoCD AppProject with the given name exists
+func (a *argoCDService) AppProjectExists(ctx context.Context, name string) (bool, error) {
+	return a.resourceExists(ctx, name, "AppProject", appProjectGVR)
+}
+
+// ApplicationSetExists reports whether an ArgoCD ApplicationSet with the given name exists
+func (a *argoCDService) ApplicationSetExists(ctx context.Context, name string) (bool, error) {
+	return a.resourceExists(ctx, name, "ApplicationSet", applicationSetGVR)
+}
+
+func (a *argoCDService) resourceExists(ctx context.Context, name, resourceType string, gvr schema.GroupVersionResource) (bool, error) {
+	_, err := a.client.Resource(gvr).Namespace(a.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get %s %s: %w", resourceType, name, err)
+	}
+	return true, nil
+}
+
 // GetApplicationStatus retrieves the status of an ArgoCD Application
 func (a *argoCDService) GetApplicationStatus(ctx context.Context, name string) (*types.ApplicationStatus, error) {
 	a.logger.WithField("application", name).Info("Getting ArgoCD Application status")
@@ -278,7 +780,28 @@ func (a *argoCDService) GetApplicationStatus(ctx context.Context, name string) (
 		return nil, fmt.Errorf("failed to get Application %s: %w", name, err)
 	}
 
-	// Extract status information
+	return applicationStatusFromUnstructured(app), nil
+}
+
+// ListApplicationStatuses returns the status of every Application created by this service, keyed by
+// Application name, using a single list call
+func (a *argoCDService) ListApplicationStatuses(ctx context.Context) (map[string]*types.ApplicationStatus, error) {
+	apps, err := a.client.Resource(applicationGVR).Namespace(a.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: ManagedByLabelSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list managed Applications: %w", err)
+	}
+
+	statuses := make(map[string]*types.ApplicationStatus, len(apps.Items))
+	for i := range apps.Items {
+		statuses[apps.Items[i].GetName()] = applicationStatusFromUnstructured(&apps.Items[i])
+	}
+	return statuses, nil
+}
+
+// applicationStatusFromUnstructured extracts health, sync and last operation time from an Application resource
+func applicationStatusFromUnstructured(app *unstructured.Unstructured) *types.ApplicationStatus {
 	status := &types.ApplicationStatus{
 		Phase:   "Unknown",
 		Health:  "Unknown",
@@ -291,17 +814,105 @@ func (a *argoCDService) GetApplicationStatus(ctx context.Context, name string) (
 		status.Health = healthStatus
 	}
 
-	// Try to extract sync status and last operation time
+	if syncStatus, found, err := unstructured.NestedString(app.Object, "status", "sync", "status"); err == nil && found {
+		status.Sync = syncStatus
+	}
+
+	// Try to extract last operation time
 	if operationTime, found, err := unstructured.NestedString(app.Object, "status", "operationState", "finishedAt"); err == nil && found {
 		if timestamp, err := time.Parse(time.RFC3339, operationTime); err == nil {
 			status.LastSyncTime = timestamp
 		}
 	}
 
-	return status, nil
+	return status
+}
+
+// GetApplicationResources returns the resources listed in an ArgoCD Application's status.
+// A missing Application is returned as a wrapped NotFound error.
+func (a *argoCDService) GetApplicationResources(ctx context.Context, name string) ([]types.ApplicationResource, error) {
+	app, err := a.client.Resource(applicationGVR).Namespace(a.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Application %s: %w", name, err)
+	}
+
+	entries, _, err := unstructured.NestedSlice(app.Object, "status", "resources")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resources of Application %s: %w", name, err)
+	}
+
+	resources := make([]types.ApplicationResource, 0, len(entries))
+	for _, e := range entries {
+		entry, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		resource := types.ApplicationResource{}
+		resource.Group, _, _ = unstructured.NestedString(entry, "group")
+		resource.Version, _, _ = unstructured.NestedString(entry, "version")
+		resource.Kind, _, _ = unstructured.NestedString(entry, "kind")
+		resource.Namespace, _, _ = unstructured.NestedString(entry, "namespace")
+		resource.Name, _, _ = unstructured.NestedString(entry, "name")
+		resource.Health, _, _ = unstructured.NestedString(entry, "health", "status")
+		resource.Sync, _, _ = unstructured.NestedString(entry, "status")
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+// GetApplicationHistory returns the syncs recorded in an ArgoCD Application's status.history, newest first.
+// A missing Application is returned as a wrapped NotFound error.
+func (a *argoCDService) GetApplicationHistory(ctx context.Context, name string) ([]types.ApplicationHistoryEntry, error) {
+	app, err := a.client.Resource(applicationGVR).Namespace(a.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Application %s: %w", name, err)
+	}
+
+	entries, _, err := unstructured.NestedSlice(app.Object, "status", "history")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history of Application %s: %w", name, err)
+	}
+
+	history := make([]types.ApplicationHistoryEntry, 0, len(entries))
+	for _, e := range entries {
+		entry, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		item := types.ApplicationHistoryEntry{}
+		item.ID, _, _ = unstructured.NestedInt64(entry, "id")
+		item.Revision, _, _ = unstructured.NestedString(entry, "revision")
+		if deployedAt, found, _ := unstructured.NestedString(entry, "deployedAt"); found {
+			item.DeployedAt, _ = time.Parse(time.RFC3339, deployedAt)
+		}
+		if startedAt, found, _ := unstructured.NestedString(entry, "deployStartedAt"); found {
+			if timestamp, err := time.Parse(time.RFC3339, startedAt); err == nil {
+				item.DeployStartedAt = &timestamp
+			}
+		}
+		if automated, _, _ := unstructured.NestedBool(entry, "initiatedBy", "automated"); automated {
+			item.InitiatedBy = "automated"
+		} else {
+			item.InitiatedBy, _, _ = unstructured.NestedString(entry, "initiatedBy", "username")
+		}
+		history = append(history, item)
+	}
+
+	// ArgoCD appends to the history, so the newest sync is last
+	slices.Reverse(history)
+	return history, nil
 }
 
 func (a *argoCDService) HealthCheck(ctx context.Context) error {
+	if err := a.checkCRDs(); err != nil {
+		return fmt.Errorf("ArgoCD health check failed: %w", err)
+	}
+
+	if err := a.checkNamespace(ctx); err != nil {
+		return fmt.Errorf("ArgoCD health check failed: %w", err)
+	}
+
 	// Simple health check - try to list AppProjects
 	_, err := a.client.Resource(appProjectGVR).Namespace(a.namespace).List(ctx, metav1.ListOptions{Limit: 1})
 	if err != nil {
@@ -310,21 +921,344 @@ func (a *argoCDService) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
+// checkNamespace verifies that the configured ArgoCD namespace exists
+func (a *argoCDService) checkNamespace(ctx context.Context) error {
+	_, err := a.client.Resource(namespaceGVR).Get(ctx, a.namespace, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return &ArgoCDNamespaceMissingError{Namespace: a.namespace}
+		}
+		return fmt.Errorf("failed to get ArgoCD namespace %s: %w", a.namespace, err)
+	}
+	return nil
+}
+
+// isNamespaceNotFound reports whether err is the NotFound the API server returns
+// when a namespaced object is created in a namespace that does not exist
+func isNamespaceNotFound(err error) bool {
+	if !errors.IsNotFound(err) {
+		return false
+	}
+	status, ok := err.(errors.APIStatus)
+	if !ok || status.Status().Details == nil {
+		return false
+	}
+	return status.Status().Details.Kind == "namespaces"
+}
+
+// checkCRDs verifies that the AppProject and Application CRDs are served by the API server
+func (a *argoCDService) checkCRDs() error {
+	if a.discovery == nil {
+		return nil
+	}
+
+	groupVersion := appProjectGVR.GroupVersion().String()
+	resources, err := a.discovery.ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		return fmt.Errorf("ArgoCD API group %s is not available, is ArgoCD installed?: %w", groupVersion, err)
+	}
+
+	for _, gvr := range []schema.GroupVersionResource{appProjectGVR, applicationGVR} {
+		found := false
+		for _, resource := range resources.APIResources {
+			if resource.Name == gvr.Resource {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("CRD %s.%s is not installed", gvr.Resource, gvr.Group)
+		}
+	}
+	return nil
+}
+
 // CheckAppProjectConflict checks if an AppProject exists for the given repository hash
 func (a *argoCDService) CheckAppProjectConflict(ctx context.Context, repositoryHash string) (bool, error) {
-	labelSelector := fmt.Sprintf("%s=%s", RepositoryHashLabel, repositoryHash)
+	labelSelector := fmt.Sprintf("%s=%s", repositoryHashLabel(a.cfg), repositoryHash)
 
-	appProjects, err := a.client.Resource(appProjectGVR).Namespace(a.namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: labelSelector,
-	})
+	appProjects, err := a.listAppProjectsPaged(ctx, labelSelector)
 	if err != nil {
 		return false, fmt.Errorf("failed to check AppProject conflict for repository hash %s: %w", repositoryHash, err)
 	}
 
-	exists := len(appProjects.Items) > 0
+	exists := len(appProjects) > 0
 	if exists {
 		a.logger.Infof("Found existing AppProject for repository hash %s", repositoryHash)
 	}
 
 	return exists, nil
 }
+
+// FindAppProjectByRepoHash returns the AppProject labeled with the given repository hash, or nil if none exists
+func (a *argoCDService) FindAppProjectByRepoHash(ctx context.Context, repositoryHash string) (*types.AppProject, error) {
+	labelSelector := fmt.Sprintf("%s=%s", repositoryHashLabel(a.cfg), repositoryHash)
+
+	appProjects, err := a.listAppProjectsPaged(ctx, labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find AppProject for repository hash %s: %w", repositoryHash, err)
+	}
+
+	if len(appProjects) == 0 {
+		return nil, nil
+	}
+
+	project := appProjectFromUnstructured(&appProjects[0])
+	a.logger.Infof("Found AppProject %s for repository hash %s", project.Name, repositoryHash)
+	return project, nil
+}
+
+// DeleteAppProjectByRepoHash removes the AppProject labeled with the given repository hash together
+// with its tenant Application. It returns the deleted AppProject, or nil if none matched.
+func (a *argoCDService) DeleteAppProjectByRepoHash(ctx context.Context, repositoryHash string) (*types.AppProject, error) {
+	project, err := a.FindAppProjectByRepoHash(ctx, repositoryHash)
+	if err != nil || project == nil {
+		return nil, err
+	}
+
+	// Remove the Application first so ArgoCD does not block deleting its project
+	if err := deleteTenantApplication(ctx, a, a.cfg, tenantNamespace(project)); err != nil {
+		return nil, err
+	}
+	if err := a.DeleteAppProject(ctx, project.Name); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// ListManagedAppProjects returns the AppProjects created by this service
+func (a *argoCDService) ListManagedAppProjects(ctx context.Context) ([]types.AppProject, error) {
+	appProjects, err := a.listAppProjectsPaged(ctx, ManagedByLabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list managed AppProjects: %w", err)
+	}
+
+	projects := make([]types.AppProject, 0, len(appProjects))
+	for i := range appProjects {
+		projects = append(projects, *appProjectFromUnstructured(&appProjects[i]))
+	}
+	return projects, nil
+}
+
+// EnsureRepoHashLabel sets the repository hash label of an AppProject from repoURL if it is missing
+// or stale. It reports whether the AppProject was updated.
+func (a *argoCDService) EnsureRepoHashLabel(ctx context.Context, name, repoURL string) (bool, error) {
+	project, err := a.client.Resource(appProjectGVR).Namespace(a.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to get AppProject %s: %w", name, err)
+	}
+
+	repoHash := GenerateRepositoryHashWithLength(repoURL, repositoryHashLength(a.cfg))
+	hashLabel := repositoryHashLabel(a.cfg)
+	labels := project.GetLabels()
+	if labels[hashLabel] == repoHash {
+		return false, nil
+	}
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[hashLabel] = repoHash
+	project.SetLabels(labels)
+
+	if _, err := a.client.Resource(appProjectGVR).Namespace(a.namespace).Update(ctx, project, metav1.UpdateOptions{}); err != nil {
+		return false, fmt.Errorf("failed to label AppProject %s: %w", name, err)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"project":        name,
+		"repositoryHash": repoHash,
+	}).Info("Labeled AppProject with repository hash")
+	return true, nil
+}
+
+// AdoptAppProject brings an existing AppProject under management by adding the labels this service
+// uses to find and conflict-check its projects. The project's spec is left as it is.
+func (a *argoCDService) AdoptAppProject(ctx context.Context, project *types.AppProject) error {
+	existing, err := a.client.Resource(appProjectGVR).Namespace(a.namespace).Get(ctx, project.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get AppProject %s: %w", project.Name, err)
+	}
+
+	labels := existing.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	for key, value := range project.Labels {
+		labels[key] = value
+	}
+	if len(project.Destinations) > 0 {
+		labels["gitops.io/tenant"] = project.Destinations[0].Namespace
+	}
+	existing.SetLabels(labels)
+
+	if _, err := a.client.Resource(appProjectGVR).Namespace(a.namespace).Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to adopt AppProject %s: %w", project.Name, err)
+	}
+
+	a.logger.WithField("project", project.Name).Info("Adopted existing ArgoCD AppProject")
+	return nil
+}
+
+// ResolveClusterServer returns the API server URL of the ArgoCD cluster registered under name.
+// It returns a DestinationClusterNotFoundError if no cluster Secret carries that name.
+func (a *argoCDService) ResolveClusterServer(ctx context.Context, name string) (string, error) {
+	if name == InClusterName {
+		return InClusterServer, nil
+	}
+
+	secrets, err := a.client.Resource(secretGVR).Namespace(a.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: clusterSecretSelector,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list ArgoCD cluster secrets: %w", err)
+	}
+
+	for i := range secrets.Items {
+		if clusterName, _ := secretDataString(&secrets.Items[i], "name"); clusterName != name {
+			continue
+		}
+		server, err := secretDataString(&secrets.Items[i], "server")
+		if err != nil || server == "" {
+			return "", fmt.Errorf("ArgoCD cluster secret %s has no server", secrets.Items[i].GetName())
+		}
+		return server, nil
+	}
+
+	return "", &DestinationClusterNotFoundError{Cluster: name}
+}
+
+// repositoryConnectionStateSuccessful is the connection status ArgoCD reports for a reachable repository
+const repositoryConnectionStateSuccessful = "Successful"
+
+// TestRepositoryConnection asks the ArgoCD API server for the connection state of a repository.
+// When credsSecret is set it must exist in the ArgoCD namespace, since ArgoCD reads repository
+// credentials from there. It returns a RepositoryUnreachableError if ArgoCD cannot access the repository.
+func (a *argoCDService) TestRepositoryConnection(ctx context.Context, repoURL, credsSecret string) error {
+	a.logger.WithField("repository", repoURL).Info("Testing ArgoCD repository connection")
+
+	if credsSecret != "" {
+		_, err := a.client.Resource(secretGVR).Namespace(a.namespace).Get(ctx, credsSecret, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return &RepositoryUnreachableError{
+				Repository: repoURL,
+				Reason:     fmt.Sprintf("credentials secret %s not found in namespace %s", credsSecret, a.namespace),
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get repository credentials secret %s: %w", credsSecret, err)
+		}
+	}
+
+	endpoint := argoCDServerURL(a.cfg.ArgoCD.Server) + "/api/v1/repositories/" + url.PathEscape(repoURL) + "?forceRefresh=true"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("failed to build repository request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+a.cfg.ArgoCD.Token)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query ArgoCD repository: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return &RepositoryUnreachableError{Repository: repoURL, Reason: "repository is not configured in ArgoCD"}
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("ArgoCD repository query returned status %d", resp.StatusCode)
+	}
+
+	var repository struct {
+		ConnectionState struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"connectionState"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&repository); err != nil {
+		return fmt.Errorf("failed to decode ArgoCD repository: %w", err)
+	}
+
+	if state := repository.ConnectionState; state.Status != repositoryConnectionStateSuccessful {
+		reason := state.Message
+		if reason == "" {
+			reason = fmt.Sprintf("connection state %q", state.Status)
+		}
+		return &RepositoryUnreachableError{Repository: repoURL, Reason: reason}
+	}
+	return nil
+}
+
+// argoCDServerURL returns the ArgoCD API server base URL, defaulting to https when no scheme is configured
+func argoCDServerURL(server string) string {
+	server = strings.TrimSuffix(server, "/")
+	if !strings.Contains(server, "://") {
+		return "https://" + server
+	}
+	return server
+}
+
+// secretDataString decodes a base64 value from the data of an unstructured Secret
+func secretDataString(secret *unstructured.Unstructured, key string) (string, error) {
+	encoded, _, err := unstructured.NestedString(secret.Object, "data", key)
+	if err != nil {
+		return "", err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+// listAppProjectsPaged lists the AppProjects matching selector in chunks of appProjectPageSize,
+// following continue tokens so the API server never has to return every project in one response
+func (a *argoCDService) listAppProjectsPaged(ctx context.Context, selector string) ([]unstructured.Unstructured, error) {
+	var items []unstructured.Unstructured
+	opts := metav1.ListOptions{LabelSelector: selector, Limit: appProjectPageSize}
+
+	for {
+		page, err := a.client.Resource(appProjectGVR).Namespace(a.namespace).List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+
+		opts.Continue = page.GetContinue()
+		if opts.Continue == "" {
+			return items, nil
+		}
+	}
+}
+
+// appProjectFromUnstructured extracts the metadata and destinations of an AppProject resource
+func appProjectFromUnstructured(item *unstructured.Unstructured) *types.AppProject {
+	project := &types.AppProject{
+		Name:      item.GetName(),
+		Namespace: item.GetNamespace(),
+		Labels:    item.GetLabels(),
+	}
+
+	if sourceRepos, found, err := unstructured.NestedStringSlice(item.Object, "spec", "sourceRepos"); err == nil && found {
+		project.SourceRepos = sourceRepos
+	}
+
+	destinations, found, err := unstructured.NestedSlice(item.Object, "spec", "destinations")
+	if err == nil && found {
+		for _, d := range destinations {
+			destination, ok := d.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			server, _, _ := unstructured.NestedString(destination, "server")
+			namespace, _, _ := unstructured.NestedString(destination, "namespace")
+			project.Destinations = append(project.Destinations, types.AppProjectDestination{
+				Server:    server,
+				Namespace: namespace,
+			})
+		}
+	}
+
+	return project
+}
@@ -1,17 +1,34 @@
 package services
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/konflux-ci/gitops-registration-service/internal/config"
 	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/konflux-ci/gitops-registration-service/internal/version"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic"
 	fakedynamic "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
 )
 
 func TestConvertResourceListToInterface(t *testing.T) {
@@ -123,13 +140,156 @@ func TestBuildProjectSpec_BasicStructure(t *testing.T) {
 	assert.Equal(t, "test-namespace", firstDest["namespace"])
 	assert.Equal(t, "https://kubernetes.default.svc", firstDest["server"])
 
+	// Every destination is rendered, including a cluster-scoped one
+	project.Destinations = append(project.Destinations, types.AppProjectDestination{
+		Namespace: ClusterScopedDestinationNamespace,
+		Server:    "https://kubernetes.default.svc",
+	})
+	destinations = service.buildProjectSpec(project)["destinations"].([]interface{})
+	require.Len(t, destinations, 2)
+	assert.Equal(t, "", destinations[1].(map[string]interface{})["namespace"])
+
 	// Check roles structure
 	roles := spec["roles"].([]interface{})
 	assert.Len(t, roles, 1)
 
 	role := roles[0].(map[string]interface{})
 	assert.Equal(t, "tenant-role", role["name"])
-	assert.NotEmpty(t, role["policies"])
+	assert.Equal(t, []string{
+		"p, proj:test-project:tenant-role, applications, sync, test-project/*, allow",
+		"p, proj:test-project:tenant-role, applications, get, test-project/*, allow",
+		"p, proj:test-project:tenant-role, applications, update, test-project/*, allow",
+	}, role["policies"])
+}
+
+func TestBuildProjectSpec_RolePoliciesScopedPerProject(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	service := &argoCDService{logger: logger}
+
+	policiesFor := func(name string) []string {
+		project := &types.AppProject{
+			Name:         name,
+			SourceRepos:  []string{"https://github.com/test/repo"},
+			Destinations: []types.AppProjectDestination{{Namespace: name, Server: "https://kubernetes.default.svc"}},
+		}
+		roles := service.buildProjectSpec(project)["roles"].([]interface{})
+		require.Len(t, roles, 1)
+		return roles[0].(map[string]interface{})["policies"].([]string)
+	}
+
+	teamA := policiesFor("team-a")
+	teamB := policiesFor("team-b")
+	require.NotEmpty(t, teamA)
+	require.NotEmpty(t, teamB)
+
+	for _, policy := range teamA {
+		assert.Contains(t, policy, "proj:team-a:tenant-role")
+		assert.Contains(t, policy, ", team-a/*, ")
+		assert.NotContains(t, policy, "team-b")
+		assert.NotContains(t, policy, ", */")
+		assert.NotContains(t, policy, ", *, allow")
+	}
+	for _, policy := range teamB {
+		assert.Contains(t, policy, ", team-b/*, ")
+		assert.NotContains(t, policy, "team-a")
+	}
+}
+
+func TestBuildProjectSpec_ConfiguredProjectRole(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	service := &argoCDService{
+		logger: logger,
+		cfg: &config.Config{ArgoCD: config.ArgoCDConfig{ProjectRole: config.ProjectRoleConfig{
+			Name:     "sync-only",
+			Policies: []string{"applications, get, allow", "applications, sync, allow"},
+		}}},
+	}
+
+	project := &types.AppProject{
+		Name:         "team-a",
+		SourceRepos:  []string{"https://github.com/test/repo"},
+		Destinations: []types.AppProjectDestination{{Namespace: "team-a", Server: "https://kubernetes.default.svc"}},
+	}
+
+	roles := service.buildProjectSpec(project)["roles"].([]interface{})
+	require.Len(t, roles, 1)
+
+	role := roles[0].(map[string]interface{})
+	assert.Equal(t, "sync-only", role["name"])
+	assert.Equal(t, []string{
+		"p, proj:team-a:sync-only, applications, get, team-a/*, allow",
+		"p, proj:team-a:sync-only, applications, sync, team-a/*, allow",
+	}, role["policies"])
+}
+
+func TestArgoCDService_CreatedByVersionAnnotation(t *testing.T) {
+	original := version.Version
+	version.Version = "v1.2.3"
+	t.Cleanup(func() { version.Version = original })
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	service := &argoCDService{logger: logger, namespace: "argocd"}
+
+	project := &types.AppProject{
+		Name:         "team-a",
+		SourceRepos:  []string{"https://github.com/test/repo"},
+		Destinations: []types.AppProjectDestination{{Namespace: "team-a", Server: InClusterServer}},
+	}
+	appProject := service.buildAppProjectResource(project, service.buildProjectSpec(project))
+	assert.Equal(t, "v1.2.3", appProject.GetAnnotations()[CreatedByVersionAnnotation])
+
+	application := service.buildApplicationResource(&types.Application{
+		Name:        "team-a-app",
+		Project:     "team-a",
+		Source:      types.ApplicationSource{RepoURL: "https://github.com/test/repo", Path: "manifests"},
+		Destination: types.ApplicationDestination{Namespace: "team-a", Server: InClusterServer},
+	})
+	assert.Equal(t, "v1.2.3", application.GetAnnotations()[CreatedByVersionAnnotation])
+
+	version.Version = ""
+	appProject = service.buildAppProjectResource(project, service.buildProjectSpec(project))
+	assert.Equal(t, "dev", appProject.GetAnnotations()[CreatedByVersionAnnotation])
+}
+
+func TestBuildProjectSpec_SyncWindows(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	service := &argoCDService{logger: logger}
+	project := &types.AppProject{
+		Name:         "team-a",
+		Destinations: []types.AppProjectDestination{{Namespace: "team-a", Server: "https://kubernetes.default.svc"}},
+		SyncWindows: []types.SyncWindow{
+			{Kind: "deny", Schedule: "0 22 * * *", Duration: "8h"},
+			{Kind: "allow", Schedule: "0 6 * * mon-fri", Duration: "1h", Applications: []string{"team-a-*"}},
+		},
+	}
+
+	spec := service.buildProjectSpec(project)
+	rendered, err := json.Marshal(spec["syncWindows"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"kind": "deny", "schedule": "0 22 * * *", "duration": "8h"},
+		{"kind": "allow", "schedule": "0 6 * * mon-fri", "duration": "1h", "applications": ["team-a-*"]}
+	]`, string(rendered))
+
+	// The rendered windows must survive the deep copy made when the AppProject is sent to the API server
+	resource := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"syncWindows": spec["syncWindows"]},
+	}}
+	windows, found, err := unstructured.NestedSlice(resource.DeepCopy().Object, "spec", "syncWindows")
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Len(t, windows, 2)
+
+	t.Run("No windows configured", func(t *testing.T) {
+		project.SyncWindows = nil
+		assert.NotContains(t, service.buildProjectSpec(project), "syncWindows")
+	})
 }
 
 func TestAddResourceRestrictions_WithWhitelist(t *testing.T) {
@@ -378,6 +538,159 @@ func TestBuildAppProjectResource(t *testing.T) {
 	assert.Equal(t, spec["destinations"], embeddedSpec["destinations"])
 }
 
+func TestBuildApplicationResource(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	service := &argoCDService{
+		logger:    logger,
+		namespace: "argocd",
+	}
+
+	app := &types.Application{
+		Name:    "test-app",
+		Project: "test-project",
+		Source: types.ApplicationSource{
+			RepoURL:        "https://github.com/test/repo",
+			TargetRevision: "main",
+			Path:           "manifests",
+		},
+		Destination: types.ApplicationDestination{
+			Server:    "https://kubernetes.default.svc",
+			Namespace: "test-namespace",
+		},
+		SyncPolicy: types.ApplicationSyncPolicy{
+			Automated: &types.ApplicationSyncPolicyAutomated{Prune: true, SelfHeal: true},
+		},
+	}
+
+	t.Run("Without retry policy", func(t *testing.T) {
+		resource := service.buildApplicationResource(app)
+
+		assert.Equal(t, "Application", resource.Object["kind"])
+		syncPolicy, found, err := unstructured.NestedMap(resource.Object, "spec", "syncPolicy")
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.NotContains(t, syncPolicy, "retry")
+		assert.Contains(t, syncPolicy, "automated")
+	})
+
+	t.Run("With retry policy", func(t *testing.T) {
+		withRetry := *app
+		withRetry.SyncPolicy.Retry = &types.ApplicationSyncPolicyRetry{
+			Limit: 5,
+			Backoff: &types.ApplicationSyncPolicyRetryBackoff{
+				Duration:    "5s",
+				Factor:      2,
+				MaxDuration: "3m",
+			},
+		}
+
+		resource := service.buildApplicationResource(&withRetry)
+
+		limit, found, err := unstructured.NestedInt64(resource.Object, "spec", "syncPolicy", "retry", "limit")
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, int64(5), limit)
+
+		backoff, found, err := unstructured.NestedMap(resource.Object, "spec", "syncPolicy", "retry", "backoff")
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, "5s", backoff["duration"])
+		assert.Equal(t, int64(2), backoff["factor"])
+		assert.Equal(t, "3m", backoff["maxDuration"])
+	})
+
+	t.Run("Manual sync without an automated policy", func(t *testing.T) {
+		manual := *app
+		manual.SyncPolicy = types.ApplicationSyncPolicy{}
+
+		resource := service.buildApplicationResource(&manual)
+		syncPolicy, found, err := unstructured.NestedMap(resource.Object, "spec", "syncPolicy")
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.NotContains(t, syncPolicy, "automated")
+		assert.Contains(t, syncPolicy, "syncOptions")
+	})
+
+	t.Run("Automated policy without prune", func(t *testing.T) {
+		noPrune := *app
+		noPrune.SyncPolicy = types.ApplicationSyncPolicy{
+			Automated: &types.ApplicationSyncPolicyAutomated{SelfHeal: true},
+		}
+
+		resource := service.buildApplicationResource(&noPrune)
+		automated, found, err := unstructured.NestedMap(resource.Object, "spec", "syncPolicy", "automated")
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, false, automated["prune"])
+		assert.Equal(t, true, automated["selfHeal"])
+	})
+
+	t.Run("With owner references", func(t *testing.T) {
+		owned := *app
+		owned.OwnerReferences = []types.OwnerReference{
+			{APIVersion: "v1", Kind: "Namespace", Name: "test-namespace", UID: "ns-uid"},
+		}
+
+		resource := service.buildApplicationResource(&owned)
+
+		refs := resource.GetOwnerReferences()
+		require.Len(t, refs, 1)
+		assert.Equal(t, "Namespace", refs[0].Kind)
+		assert.Equal(t, "test-namespace", refs[0].Name)
+		assert.Equal(t, "ns-uid", string(refs[0].UID))
+	})
+
+	t.Run("Without owner references", func(t *testing.T) {
+		resource := service.buildApplicationResource(app)
+		assert.Empty(t, resource.GetOwnerReferences())
+	})
+
+	t.Run("Without tool overrides", func(t *testing.T) {
+		resource := service.buildApplicationResource(app)
+		source, _, err := unstructured.NestedMap(resource.Object, "spec", "source")
+		require.NoError(t, err)
+		assert.NotContains(t, source, "helm")
+		assert.NotContains(t, source, "kustomize")
+	})
+
+	t.Run("With Helm overrides", func(t *testing.T) {
+		withHelm := *app
+		withHelm.Source.Helm = &types.ApplicationSourceHelm{
+			Values:     "replicas: 2\n",
+			Parameters: []types.HelmParameter{{Name: "image.tag", Value: "v1.2.3"}},
+		}
+
+		resource := service.buildApplicationResource(&withHelm)
+
+		values, _, err := unstructured.NestedString(resource.Object, "spec", "source", "helm", "values")
+		require.NoError(t, err)
+		assert.Equal(t, "replicas: 2\n", values)
+		parameters, found, err := unstructured.NestedSlice(resource.Object, "spec", "source", "helm", "parameters")
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, []interface{}{map[string]interface{}{"name": "image.tag", "value": "v1.2.3"}}, parameters)
+	})
+
+	t.Run("With Kustomize overrides", func(t *testing.T) {
+		withKustomize := *app
+		withKustomize.Source.Kustomize = &types.ApplicationSourceKustomize{
+			NamePrefix: "team-a-",
+			Images:     []string{"nginx=nginx:1.25"},
+		}
+
+		resource := service.buildApplicationResource(&withKustomize)
+
+		prefix, _, err := unstructured.NestedString(resource.Object, "spec", "source", "kustomize", "namePrefix")
+		require.NoError(t, err)
+		assert.Equal(t, "team-a-", prefix)
+		images, _, err := unstructured.NestedStringSlice(resource.Object, "spec", "source", "kustomize", "images")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"nginx=nginx:1.25"}, images)
+	})
+}
+
 func TestNewArgoCDServiceReal_Constructor(t *testing.T) {
 	logger := logrus.New()
 	cfg := &config.Config{}
@@ -577,3 +890,1088 @@ func TestArgoCDServiceWithFakeClient_Operations(t *testing.T) {
 		assert.Nil(t, service)
 	})
 }
+
+func TestArgoCDService_FindAppProjectByRepoHash(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{}
+
+	repoHash := GenerateRepositoryHash("https://github.com/test/repo")
+	existing := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "AppProject",
+			"metadata": map[string]interface{}{
+				"name":      "team-a",
+				"namespace": "argocd",
+				"labels": map[string]interface{}{
+					RepositoryHashLabel: repoHash,
+					"gitops.io/tenant":  "team-a",
+				},
+			},
+			"spec": map[string]interface{}{
+				"destinations": []interface{}{
+					map[string]interface{}{
+						"server":    "https://kubernetes.default.svc",
+						"namespace": "team-a",
+					},
+				},
+			},
+		},
+	}
+
+	fakeClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{appProjectGVR: "AppProjectList"}, existing)
+	factory := &TestArgoCDFactory{Client: fakeClient}
+
+	service, err := NewArgoCDServiceWithFactory(cfg, logger, factory)
+	require.NoError(t, err)
+
+	t.Run("Matching AppProject is returned", func(t *testing.T) {
+		project, err := service.FindAppProjectByRepoHash(context.Background(), repoHash)
+		require.NoError(t, err)
+		require.NotNil(t, project)
+		assert.Equal(t, "team-a", project.Name)
+		assert.Equal(t, "team-a", project.Labels["gitops.io/tenant"])
+		require.Len(t, project.Destinations, 1)
+		assert.Equal(t, "team-a", project.Destinations[0].Namespace)
+	})
+
+	t.Run("No match returns nil", func(t *testing.T) {
+		project, err := service.FindAppProjectByRepoHash(context.Background(),
+			GenerateRepositoryHash("https://github.com/test/other"))
+		require.NoError(t, err)
+		assert.Nil(t, project)
+	})
+}
+
+func TestArgoCDService_DeleteAppProjectByRepoHash(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{ArgoCD: config.ArgoCDConfig{Namespace: "argocd"}}
+	ctx := context.Background()
+
+	repoHash := GenerateRepositoryHash("https://github.com/test/orphaned")
+	orphaned := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "AppProject",
+			"metadata": map[string]interface{}{
+				"name":      "team-gone",
+				"namespace": "argocd",
+				"labels": map[string]interface{}{
+					RepositoryHashLabel: repoHash,
+					"gitops.io/tenant":  "team-gone",
+				},
+			},
+		},
+	}
+
+	base := &argoCDService{logger: logger, namespace: "argocd"}
+	application := base.buildApplicationResource(&types.Application{
+		Name:    "team-gone-app",
+		Project: "team-gone",
+		Destination: types.ApplicationDestination{
+			Server:    InClusterServer,
+			Namespace: "team-gone",
+		},
+	})
+
+	fakeClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			appProjectGVR:  "AppProjectList",
+			applicationGVR: "ApplicationList",
+		}, orphaned, application)
+
+	service, err := NewArgoCDServiceWithFactory(cfg, logger, &TestArgoCDFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	t.Run("No match returns nil", func(t *testing.T) {
+		project, err := service.DeleteAppProjectByRepoHash(ctx, GenerateRepositoryHash("https://github.com/test/other"))
+		require.NoError(t, err)
+		assert.Nil(t, project)
+
+		_, err = fakeClient.Resource(appProjectGVR).Namespace("argocd").Get(ctx, "team-gone", metav1.GetOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Orphaned AppProject and Application are removed", func(t *testing.T) {
+		project, err := service.DeleteAppProjectByRepoHash(ctx, repoHash)
+		require.NoError(t, err)
+		require.NotNil(t, project)
+		assert.Equal(t, "team-gone", project.Name)
+
+		_, err = fakeClient.Resource(appProjectGVR).Namespace("argocd").Get(ctx, "team-gone", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+		_, err = fakeClient.Resource(applicationGVR).Namespace("argocd").Get(ctx, "team-gone-app", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("ApplicationSet mode removes the ApplicationSet", func(t *testing.T) {
+		appSetCfg := &config.Config{
+			ArgoCD:       config.ArgoCDConfig{Namespace: "argocd"},
+			Registration: config.RegistrationConfig{UseApplicationSet: true},
+		}
+		appSet := base.buildApplicationSetResource(&types.ApplicationSet{
+			Name:        "team-gone-appset",
+			Project:     "team-gone",
+			Destination: types.ApplicationDestination{Server: InClusterServer, Namespace: "team-gone"},
+		})
+		appSetClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{appProjectGVR: "AppProjectList"}, orphaned.DeepCopy(), appSet)
+		appSetService, err := NewArgoCDServiceWithFactory(appSetCfg, logger, &TestArgoCDFactory{Client: appSetClient})
+		require.NoError(t, err)
+
+		project, err := appSetService.DeleteAppProjectByRepoHash(ctx, repoHash)
+		require.NoError(t, err)
+		require.NotNil(t, project)
+
+		_, err = appSetClient.Resource(applicationSetGVR).Namespace("argocd").Get(ctx, "team-gone-appset", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+}
+
+func TestArgoCDService_UpdateApplicationSource(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	base := &argoCDService{logger: logger, namespace: "argocd"}
+	existing := base.buildApplicationResource(&types.Application{
+		Name:    "team-a-app",
+		Project: "team-a",
+		Source: types.ApplicationSource{
+			RepoURL:        "https://github.com/test/repo",
+			TargetRevision: "main",
+			Path:           "manifests",
+		},
+		Destination: types.ApplicationDestination{
+			Server:    "https://kubernetes.default.svc",
+			Namespace: "team-a",
+		},
+	})
+
+	fakeClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), existing)
+	service, err := NewArgoCDServiceWithFactory(&config.Config{}, logger, &TestArgoCDFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	t.Run("Updates target revision only", func(t *testing.T) {
+		err := service.UpdateApplicationSource(ctx, "team-a-app", &types.ApplicationSource{TargetRevision: "develop"})
+		require.NoError(t, err)
+
+		app, err := fakeClient.Resource(applicationGVR).Namespace("argocd").Get(ctx, "team-a-app", metav1.GetOptions{})
+		require.NoError(t, err)
+		source, _, _ := unstructured.NestedStringMap(app.Object, "spec", "source")
+		assert.Equal(t, "develop", source["targetRevision"])
+		assert.Equal(t, "manifests", source["path"])
+		assert.Equal(t, "https://github.com/test/repo", source["repoURL"])
+	})
+
+	t.Run("Repository URL is never changed", func(t *testing.T) {
+		err := service.UpdateApplicationSource(ctx, "team-a-app", &types.ApplicationSource{
+			RepoURL: "https://github.com/other/repo",
+			Path:    "overlays/prod",
+		})
+		require.NoError(t, err)
+
+		app, err := fakeClient.Resource(applicationGVR).Namespace("argocd").Get(ctx, "team-a-app", metav1.GetOptions{})
+		require.NoError(t, err)
+		source, _, _ := unstructured.NestedStringMap(app.Object, "spec", "source")
+		assert.Equal(t, "overlays/prod", source["path"])
+		assert.Equal(t, "https://github.com/test/repo", source["repoURL"])
+	})
+
+	t.Run("Missing Application", func(t *testing.T) {
+		err := service.UpdateApplicationSource(ctx, "missing-app", &types.ApplicationSource{TargetRevision: "develop"})
+		var notFoundErr *ApplicationNotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+		assert.Equal(t, "missing-app", notFoundErr.Application)
+	})
+}
+
+func TestArgoCDService_RefreshApplication(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	base := &argoCDService{logger: logger, namespace: "argocd"}
+	existing := base.buildApplicationResource(&types.Application{
+		Name:    "team-a-app",
+		Project: "team-a",
+		Source: types.ApplicationSource{
+			RepoURL:        "https://github.com/test/repo",
+			TargetRevision: "main",
+			Path:           "manifests",
+		},
+		Destination: types.ApplicationDestination{
+			Server:    "https://kubernetes.default.svc",
+			Namespace: "team-a",
+		},
+	})
+
+	fakeClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), existing)
+	service, err := NewArgoCDServiceWithFactory(&config.Config{}, logger, &TestArgoCDFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	refreshAnnotation := func(t *testing.T) string {
+		app, err := fakeClient.Resource(applicationGVR).Namespace("argocd").Get(ctx, "team-a-app", metav1.GetOptions{})
+		require.NoError(t, err)
+		// The source is left alone, only the annotation changes
+		source, _, _ := unstructured.NestedStringMap(app.Object, "spec", "source")
+		assert.Equal(t, "main", source["targetRevision"])
+		return app.GetAnnotations()[ApplicationRefreshAnnotation]
+	}
+
+	t.Run("Normal refresh", func(t *testing.T) {
+		require.NoError(t, service.RefreshApplication(ctx, "team-a-app", false))
+		assert.Equal(t, "normal", refreshAnnotation(t))
+	})
+
+	t.Run("Hard refresh", func(t *testing.T) {
+		require.NoError(t, service.RefreshApplication(ctx, "team-a-app", true))
+		assert.Equal(t, "hard", refreshAnnotation(t))
+	})
+
+	t.Run("Missing Application", func(t *testing.T) {
+		err := service.RefreshApplication(ctx, "missing-app", true)
+		var notFoundErr *ApplicationNotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+		assert.Equal(t, "missing-app", notFoundErr.Application)
+	})
+}
+
+func TestArgoCDService_SuspendApplication(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	base := &argoCDService{logger: logger, namespace: "argocd"}
+	existing := base.buildApplicationResource(&types.Application{
+		Name:    "team-a-app",
+		Project: "team-a",
+		Source: types.ApplicationSource{
+			RepoURL:        "https://github.com/test/repo",
+			TargetRevision: "main",
+			Path:           "manifests",
+		},
+		Destination: types.ApplicationDestination{
+			Server:    "https://kubernetes.default.svc",
+			Namespace: "team-a",
+		},
+		SyncPolicy: types.ApplicationSyncPolicy{
+			Automated: &types.ApplicationSyncPolicyAutomated{Prune: true, SelfHeal: true},
+		},
+	})
+
+	fakeClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), existing)
+	service, err := NewArgoCDServiceWithFactory(&config.Config{}, logger, &TestArgoCDFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	t.Run("Automated sync is removed", func(t *testing.T) {
+		require.NoError(t, service.SuspendApplication(ctx, "team-a-app"))
+
+		app, err := fakeClient.Resource(applicationGVR).Namespace("argocd").Get(ctx, "team-a-app", metav1.GetOptions{})
+		require.NoError(t, err)
+		_, found, _ := unstructured.NestedMap(app.Object, "spec", "syncPolicy", "automated")
+		assert.False(t, found)
+		// The rest of the sync policy is left alone so the Application can be resumed as it was
+		options, _, _ := unstructured.NestedStringSlice(app.Object, "spec", "syncPolicy", "syncOptions")
+		assert.Contains(t, options, "CreateNamespace=false")
+	})
+
+	t.Run("Missing Application", func(t *testing.T) {
+		err := service.SuspendApplication(ctx, "missing-app")
+		var notFoundErr *ApplicationNotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+		assert.Equal(t, "missing-app", notFoundErr.Application)
+	})
+
+	t.Run("Resume restores automated sync", func(t *testing.T) {
+		automated := &types.ApplicationSyncPolicyAutomated{Prune: true, SelfHeal: false}
+		require.NoError(t, service.ResumeApplication(ctx, "team-a-app", automated))
+
+		app, err := fakeClient.Resource(applicationGVR).Namespace("argocd").Get(ctx, "team-a-app", metav1.GetOptions{})
+		require.NoError(t, err)
+		prune, _, _ := unstructured.NestedBool(app.Object, "spec", "syncPolicy", "automated", "prune")
+		selfHeal, _, _ := unstructured.NestedBool(app.Object, "spec", "syncPolicy", "automated", "selfHeal")
+		assert.True(t, prune)
+		assert.False(t, selfHeal)
+	})
+}
+
+func TestArgoCDService_SuspendApplicationSet(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	base := &argoCDService{logger: logger, namespace: "argocd"}
+	existing := base.buildApplicationSetResource(&types.ApplicationSet{
+		Name:           "team-a-appset",
+		Project:        "team-a",
+		RepoURL:        "https://github.com/test/repo",
+		TargetRevision: "main",
+		DirectoryPath:  "apps/*",
+		Destination: types.ApplicationDestination{
+			Server:    "https://kubernetes.default.svc",
+			Namespace: "team-a",
+		},
+		SyncPolicy: types.ApplicationSyncPolicy{
+			Automated: &types.ApplicationSyncPolicyAutomated{Prune: true, SelfHeal: true},
+		},
+	})
+
+	fakeClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), existing)
+	service, err := NewArgoCDServiceWithFactory(&config.Config{}, logger, &TestArgoCDFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	automatedPath := []string{"spec", "template", "spec", "syncPolicy", "automated"}
+
+	t.Run("Automated sync is removed from the template", func(t *testing.T) {
+		require.NoError(t, service.SuspendApplicationSet(ctx, "team-a-appset"))
+
+		appSet, err := fakeClient.Resource(applicationSetGVR).Namespace("argocd").Get(ctx, "team-a-appset", metav1.GetOptions{})
+		require.NoError(t, err)
+		_, found, _ := unstructured.NestedMap(appSet.Object, automatedPath...)
+		assert.False(t, found)
+	})
+
+	t.Run("Resume restores it", func(t *testing.T) {
+		automated := &types.ApplicationSyncPolicyAutomated{Prune: true, SelfHeal: true}
+		require.NoError(t, service.ResumeApplicationSet(ctx, "team-a-appset", automated))
+
+		appSet, err := fakeClient.Resource(applicationSetGVR).Namespace("argocd").Get(ctx, "team-a-appset", metav1.GetOptions{})
+		require.NoError(t, err)
+		selfHeal, found, _ := unstructured.NestedBool(appSet.Object, append(automatedPath, "selfHeal")...)
+		assert.True(t, found)
+		assert.True(t, selfHeal)
+	})
+
+	t.Run("Missing ApplicationSet", func(t *testing.T) {
+		err := service.SuspendApplicationSet(ctx, "missing-appset")
+		var notFoundErr *ApplicationNotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+		assert.Equal(t, "missing-appset", notFoundErr.Application)
+	})
+}
+
+func TestArgoCDService_HealthCheck_CRDs(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{}
+	ctx := context.Background()
+
+	newClient := func() *fakedynamic.FakeDynamicClient {
+		return fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{appProjectGVR: "AppProjectList"}, argoCDNamespaceObject("argocd"))
+	}
+
+	t.Run("CRDs present", func(t *testing.T) {
+		factory := &TestArgoCDFactory{Client: newClient(), Discovery: NewFakeArgoCDDiscovery(true)}
+		service, err := NewArgoCDServiceWithFactory(cfg, logger, factory)
+		require.NoError(t, err)
+
+		assert.NoError(t, service.HealthCheck(ctx))
+	})
+
+	t.Run("ArgoCD not installed", func(t *testing.T) {
+		factory := &TestArgoCDFactory{Client: newClient(), Discovery: NewFakeArgoCDDiscovery(false)}
+		service, err := NewArgoCDServiceWithFactory(cfg, logger, factory)
+		require.NoError(t, err)
+
+		err = service.HealthCheck(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is ArgoCD installed")
+	})
+
+	t.Run("Application CRD missing", func(t *testing.T) {
+		partial := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{
+			Resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: "argoproj.io/v1alpha1",
+					APIResources: []metav1.APIResource{{Name: "appprojects", Kind: "AppProject"}},
+				},
+			},
+		}}
+		factory := &TestArgoCDFactory{Client: newClient(), Discovery: partial}
+		service, err := NewArgoCDServiceWithFactory(cfg, logger, factory)
+		require.NoError(t, err)
+
+		err = service.HealthCheck(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "applications.argoproj.io is not installed")
+	})
+}
+
+func argoCDNamespaceObject(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+		},
+	}
+}
+
+func TestArgoCDService_Namespace(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{ArgoCD: config.ArgoCDConfig{Namespace: "openshift-gitops"}}
+	ctx := context.Background()
+
+	newService := func(objects ...runtime.Object) ArgoCDService {
+		client := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{appProjectGVR: "AppProjectList"}, objects...)
+		service, err := NewArgoCDServiceWithFactory(cfg, logger, &TestArgoCDFactory{Client: client})
+		require.NoError(t, err)
+		return service
+	}
+
+	t.Run("Health check passes when the namespace exists", func(t *testing.T) {
+		service := newService(argoCDNamespaceObject("openshift-gitops"))
+		assert.NoError(t, service.HealthCheck(ctx))
+	})
+
+	t.Run("Health check reports a missing namespace", func(t *testing.T) {
+		service := newService(argoCDNamespaceObject("argocd"))
+
+		err := service.HealthCheck(ctx)
+		var missingErr *ArgoCDNamespaceMissingError
+		require.ErrorAs(t, err, &missingErr)
+		assert.Equal(t, "openshift-gitops", missingErr.Namespace)
+		assert.Contains(t, err.Error(), "argocd.namespace")
+	})
+
+	t.Run("Create errors caused by the missing namespace", func(t *testing.T) {
+		// The fake dynamic client cannot deep-copy AppProject specs, so the
+		// mapping used by CreateAppProject is exercised directly
+		namespaceGone := apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "openshift-gitops")
+		crdGone := apierrors.NewNotFound(appProjectGVR.GroupResource(), "team-a")
+
+		assert.True(t, isNamespaceNotFound(namespaceGone))
+		assert.False(t, isNamespaceNotFound(crdGone))
+		assert.False(t, isNamespaceNotFound(errors.New("connection refused")))
+	})
+}
+
+func TestArgoCDService_ApplicationExists(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{ArgoCD: config.ArgoCDConfig{Namespace: "argocd"}}
+	ctx := context.Background()
+
+	existing := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "Application",
+			"metadata": map[string]interface{}{
+				"name":      "team-a-app",
+				"namespace": "argocd",
+			},
+		},
+	}
+
+	fakeClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), existing)
+	service, err := NewArgoCDServiceWithFactory(cfg, logger, &TestArgoCDFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	t.Run("Existing Application", func(t *testing.T) {
+		exists, err := service.ApplicationExists(ctx, "team-a-app")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("Absent Application", func(t *testing.T) {
+		exists, err := service.ApplicationExists(ctx, "team-b-app")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("Lookup failure", func(t *testing.T) {
+		failingClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+		failingClient.PrependReactor("get", "applications",
+			func(action clienttesting.Action) (bool, runtime.Object, error) {
+				return true, nil, errors.New("connection refused")
+			})
+		failing, err := NewArgoCDServiceWithFactory(cfg, logger, &TestArgoCDFactory{Client: failingClient})
+		require.NoError(t, err)
+
+		_, err = failing.ApplicationExists(ctx, "team-a-app")
+		assert.Error(t, err)
+	})
+}
+
+func TestArgoCDService_CreateApplicationSet(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{ArgoCD: config.ArgoCDConfig{Namespace: "argocd"}}
+	ctx := context.Background()
+
+	fakeClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+	service, err := NewArgoCDServiceWithFactory(cfg, logger, &TestArgoCDFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	appSet := &types.ApplicationSet{
+		Name:           "team-a-appset",
+		Project:        "team-a",
+		RepoURL:        "https://github.com/test/monorepo",
+		TargetRevision: "main",
+		DirectoryPath:  "manifests/*",
+		Destination: types.ApplicationDestination{
+			Server:    "https://kubernetes.default.svc",
+			Namespace: "team-a",
+		},
+		OwnerReferences: []types.OwnerReference{
+			{APIVersion: "v1", Kind: "Namespace", Name: "team-a", UID: "ns-uid"},
+		},
+		Notifications: []types.NotificationSubscription{
+			{Trigger: "on-health-degraded", Service: "slack", Recipients: []string{"team-a-alerts"}},
+		},
+	}
+
+	require.NoError(t, service.CreateApplicationSet(ctx, appSet))
+
+	created, err := fakeClient.Resource(applicationSetGVR).Namespace("argocd").Get(ctx, "team-a-appset", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "ApplicationSet", created.GetKind())
+	assert.Equal(t, "team-a", created.GetLabels()["gitops.io/tenant"])
+	require.Len(t, created.GetOwnerReferences(), 1)
+
+	generators, found, err := unstructured.NestedSlice(created.Object, "spec", "generators")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Len(t, generators, 1)
+	git, _, _ := unstructured.NestedMap(generators[0].(map[string]interface{}), "git")
+	assert.Equal(t, "https://github.com/test/monorepo", git["repoURL"])
+	assert.Equal(t, "main", git["revision"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"path": "manifests/*"}}, git["directories"])
+
+	template, found, err := unstructured.NestedMap(created.Object, "spec", "template")
+	require.NoError(t, err)
+	require.True(t, found)
+	name, _, _ := unstructured.NestedString(template, "metadata", "name")
+	assert.Equal(t, "team-a-{{path.basename}}", name)
+	project, _, _ := unstructured.NestedString(template, "spec", "project")
+	assert.Equal(t, "team-a", project)
+	path, _, _ := unstructured.NestedString(template, "spec", "source", "path")
+	assert.Equal(t, "{{path}}", path)
+	namespace, _, _ := unstructured.NestedString(template, "spec", "destination", "namespace")
+	assert.Equal(t, "team-a", namespace)
+	subscription, _, _ := unstructured.NestedString(template, "metadata", "annotations",
+		"notifications.argoproj.io/subscribe.on-health-degraded.slack")
+	assert.Equal(t, "team-a-alerts", subscription)
+
+	t.Run("Existing ApplicationSet is not an error", func(t *testing.T) {
+		assert.NoError(t, service.CreateApplicationSet(ctx, appSet))
+	})
+
+	t.Run("ApplicationSet lookup and deletion", func(t *testing.T) {
+		exists, err := service.ApplicationSetExists(ctx, "team-a-appset")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		require.NoError(t, service.DeleteApplicationSet(ctx, "team-a-appset"))
+		exists, err = service.ApplicationSetExists(ctx, "team-a-appset")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		// Deleting an absent ApplicationSet is a no-op
+		assert.NoError(t, service.DeleteApplicationSet(ctx, "team-a-appset"))
+	})
+}
+
+func TestTenantApplicationName(t *testing.T) {
+	assert.Equal(t, "team-a-app", TenantApplicationName(nil, "team-a"))
+	assert.Equal(t, "team-a-app", TenantApplicationName(&config.Config{}, "team-a"))
+	assert.Equal(t, "team-a-appset", TenantApplicationName(&config.Config{
+		Registration: config.RegistrationConfig{UseApplicationSet: true},
+	}, "team-a"))
+}
+
+// pagingDynamicClient serves list calls in chunks honouring Limit and Continue, which the fake
+// dynamic client ignores. Continue tokens are the offset of the next item.
+type pagingDynamicClient struct {
+	*fakedynamic.FakeDynamicClient
+	listCalls int
+}
+
+func (c *pagingDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &pagingResource{NamespaceableResourceInterface: c.FakeDynamicClient.Resource(gvr), client: c}
+}
+
+type pagingResource struct {
+	dynamic.NamespaceableResourceInterface
+	client *pagingDynamicClient
+}
+
+func (r *pagingResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &pagingNamespacedResource{ResourceInterface: r.NamespaceableResourceInterface.Namespace(namespace), client: r.client}
+}
+
+type pagingNamespacedResource struct {
+	dynamic.ResourceInterface
+	client *pagingDynamicClient
+}
+
+func (r *pagingNamespacedResource) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	r.client.listCalls++
+	if opts.Limit <= 0 {
+		return nil, errors.New("unbounded list request")
+	}
+
+	all, err := r.ResourceInterface.List(ctx, metav1.ListOptions{LabelSelector: opts.LabelSelector})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(all.Items, func(i, j int) bool { return all.Items[i].GetName() < all.Items[j].GetName() })
+
+	start := 0
+	if opts.Continue != "" {
+		if start, err = strconv.Atoi(opts.Continue); err != nil {
+			return nil, err
+		}
+	}
+	end := start + int(opts.Limit)
+	if end > len(all.Items) {
+		end = len(all.Items)
+	}
+
+	page := &unstructured.UnstructuredList{Object: all.Object, Items: all.Items[start:end]}
+	if end < len(all.Items) {
+		page.SetContinue(strconv.Itoa(end))
+	}
+	return page, nil
+}
+
+func TestArgoCDService_ListAppProjectsPaged(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{ArgoCD: config.ArgoCDConfig{Namespace: "argocd"}}
+	ctx := context.Background()
+
+	total := int(appProjectPageSize)*2 + 5
+	objects := make([]runtime.Object, 0, total+1)
+	for i := 0; i < total; i++ {
+		objects = append(objects, &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "argoproj.io/v1alpha1",
+				"kind":       "AppProject",
+				"metadata": map[string]interface{}{
+					"name":      fmt.Sprintf("team-%03d", i),
+					"namespace": "argocd",
+					"labels": map[string]interface{}{
+						"gitops.io/managed-by": GitOpsRegistrationService,
+						RepositoryHashLabel:    fmt.Sprintf("hash-%03d", i),
+					},
+				},
+			},
+		})
+	}
+	objects = append(objects, &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "AppProject",
+			"metadata": map[string]interface{}{
+				"name":      "default",
+				"namespace": "argocd",
+			},
+		},
+	})
+
+	newService := func(t *testing.T) (ArgoCDService, *pagingDynamicClient) {
+		client := &pagingDynamicClient{
+			FakeDynamicClient: fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{appProjectGVR: "AppProjectList"}, objects...),
+		}
+		service, err := NewArgoCDServiceWithFactory(cfg, logger, &TestArgoCDFactory{Client: client})
+		require.NoError(t, err)
+		return service, client
+	}
+
+	t.Run("All managed AppProjects are returned across pages", func(t *testing.T) {
+		service, client := newService(t)
+
+		projects, err := service.ListManagedAppProjects(ctx)
+		require.NoError(t, err)
+		assert.Len(t, projects, total)
+		assert.Equal(t, 3, client.listCalls)
+
+		seen := make(map[string]bool, len(projects))
+		for _, project := range projects {
+			seen[project.Name] = true
+		}
+		assert.Len(t, seen, total)
+		assert.True(t, seen[fmt.Sprintf("team-%03d", total-1)])
+	})
+
+	t.Run("Repository hash lookups use bounded requests", func(t *testing.T) {
+		service, client := newService(t)
+
+		project, err := service.FindAppProjectByRepoHash(ctx, fmt.Sprintf("hash-%03d", total-1))
+		require.NoError(t, err)
+		require.NotNil(t, project)
+		assert.Equal(t, fmt.Sprintf("team-%03d", total-1), project.Name)
+
+		exists, err := service.CheckAppProjectConflict(ctx, "hash-missing")
+		require.NoError(t, err)
+		assert.False(t, exists)
+		assert.Equal(t, 2, client.listCalls)
+	})
+}
+
+func TestArgoCDService_GetApplicationResources(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{ArgoCD: config.ArgoCDConfig{Namespace: "argocd"}}
+	ctx := context.Background()
+
+	application := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "Application",
+			"metadata": map[string]interface{}{
+				"name":      "team-a-app",
+				"namespace": "argocd",
+			},
+			"status": map[string]interface{}{
+				"resources": []interface{}{
+					map[string]interface{}{
+						"group":     "apps",
+						"version":   "v1",
+						"kind":      "Deployment",
+						"namespace": "team-a",
+						"name":      "web",
+						"status":    "Synced",
+						"health":    map[string]interface{}{"status": "Healthy"},
+					},
+					map[string]interface{}{
+						"version":   "v1",
+						"kind":      "ConfigMap",
+						"namespace": "team-a",
+						"name":      "web-config",
+						"status":    "OutOfSync",
+					},
+				},
+			},
+		},
+	}
+	withoutStatus := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "Application",
+			"metadata": map[string]interface{}{
+				"name":      "team-b-app",
+				"namespace": "argocd",
+			},
+		},
+	}
+
+	fakeClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), application, withoutStatus)
+	service, err := NewArgoCDServiceWithFactory(cfg, logger, &TestArgoCDFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	t.Run("Resources are read from the Application status", func(t *testing.T) {
+		resources, err := service.GetApplicationResources(ctx, "team-a-app")
+		require.NoError(t, err)
+		assert.Equal(t, []types.ApplicationResource{
+			{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "team-a", Name: "web", Health: "Healthy", Sync: "Synced"},
+			{Version: "v1", Kind: "ConfigMap", Namespace: "team-a", Name: "web-config", Sync: "OutOfSync"},
+		}, resources)
+	})
+
+	t.Run("Application that has not synced yet", func(t *testing.T) {
+		resources, err := service.GetApplicationResources(ctx, "team-b-app")
+		require.NoError(t, err)
+		assert.Empty(t, resources)
+	})
+
+	t.Run("Missing Application", func(t *testing.T) {
+		_, err := service.GetApplicationResources(ctx, "team-missing-app")
+		require.Error(t, err)
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+}
+
+func TestArgoCDService_GetApplicationHistory(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{ArgoCD: config.ArgoCDConfig{Namespace: "argocd"}}
+	ctx := context.Background()
+
+	application := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "Application",
+			"metadata": map[string]interface{}{
+				"name":      "team-a-app",
+				"namespace": "argocd",
+			},
+			"status": map[string]interface{}{
+				"history": []interface{}{
+					map[string]interface{}{
+						"id":              int64(0),
+						"revision":        "1111111",
+						"deployStartedAt": "2024-05-01T10:00:00Z",
+						"deployedAt":      "2024-05-01T10:00:05Z",
+						"initiatedBy":     map[string]interface{}{"automated": true},
+					},
+					map[string]interface{}{
+						"id":          int64(1),
+						"revision":    "2222222",
+						"deployedAt":  "2024-05-02T09:30:00Z",
+						"initiatedBy": map[string]interface{}{"username": "alice"},
+					},
+				},
+			},
+		},
+	}
+	withoutStatus := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "Application",
+			"metadata": map[string]interface{}{
+				"name":      "team-b-app",
+				"namespace": "argocd",
+			},
+		},
+	}
+
+	fakeClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), application, withoutStatus)
+	service, err := NewArgoCDServiceWithFactory(cfg, logger, &TestArgoCDFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	t.Run("History is read from the Application status, newest first", func(t *testing.T) {
+		history, err := service.GetApplicationHistory(ctx, "team-a-app")
+		require.NoError(t, err)
+
+		startedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		assert.Equal(t, []types.ApplicationHistoryEntry{
+			{ID: 1, Revision: "2222222", DeployedAt: time.Date(2024, 5, 2, 9, 30, 0, 0, time.UTC), InitiatedBy: "alice"},
+			{
+				ID: 0, Revision: "1111111", DeployedAt: time.Date(2024, 5, 1, 10, 0, 5, 0, time.UTC),
+				DeployStartedAt: &startedAt, InitiatedBy: "automated",
+			},
+		}, history)
+	})
+
+	t.Run("Application that has not synced yet", func(t *testing.T) {
+		history, err := service.GetApplicationHistory(ctx, "team-b-app")
+		require.NoError(t, err)
+		assert.Empty(t, history)
+	})
+
+	t.Run("Missing Application", func(t *testing.T) {
+		_, err := service.GetApplicationHistory(ctx, "team-missing-app")
+		require.Error(t, err)
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+}
+
+func TestArgoCDService_EnsureRepoHashLabel(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{ArgoCD: config.ArgoCDConfig{Namespace: "argocd"}}
+	ctx := context.Background()
+	repoURL := "https://github.com/test/legacy"
+
+	fakeClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "AppProject",
+			"metadata": map[string]interface{}{
+				"name":      "team-legacy",
+				"namespace": "argocd",
+			},
+		},
+	})
+	service, err := NewArgoCDServiceWithFactory(cfg, logger, &TestArgoCDFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	updated, err := service.EnsureRepoHashLabel(ctx, "team-legacy", repoURL)
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	project, err := fakeClient.Resource(appProjectGVR).Namespace("argocd").Get(ctx, "team-legacy", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, GenerateRepositoryHash(repoURL), project.GetLabels()[RepositoryHashLabel])
+
+	updated, err = service.EnsureRepoHashLabel(ctx, "team-legacy", repoURL)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	_, err = service.EnsureRepoHashLabel(ctx, "team-missing", repoURL)
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestArgoCDService_AdoptAppProject(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{ArgoCD: config.ArgoCDConfig{Namespace: "argocd"}}
+	ctx := context.Background()
+
+	fakeClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "AppProject",
+			"metadata": map[string]interface{}{
+				"name":      "team-manual",
+				"namespace": "argocd",
+			},
+			"spec": map[string]interface{}{
+				"description": "created by hand",
+			},
+		},
+	})
+	service, err := NewArgoCDServiceWithFactory(cfg, logger, &TestArgoCDFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	exists, err := service.AppProjectExists(ctx, "team-manual")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = service.AppProjectExists(ctx, "team-missing")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	err = service.AdoptAppProject(ctx, &types.AppProject{
+		Name:         "team-manual",
+		Labels:       map[string]string{RepositoryHashLabel: "abc12345", "gitops.io/managed-by": "gitops-registration-service"},
+		Destinations: []types.AppProjectDestination{{Namespace: "team-manual", Server: InClusterServer}},
+	})
+	require.NoError(t, err)
+
+	project, err := fakeClient.Resource(appProjectGVR).Namespace("argocd").Get(ctx, "team-manual", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "abc12345", project.GetLabels()[RepositoryHashLabel])
+	assert.Equal(t, "gitops-registration-service", project.GetLabels()["gitops.io/managed-by"])
+	assert.Equal(t, "team-manual", project.GetLabels()["gitops.io/tenant"])
+	description, _, _ := unstructured.NestedString(project.Object, "spec", "description")
+	assert.Equal(t, "created by hand", description)
+
+	err = service.AdoptAppProject(ctx, &types.AppProject{Name: "team-missing"})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestArgoCDService_ResolveClusterServer(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{ArgoCD: config.ArgoCDConfig{Namespace: "argocd"}}
+	ctx := context.Background()
+
+	clusterSecret := func(name, clusterName, server string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "argocd",
+					"labels": map[string]interface{}{
+						"argocd.argoproj.io/secret-type": "cluster",
+					},
+				},
+				"data": map[string]interface{}{
+					"name":   base64.StdEncoding.EncodeToString([]byte(clusterName)),
+					"server": base64.StdEncoding.EncodeToString([]byte(server)),
+				},
+			},
+		}
+	}
+
+	fakeClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{secretGVR: "SecretList"},
+		clusterSecret("cluster-spoke-1", "spoke-1", "https://spoke-1.example.com:6443"),
+		clusterSecret("cluster-spoke-2", "spoke-2", "https://spoke-2.example.com:6443"))
+	service, err := NewArgoCDServiceWithFactory(cfg, logger, &TestArgoCDFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	t.Run("Registered cluster name", func(t *testing.T) {
+		server, err := service.ResolveClusterServer(ctx, "spoke-2")
+		require.NoError(t, err)
+		assert.Equal(t, "https://spoke-2.example.com:6443", server)
+	})
+
+	t.Run("In-cluster name", func(t *testing.T) {
+		server, err := service.ResolveClusterServer(ctx, InClusterName)
+		require.NoError(t, err)
+		assert.Equal(t, InClusterServer, server)
+	})
+
+	t.Run("Unknown cluster name", func(t *testing.T) {
+		_, err := service.ResolveClusterServer(ctx, "spoke-9")
+		var notFound *DestinationClusterNotFoundError
+		require.ErrorAs(t, err, &notFound)
+		assert.Equal(t, "spoke-9", notFound.Cluster)
+	})
+}
+
+func TestArgoCDService_TestRepositoryConnection(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	const (
+		reachableRepo   = "https://github.com/test/reachable"
+		unreachableRepo = "https://github.com/test/unreachable"
+	)
+
+	var authorization string
+	argocdServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/api/v1/repositories/" + reachableRepo:
+			fmt.Fprint(w, `{"repo":"`+reachableRepo+`","connectionState":{"status":"Successful"}}`)
+		case "/api/v1/repositories/" + unreachableRepo:
+			fmt.Fprint(w, `{"repo":"`+unreachableRepo+`","connectionState":{"status":"Failed","message":"authentication required"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer argocdServer.Close()
+
+	cfg := &config.Config{ArgoCD: config.ArgoCDConfig{Namespace: "argocd", Server: argocdServer.URL, Token: "argocd-token"}}
+	credsSecret := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]interface{}{
+				"name":      "private-repo-creds",
+				"namespace": "argocd",
+			},
+		},
+	}
+	fakeClient := fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), credsSecret)
+	service, err := NewArgoCDServiceWithFactory(cfg, logger, &TestArgoCDFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	t.Run("Reachable repository", func(t *testing.T) {
+		require.NoError(t, service.TestRepositoryConnection(ctx, reachableRepo, "private-repo-creds"))
+		assert.Equal(t, "Bearer argocd-token", authorization)
+	})
+
+	t.Run("Failed connection state", func(t *testing.T) {
+		err := service.TestRepositoryConnection(ctx, unreachableRepo, "private-repo-creds")
+		var unreachable *RepositoryUnreachableError
+		require.ErrorAs(t, err, &unreachable)
+		assert.Equal(t, unreachableRepo, unreachable.Repository)
+		assert.Equal(t, "authentication required", unreachable.Reason)
+	})
+
+	t.Run("Repository not configured in ArgoCD", func(t *testing.T) {
+		err := service.TestRepositoryConnection(ctx, "https://github.com/test/unknown", "private-repo-creds")
+		var unreachable *RepositoryUnreachableError
+		require.ErrorAs(t, err, &unreachable)
+	})
+
+	t.Run("Missing credentials secret", func(t *testing.T) {
+		err := service.TestRepositoryConnection(ctx, reachableRepo, "missing-creds")
+		var unreachable *RepositoryUnreachableError
+		require.ErrorAs(t, err, &unreachable)
+		assert.Contains(t, unreachable.Reason, "missing-creds")
+	})
+}
+
+func TestArgoCDServerURL(t *testing.T) {
+	assert.Equal(t, "https://argocd-server.argocd.svc.cluster.local", argoCDServerURL("argocd-server.argocd.svc.cluster.local"))
+	assert.Equal(t, "http://localhost:8080", argoCDServerURL("http://localhost:8080/"))
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// BackfillRepoHashLabels labels managed AppProjects created before the repository hash label existed, or
+// before security.repositoryHashLength changed, so that repository conflict detection can find them. The
+// hash is computed from the first source repository; AppProjects that already carry a label of the
+// configured length or have no source repositories are left alone.
+// It returns the names of the updated AppProjects. Failures are collected without stopping the scan.
+func BackfillRepoHashLabels(
+	ctx context.Context, argocd ArgoCDService, cfg *config.Config, logger *logrus.Logger,
+) ([]string, error) {
+	projects, err := argocd.ListManagedAppProjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list managed AppProjects: %w", err)
+	}
+
+	updated := []string{}
+	var errs []error
+	for i := range projects {
+		project := &projects[i]
+		// Labels of another length predate a security.repositoryHashLength change and no longer match lookups
+		hash := project.Labels[repositoryHashLabel(cfg)]
+		if (hash != "" && len(hash) == repositoryHashLength(cfg)) || len(project.SourceRepos) == 0 {
+			continue
+		}
+
+		changed, err := argocd.EnsureRepoHashLabel(ctx, project.Name, project.SourceRepos[0])
+		if err != nil {
+			logger.WithError(err).WithField("project", project.Name).Warn("Failed to backfill repository hash label")
+			errs = append(errs, err)
+			continue
+		}
+		if changed {
+			updated = append(updated, project.Name)
+		}
+	}
+
+	logger.WithFields(logrus.Fields{
+		"scanned": len(projects),
+		"updated": len(updated),
+		"failed":  len(errs),
+	}).Info("Repository hash label backfill finished")
+	return updated, errors.Join(errs...)
+}
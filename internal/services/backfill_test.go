@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+)
+
+// newBackfillAppProject builds an AppProject resource with the given labels and source repositories
+func newBackfillAppProject(name string, labels map[string]interface{}, sourceRepos ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "AppProject",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "argocd",
+				"labels":    labels,
+			},
+			"spec": map[string]interface{}{
+				"sourceRepos": sourceRepos,
+			},
+		},
+	}
+}
+
+func TestBackfillRepoHashLabels(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{ArgoCD: config.ArgoCDConfig{Namespace: "argocd"}}
+	ctx := context.Background()
+
+	legacyRepo := "https://github.com/test/legacy"
+	labeledRepo := "https://github.com/test/labeled"
+
+	fakeClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{appProjectGVR: "AppProjectList"},
+		newBackfillAppProject("team-legacy", map[string]interface{}{
+			ManagedByLabel: GitOpsRegistrationService,
+		}, legacyRepo),
+		newBackfillAppProject("team-labeled", map[string]interface{}{
+			ManagedByLabel:      GitOpsRegistrationService,
+			RepositoryHashLabel: GenerateRepositoryHash(labeledRepo),
+		}, labeledRepo),
+		newBackfillAppProject("team-no-repos", map[string]interface{}{
+			ManagedByLabel: GitOpsRegistrationService,
+		}),
+		newBackfillAppProject("unmanaged", map[string]interface{}{}, "https://github.com/test/unmanaged"),
+	)
+
+	service, err := NewArgoCDServiceWithFactory(cfg, logger, &TestArgoCDFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	labelOf := func(name string) string {
+		project, err := fakeClient.Resource(appProjectGVR).Namespace("argocd").Get(ctx, name, metav1.GetOptions{})
+		require.NoError(t, err)
+		return project.GetLabels()[RepositoryHashLabel]
+	}
+
+	updated, err := BackfillRepoHashLabels(ctx, service, &config.Config{}, logger)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-legacy"}, updated)
+
+	assert.Equal(t, GenerateRepositoryHash(legacyRepo), labelOf("team-legacy"))
+	assert.Equal(t, GenerateRepositoryHash(labeledRepo), labelOf("team-labeled"))
+	assert.Empty(t, labelOf("team-no-repos"))
+	assert.Empty(t, labelOf("unmanaged"))
+
+	// Conflict detection now finds the legacy project
+	exists, err := service.CheckAppProjectConflict(ctx, GenerateRepositoryHash(legacyRepo))
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	// Running again changes nothing
+	updated, err = BackfillRepoHashLabels(ctx, service, &config.Config{}, logger)
+	require.NoError(t, err)
+	assert.Empty(t, updated)
+}
+
+func TestBackfillRepoHashLabels_HashLengthChanged(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{
+		ArgoCD:   config.ArgoCDConfig{Namespace: "argocd"},
+		Security: config.SecurityConfig{RepositoryHashLength: 32},
+	}
+	ctx := context.Background()
+
+	repoURL := "https://github.com/test/short"
+	fakeClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{appProjectGVR: "AppProjectList"},
+		newBackfillAppProject("team-short", map[string]interface{}{
+			ManagedByLabel:      GitOpsRegistrationService,
+			RepositoryHashLabel: GenerateRepositoryHash(repoURL),
+		}, repoURL),
+	)
+	service, err := NewArgoCDServiceWithFactory(cfg, logger, &TestArgoCDFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	updated, err := BackfillRepoHashLabels(ctx, service, cfg, logger)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-short"}, updated)
+
+	exists, err := service.CheckAppProjectConflict(ctx, GenerateRepositoryHashWithLength(repoURL, 32))
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	updated, err = BackfillRepoHashLabels(ctx, service, cfg, logger)
+	require.NoError(t, err)
+	assert.Empty(t, updated)
+}
+
+func TestBackfillRepoHashLabels_Errors(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	t.Run("List failure", func(t *testing.T) {
+		mockArgoCD := new(MockArgoCDService)
+		mockArgoCD.On("ListManagedAppProjects", ctx).Return([]types.AppProject(nil), errors.New("connection refused"))
+
+		_, err := BackfillRepoHashLabels(ctx, mockArgoCD, &config.Config{}, logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to list managed AppProjects")
+	})
+
+	t.Run("Label failure does not stop the scan", func(t *testing.T) {
+		mockArgoCD := new(MockArgoCDService)
+		mockArgoCD.On("ListManagedAppProjects", ctx).Return([]types.AppProject{
+			{Name: "team-a", SourceRepos: []string{"https://github.com/test/a"}},
+			{Name: "team-b", SourceRepos: []string{"https://github.com/test/b"}},
+		}, nil)
+		mockArgoCD.On("EnsureRepoHashLabel", ctx, "team-a", "https://github.com/test/a").Return(false, errors.New("forbidden"))
+		mockArgoCD.On("EnsureRepoHashLabel", ctx, "team-b", "https://github.com/test/b").Return(true, nil)
+
+		updated, err := BackfillRepoHashLabels(ctx, mockArgoCD, &config.Config{}, logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "forbidden")
+		assert.Equal(t, []string{"team-b"}, updated)
+		mockArgoCD.AssertExpectations(t)
+	})
+}
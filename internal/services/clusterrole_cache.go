@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// parseClusterRoleCacheTTL converts security.clusterRoleCacheTTL; empty or invalid values disable caching
+func parseClusterRoleCacheTTL(raw string, logger *logrus.Logger) time.Duration {
+	if raw == "" {
+		return 0
+	}
+
+	ttl, err := time.ParseDuration(raw)
+	if err != nil || ttl < 0 {
+		logger.WithField("clusterRoleCacheTTL", raw).Warn("Invalid ClusterRole cache TTL, validating on every call")
+		return 0
+	}
+	return ttl
+}
+
+// clusterRoleCacheEntry is a validation result and the time it stops being reused
+type clusterRoleCacheEntry struct {
+	validation *ClusterRoleValidation
+	expiresAt  time.Time
+}
+
+// clusterRoleCachingKubernetesService reuses ValidateClusterRole results per role name for a TTL, so validating
+// the impersonation role on every registration does not read it from the API server each time. Other calls
+// go straight to the wrapped service.
+type clusterRoleCachingKubernetesService struct {
+	KubernetesService
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]clusterRoleCacheEntry
+
+	now func() time.Time // Overridden in tests
+}
+
+// WithClusterRoleCache wraps a KubernetesService so ValidateClusterRole results are reused for ttl; zero disables it
+func WithClusterRoleCache(next KubernetesService, ttl time.Duration) KubernetesService {
+	if ttl <= 0 {
+		return next
+	}
+	return &clusterRoleCachingKubernetesService{
+		KubernetesService: next,
+		ttl:               ttl,
+		entries:           map[string]clusterRoleCacheEntry{},
+		now:               time.Now,
+	}
+}
+
+// ValidateClusterRole returns the cached validation of name while it is valid, and validates and caches it
+// otherwise. Errors are not cached. Callers get a copy so they cannot change the cached result.
+func (c *clusterRoleCachingKubernetesService) ValidateClusterRole(ctx context.Context, name string) (*ClusterRoleValidation, error) {
+	c.mu.Lock()
+	entry, found := c.entries[name]
+	c.mu.Unlock()
+	if found && c.now().Before(entry.expiresAt) {
+		return copyClusterRoleValidation(entry.validation), nil
+	}
+
+	validation, err := c.KubernetesService.ValidateClusterRole(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[name] = clusterRoleCacheEntry{
+		validation: copyClusterRoleValidation(validation),
+		expiresAt:  c.now().Add(c.ttl),
+	}
+	c.mu.Unlock()
+	return validation, nil
+}
+
+// copyClusterRoleValidation returns a copy of validation that shares no slices with it
+func copyClusterRoleValidation(validation *ClusterRoleValidation) *ClusterRoleValidation {
+	if validation == nil {
+		return nil
+	}
+	copied := *validation
+	copied.Warnings = slices.Clone(validation.Warnings)
+	copied.ResourceTypes = slices.Clone(validation.ResourceTypes)
+	return &copied
+}
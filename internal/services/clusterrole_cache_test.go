@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newCountingClusterRoleService returns a caching KubernetesService over a fake client serving the gitops-deployer
+// ClusterRole, and a counter of the ClusterRole reads that reached the client
+func newCountingClusterRoleService(t *testing.T, ttl time.Duration) (*clusterRoleCachingKubernetesService, *fake.Clientset, *atomic.Int32) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	client := fake.NewSimpleClientset(&rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "gitops-deployer"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "create"}},
+		},
+	})
+	var gets atomic.Int32
+	client.PrependReactor("get", "clusterroles", func(k8stesting.Action) (bool, runtime.Object, error) {
+		gets.Add(1)
+		return false, nil, nil
+	})
+
+	k8sService, err := NewKubernetesServiceWithFactory(&config.Config{}, logger, &TestKubernetesFactory{Client: client})
+	require.NoError(t, err)
+	cached, ok := WithClusterRoleCache(k8sService, ttl).(*clusterRoleCachingKubernetesService)
+	require.True(t, ok)
+	return cached, client, &gets
+}
+
+func TestClusterRoleCache_ReusesResultWithinTTL(t *testing.T) {
+	ctx := context.Background()
+	service, _, gets := newCountingClusterRoleService(t, time.Minute)
+	now := time.Now()
+	service.now = func() time.Time { return now }
+
+	first, err := service.ValidateClusterRole(ctx, "gitops-deployer")
+	require.NoError(t, err)
+	assert.True(t, first.Exists)
+	assert.Equal(t, int32(1), gets.Load())
+
+	now = now.Add(59 * time.Second)
+	second, err := service.ValidateClusterRole(ctx, "gitops-deployer")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, int32(1), gets.Load(), "a call within the TTL should not reach the API server")
+
+	now = now.Add(2 * time.Second)
+	_, err = service.ValidateClusterRole(ctx, "gitops-deployer")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), gets.Load(), "a call after the TTL should read the ClusterRole again")
+}
+
+func TestClusterRoleCache_KeyedByName(t *testing.T) {
+	ctx := context.Background()
+	service, _, gets := newCountingClusterRoleService(t, time.Minute)
+
+	validation, err := service.ValidateClusterRole(ctx, "missing-role")
+	require.NoError(t, err)
+	assert.False(t, validation.Exists)
+
+	validation, err = service.ValidateClusterRole(ctx, "gitops-deployer")
+	require.NoError(t, err)
+	assert.True(t, validation.Exists)
+	assert.Equal(t, int32(2), gets.Load())
+
+	_, err = service.ValidateClusterRole(ctx, "missing-role")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), gets.Load())
+}
+
+func TestClusterRoleCache_ErrorsAreNotCached(t *testing.T) {
+	ctx := context.Background()
+	service, client, _ := newCountingClusterRoleService(t, time.Minute)
+
+	failing := true
+	client.PrependReactor("get", "clusterroles", func(k8stesting.Action) (bool, runtime.Object, error) {
+		if failing {
+			return true, nil, errors.New("connection refused")
+		}
+		return false, nil, nil
+	})
+
+	_, err := service.ValidateClusterRole(ctx, "gitops-deployer")
+	require.Error(t, err)
+
+	failing = false
+	validation, err := service.ValidateClusterRole(ctx, "gitops-deployer")
+	require.NoError(t, err)
+	assert.True(t, validation.Exists)
+}
+
+func TestClusterRoleCache_CallersCannotChangeCachedResult(t *testing.T) {
+	ctx := context.Background()
+	service, _, _ := newCountingClusterRoleService(t, time.Minute)
+
+	validation, err := service.ValidateClusterRole(ctx, "gitops-deployer")
+	require.NoError(t, err)
+	validation.Exists = false
+	validation.ResourceTypes[0] = "changed"
+
+	cached, err := service.ValidateClusterRole(ctx, "gitops-deployer")
+	require.NoError(t, err)
+	assert.True(t, cached.Exists)
+	assert.Equal(t, []string{"deployments"}, cached.ResourceTypes)
+}
+
+func TestWithClusterRoleCache_Disabled(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	next := &kubernetesServiceStub{logger: logger}
+
+	assert.Same(t, next, WithClusterRoleCache(next, 0))
+	assert.Equal(t, time.Duration(0), parseClusterRoleCacheTTL("", logger))
+	assert.Equal(t, time.Duration(0), parseClusterRoleCacheTTL("soon", logger))
+	assert.Equal(t, 30*time.Second, parseClusterRoleCacheTTL("30s", logger))
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+)
+
+// FindRepositoryHashCollisions groups managed AppProjects by their repository hash label and reports every hash
+// shared by AppProjects of different repositories. The truncated hash makes such collisions possible, and
+// conflict detection then wrongly blocks the second repository. An AppProject's repository is its first
+// source repository; AppProjects without the label or without source repositories are skipped.
+func FindRepositoryHashCollisions(
+	ctx context.Context, argocd ArgoCDService, cfg *config.Config,
+) ([]types.RepositoryHashCollision, error) {
+	projects, err := argocd.ListManagedAppProjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list managed AppProjects: %w", err)
+	}
+
+	projectsByHash := make(map[string][]*types.AppProject)
+	for i := range projects {
+		project := &projects[i]
+		hash := project.Labels[repositoryHashLabel(cfg)]
+		if hash == "" || len(project.SourceRepos) == 0 {
+			continue
+		}
+		projectsByHash[hash] = append(projectsByHash[hash], project)
+	}
+
+	collisions := []types.RepositoryHashCollision{}
+	for hash, hashProjects := range projectsByHash {
+		repositories := make(map[string]bool)
+		for _, project := range hashProjects {
+			repositories[project.SourceRepos[0]] = true
+		}
+		if len(repositories) < 2 {
+			continue
+		}
+
+		collision := types.RepositoryHashCollision{Hash: hash}
+		for repository := range repositories {
+			collision.Repositories = append(collision.Repositories, repository)
+		}
+		for _, project := range hashProjects {
+			collision.AppProjects = append(collision.AppProjects, project.Name)
+		}
+		sort.Strings(collision.Repositories)
+		sort.Strings(collision.AppProjects)
+		collisions = append(collisions, collision)
+	}
+
+	sort.Slice(collisions, func(i, j int) bool { return collisions[i].Hash < collisions[j].Hash })
+	return collisions, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+)
+
+func TestFindRepositoryHashCollisions(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{ArgoCD: config.ArgoCDConfig{Namespace: "argocd"}}
+	ctx := context.Background()
+
+	managed := func(hash string) map[string]interface{} {
+		labels := map[string]interface{}{ManagedByLabel: GitOpsRegistrationService}
+		if hash != "" {
+			labels[RepositoryHashLabel] = hash
+		}
+		return labels
+	}
+	newService := func(t *testing.T, objects ...runtime.Object) ArgoCDService {
+		fakeClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{appProjectGVR: "AppProjectList"}, objects...)
+		service, err := NewArgoCDServiceWithFactory(cfg, logger, &TestArgoCDFactory{Client: fakeClient})
+		require.NoError(t, err)
+		return service
+	}
+
+	t.Run("Collision between different repositories", func(t *testing.T) {
+		service := newService(t,
+			newBackfillAppProject("team-a", managed("abc12345"), "https://github.com/test/a"),
+			newBackfillAppProject("team-b", managed("abc12345"), "https://github.com/test/b", "https://github.com/test/a"),
+			newBackfillAppProject("team-c", managed(GenerateRepositoryHash("https://github.com/test/c")), "https://github.com/test/c"),
+		)
+
+		collisions, err := FindRepositoryHashCollisions(ctx, service, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, []types.RepositoryHashCollision{{
+			Hash:         "abc12345",
+			Repositories: []string{"https://github.com/test/a", "https://github.com/test/b"},
+			AppProjects:  []string{"team-a", "team-b"},
+		}}, collisions)
+	})
+
+	t.Run("Clean set", func(t *testing.T) {
+		monoRepo := "https://github.com/test/mono"
+		service := newService(t,
+			newBackfillAppProject("team-a", managed(GenerateRepositoryHash("https://github.com/test/a")), "https://github.com/test/a"),
+			// The same repository under one hash, e.g. a stale duplicate, is not a collision
+			newBackfillAppProject("team-mono", managed(GenerateRepositoryHash(monoRepo)), monoRepo),
+			newBackfillAppProject("team-mono-old", managed(GenerateRepositoryHash(monoRepo)), monoRepo),
+			newBackfillAppProject("team-legacy", managed(""), "https://github.com/test/legacy"),
+			newBackfillAppProject("team-no-repos", managed("abc12345")),
+		)
+
+		collisions, err := FindRepositoryHashCollisions(ctx, service, cfg)
+		require.NoError(t, err)
+		assert.Empty(t, collisions)
+		assert.NotNil(t, collisions)
+	})
+}
@@ -1,12 +1,16 @@
 package services
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/dynamic"
 	fakedynamic "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
 )
 
 // KubernetesClientFactory creates Kubernetes clients for services
@@ -19,6 +23,7 @@ type KubernetesClientFactory interface {
 type ArgoCDClientFactory interface {
 	CreateConfig() (*rest.Config, error)
 	CreateDynamicClient(*rest.Config) (dynamic.Interface, error)
+	CreateDiscoveryClient(*rest.Config) (discovery.DiscoveryInterface, error)
 }
 
 // Production implementations
@@ -45,6 +50,10 @@ func (f *InClusterArgoCDFactory) CreateDynamicClient(config *rest.Config) (dynam
 	return dynamic.NewForConfig(config)
 }
 
+func (f *InClusterArgoCDFactory) CreateDiscoveryClient(config *rest.Config) (discovery.DiscoveryInterface, error) {
+	return discovery.NewDiscoveryClientForConfig(config)
+}
+
 // Test implementations
 
 // TestKubernetesFactory creates fake Kubernetes clients for testing
@@ -78,10 +87,11 @@ func (f *TestKubernetesFactory) CreateClientset(config *rest.Config) (kubernetes
 
 // TestArgoCDFactory creates fake ArgoCD clients for testing
 type TestArgoCDFactory struct {
-	Client dynamic.Interface
-	Config *rest.Config
-	Error  error           // Error to return from CreateConfig or CreateDynamicClient
-	Scheme *runtime.Scheme // Optional scheme for fake client
+	Client    dynamic.Interface
+	Discovery discovery.DiscoveryInterface // Defaults to a fake reporting the ArgoCD CRDs as installed
+	Config    *rest.Config
+	Error     error           // Error to return from CreateConfig, CreateDynamicClient or CreateDiscoveryClient
+	Scheme    *runtime.Scheme // Optional scheme for fake client
 }
 
 func (f *TestArgoCDFactory) CreateConfig() (*rest.Config, error) {
@@ -110,6 +120,33 @@ func (f *TestArgoCDFactory) CreateDynamicClient(config *rest.Config) (dynamic.In
 	return fakedynamic.NewSimpleDynamicClient(scheme), nil
 }
 
+func (f *TestArgoCDFactory) CreateDiscoveryClient(config *rest.Config) (discovery.DiscoveryInterface, error) {
+	if f.Error != nil {
+		return nil, f.Error
+	}
+	if f.Discovery != nil {
+		return f.Discovery, nil
+	}
+	return NewFakeArgoCDDiscovery(true), nil
+}
+
+// NewFakeArgoCDDiscovery creates a fake discovery client that reports whether the ArgoCD CRDs are installed
+func NewFakeArgoCDDiscovery(installed bool) discovery.DiscoveryInterface {
+	fakeClient := &clienttesting.Fake{}
+	if installed {
+		fakeClient.Resources = []*metav1.APIResourceList{
+			{
+				GroupVersion: appProjectGVR.GroupVersion().String(),
+				APIResources: []metav1.APIResource{
+					{Name: appProjectGVR.Resource, Kind: "AppProject", Namespaced: true},
+					{Name: applicationGVR.Resource, Kind: "Application", Namespaced: true},
+				},
+			},
+		}
+	}
+	return &fakediscovery.FakeDiscovery{Fake: fakeClient}
+}
+
 // Helper functions for creating pre-configured test factories
 
 // NewTestKubernetesFactory creates a test factory with a fake Kubernetes client
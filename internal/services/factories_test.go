@@ -194,6 +194,22 @@ func TestTestArgoCDFactory(t *testing.T) {
 		assert.Error(t, err)
 		assert.Equal(t, testError, err)
 		assert.Nil(t, client)
+
+		discoveryClient, err := factory.CreateDiscoveryClient(&rest.Config{})
+		assert.Error(t, err)
+		assert.Equal(t, testError, err)
+		assert.Nil(t, discoveryClient)
+	})
+
+	t.Run("Default discovery reports ArgoCD CRDs", func(t *testing.T) {
+		factory := NewTestArgoCDFactory()
+
+		discoveryClient, err := factory.CreateDiscoveryClient(&rest.Config{})
+		require.NoError(t, err)
+
+		resources, err := discoveryClient.ServerResourcesForGroupVersion("argoproj.io/v1alpha1")
+		require.NoError(t, err)
+		assert.Len(t, resources.APIResources, 2)
 	})
 }
 
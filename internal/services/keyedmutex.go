@@ -0,0 +1,42 @@
+package services
+
+import "sync"
+
+// keyedMutex serializes callers that share a key while letting different keys proceed concurrently.
+// Entries are removed once no caller holds or waits for them. The zero value is ready to use.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*refCountedMutex
+}
+
+type refCountedMutex struct {
+	sync.Mutex
+	refs int
+}
+
+// Lock blocks until the lock for key is held and returns the function that releases it
+func (k *keyedMutex) Lock(key string) (unlock func()) {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*refCountedMutex)
+	}
+	lock, ok := k.locks[key]
+	if !ok {
+		lock = &refCountedMutex{}
+		k.locks[key] = lock
+	}
+	lock.refs++
+	k.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+
+		k.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyedMutex_SameKeySerializes(t *testing.T) {
+	var locks keyedMutex
+	var active, maxActive int32
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer locks.Lock("team-a")()
+
+			current := atomic.AddInt32(&active, 1)
+			for {
+				seen := atomic.LoadInt32(&maxActive)
+				if current <= seen || atomic.CompareAndSwapInt32(&maxActive, seen, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&active, -1)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), maxActive)
+	assert.Empty(t, locks.locks, "released locks are removed")
+}
+
+func TestKeyedMutex_DifferentKeysProceed(t *testing.T) {
+	var locks keyedMutex
+
+	unlockA := locks.Lock("team-a")
+	defer unlockA()
+
+	acquired := make(chan struct{})
+	go func() {
+		defer locks.Lock("team-b")()
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("lock for a different key was blocked")
+	}
+}
@@ -3,21 +3,50 @@ package services
 import (
 	"context"
 	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
 	"github.com/sirupsen/logrus"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
 )
 
 // Constants for commonly used strings
 const (
 	GitOpsRegistrationService = "gitops-registration-service"
+	ManagedByLabel            = "gitops.io/managed-by"
+	ManagedByLabelSelector    = ManagedByLabel + "=" + GitOpsRegistrationService
+	OwnerLabel                = "gitops.io/owner"
+	TeamLabel                 = "gitops.io/team"
+	RepositoryOrgLabel        = "gitops.io/repository-org"
+	MetadataAnnotationPrefix  = "gitops.io/meta-"
+	MetadataFilterPrefix      = "meta."
+	NamespaceFinalizer        = "gitops.io/registration-protection"
+	TenantLimitRangeName      = "gitops-tenant-defaults"
 )
 
+// NotManagedError is returned when a mutation targets a namespace this service does not own
+type NotManagedError struct {
+	Namespace string
+}
+
+func (e *NotManagedError) Error() string {
+	return fmt.Sprintf("namespace %s is not managed by %s", e.Namespace, GitOpsRegistrationService)
+}
+
 // kubernetesService is the real implementation of KubernetesService
 type kubernetesService struct {
 	client kubernetes.Interface
@@ -61,7 +90,14 @@ func (k *kubernetesService) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
+// errEmptyNamespaceName rejects an empty namespace name before it reaches the API server, whose error for it
+// does not name the problem
+var errEmptyNamespaceName = fmt.Errorf("namespace name must not be empty")
+
 func (k *kubernetesService) CreateNamespace(ctx context.Context, name string, labels map[string]string) error {
+	if name == "" {
+		return errEmptyNamespaceName
+	}
 	k.logger.WithField("namespace", name).Info("Creating namespace")
 
 	// Set up default labels
@@ -92,6 +128,9 @@ func (k *kubernetesService) CreateNamespace(ctx context.Context, name string, la
 }
 
 func (k *kubernetesService) CreateNamespaceWithMetadata(ctx context.Context, name string, labels, annotations map[string]string) error {
+	if name == "" {
+		return errEmptyNamespaceName
+	}
 	k.logger.WithField("namespace", name).Info("Creating namespace with metadata")
 
 	// Set up default labels
@@ -134,6 +173,25 @@ func (k *kubernetesService) CreateNamespaceWithMetadata(ctx context.Context, nam
 func (k *kubernetesService) DeleteNamespace(ctx context.Context, name string) error {
 	k.logger.WithField("namespace", name).Info("Deleting namespace")
 
+	if k.managedLabelRequired() {
+		namespace, err := k.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				k.logger.WithField("namespace", name).Info("Namespace already deleted")
+				return nil
+			}
+			return fmt.Errorf("failed to get namespace %s: %w", name, err)
+		}
+		if err := k.requireManaged(namespace); err != nil {
+			return err
+		}
+	}
+
+	// Release the protection finalizer so a deletion requested through the service can complete
+	if err := k.RemoveNamespaceFinalizer(ctx, name); err != nil {
+		return err
+	}
+
 	err := k.client.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -147,29 +205,39 @@ func (k *kubernetesService) DeleteNamespace(ctx context.Context, name string) er
 	return nil
 }
 
+// UpdateNamespaceLabels merges labels into a managed namespace. The namespace is read again and the update
+// retried when another writer changed it in between.
 func (k *kubernetesService) UpdateNamespaceLabels(ctx context.Context, name string, labels map[string]string) error {
 	k.logger.WithField("namespace", name).Info("Updating namespace labels")
 
-	// Get the current namespace
-	namespace, err := k.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to get namespace %s: %w", name, err)
-	}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Get the current namespace
+		namespace, err := k.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get namespace %s: %w", name, err)
+		}
+		if err := k.requireManaged(namespace); err != nil {
+			return err
+		}
 
-	// Initialize labels if nil
-	if namespace.Labels == nil {
-		namespace.Labels = make(map[string]string)
-	}
+		// Initialize labels if nil
+		if namespace.Labels == nil {
+			namespace.Labels = make(map[string]string)
+		}
 
-	// Merge the new labels with existing ones
-	for key, value := range labels {
-		namespace.Labels[key] = value
-	}
+		// Merge the new labels with existing ones
+		for key, value := range labels {
+			namespace.Labels[key] = value
+		}
 
-	// Update the namespace
-	_, err = k.client.CoreV1().Namespaces().Update(ctx, namespace, metav1.UpdateOptions{})
+		// Update the namespace
+		if _, err := k.client.CoreV1().Namespaces().Update(ctx, namespace, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update namespace %s labels: %w", name, err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to update namespace %s labels: %w", name, err)
+		return err
 	}
 
 	k.logger.WithFields(logrus.Fields{
@@ -180,43 +248,204 @@ func (k *kubernetesService) UpdateNamespaceLabels(ctx context.Context, name stri
 }
 
 func (k *kubernetesService) UpdateNamespaceMetadata(ctx context.Context, name string, labels, annotations map[string]string) error {
+	return k.updateNamespaceMetadata(ctx, name, labels, annotations, k.requireManaged)
+}
+
+// ClaimNamespace applies metadata to an existing namespace that is not yet managed, as existing-namespace
+// registration does. With the managed-label guard enabled, only namespaces matching
+// security.claimableNamespaces can be claimed.
+func (k *kubernetesService) ClaimNamespace(ctx context.Context, name string, labels, annotations map[string]string) error {
+	return k.updateNamespaceMetadata(ctx, name, labels, annotations, k.requireClaimable)
+}
+
+// UnclaimNamespace reverts the label and annotation keys in claimed: keys that original has get their original
+// value back and the rest are removed. Other keys are left alone, so changes made since the claim survive. A
+// namespace that no longer exists has nothing to revert.
+func (k *kubernetesService) UnclaimNamespace(ctx context.Context, name string, claimed, original NamespaceMetadata) error {
+	k.logger.WithField("namespace", name).Info("Reverting namespace metadata")
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		namespace, err := k.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to get namespace %s: %w", name, err)
+		}
+		if err := k.requireClaimable(namespace); err != nil {
+			return err
+		}
+
+		namespace.Labels = revertMetadata(namespace.Labels, claimed.Labels, original.Labels)
+		namespace.Annotations = revertMetadata(namespace.Annotations, claimed.Annotations, original.Annotations)
+
+		if _, err := k.client.CoreV1().Namespaces().Update(ctx, namespace, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to revert namespace %s metadata: %w", name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	k.logger.WithField("namespace", name).Info("Successfully reverted namespace metadata")
+	return nil
+}
+
+// revertMetadata sets each key of claimed in current back to its value in original, deleting keys original
+// does not have
+func revertMetadata(current, claimed, original map[string]string) map[string]string {
+	for key := range claimed {
+		value, ok := original[key]
+		if !ok {
+			delete(current, key)
+			continue
+		}
+		if current == nil {
+			current = make(map[string]string)
+		}
+		current[key] = value
+	}
+	return current
+}
+
+// updateNamespaceMetadata merges labels and annotations into a namespace that passes guard. The namespace is
+// read again and the update retried when another writer changed it in between.
+func (k *kubernetesService) updateNamespaceMetadata(ctx context.Context, name string, labels, annotations map[string]string,
+	guard func(*corev1.Namespace) error) error {
 	k.logger.WithField("namespace", name).Info("Updating namespace metadata")
 
-	// Get the current namespace
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Get the current namespace
+		namespace, err := k.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get namespace %s: %w", name, err)
+		}
+		if err := guard(namespace); err != nil {
+			return err
+		}
+
+		// Initialize labels and annotations if nil
+		if namespace.Labels == nil {
+			namespace.Labels = make(map[string]string)
+		}
+		if namespace.Annotations == nil {
+			namespace.Annotations = make(map[string]string)
+		}
+
+		// Merge the new labels with existing ones
+		for key, value := range labels {
+			namespace.Labels[key] = value
+		}
+
+		// Merge the new annotations with existing ones
+		for key, value := range annotations {
+			namespace.Annotations[key] = value
+		}
+
+		// Update the namespace
+		if _, err := k.client.CoreV1().Namespaces().Update(ctx, namespace, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update namespace %s metadata: %w", name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	k.logger.WithFields(logrus.Fields{
+		"namespace":   name,
+		"labels":      labels,
+		"annotations": annotations,
+	}).Info("Successfully updated namespace metadata")
+	return nil
+}
+
+// managedLabelRequired reports whether mutations are limited to namespaces carrying the managed-by label
+func (k *kubernetesService) managedLabelRequired() bool {
+	return k.cfg != nil && k.cfg.Security.RequireManagedLabelForMutation
+}
+
+// requireManaged returns a NotManagedError when the guard is enabled and the namespace's current labels
+// do not mark it as owned by this service
+func (k *kubernetesService) requireManaged(namespace *corev1.Namespace) error {
+	if !k.managedLabelRequired() || namespace.Labels[ManagedByLabel] == GitOpsRegistrationService {
+		return nil
+	}
+
+	k.logger.WithField("namespace", namespace.Name).Warn("Refusing to modify namespace not managed by this service")
+	return &NotManagedError{Namespace: namespace.Name}
+}
+
+// requireClaimable is requireManaged, except that unmanaged namespaces on the claim allowlist pass
+func (k *kubernetesService) requireClaimable(namespace *corev1.Namespace) error {
+	if !k.managedLabelRequired() || namespace.Labels[ManagedByLabel] == GitOpsRegistrationService {
+		return nil
+	}
+
+	for _, pattern := range k.cfg.Security.ClaimableNamespaces {
+		if matched, err := path.Match(pattern, namespace.Name); err == nil && matched {
+			return nil
+		}
+	}
+
+	k.logger.WithField("namespace", namespace.Name).Warn("Refusing to claim namespace outside security.claimableNamespaces")
+	return &NotManagedError{Namespace: namespace.Name}
+}
+
+// AddNamespaceFinalizer adds the registration protection finalizer to a namespace
+func (k *kubernetesService) AddNamespaceFinalizer(ctx context.Context, name string) error {
+	k.logger.WithField("namespace", name).Info("Adding namespace finalizer")
+
 	namespace, err := k.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to get namespace %s: %w", name, err)
 	}
 
-	// Initialize labels and annotations if nil
-	if namespace.Labels == nil {
-		namespace.Labels = make(map[string]string)
+	if slices.Contains(namespace.Finalizers, NamespaceFinalizer) {
+		return nil
+	}
+
+	namespace.Finalizers = append(namespace.Finalizers, NamespaceFinalizer)
+	_, err = k.client.CoreV1().Namespaces().Update(ctx, namespace, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to add finalizer to namespace %s: %w", name, err)
 	}
-	if namespace.Annotations == nil {
-		namespace.Annotations = make(map[string]string)
+
+	k.logger.WithField("namespace", name).Info("Successfully added namespace finalizer")
+	return nil
+}
+
+// RemoveNamespaceFinalizer removes the registration protection finalizer from a namespace
+func (k *kubernetesService) RemoveNamespaceFinalizer(ctx context.Context, name string) error {
+	k.logger.WithField("namespace", name).Info("Removing namespace finalizer")
+
+	namespace, err := k.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get namespace %s: %w", name, err)
 	}
 
-	// Merge the new labels with existing ones
-	for key, value := range labels {
-		namespace.Labels[key] = value
+	if !slices.Contains(namespace.Finalizers, NamespaceFinalizer) {
+		return nil
 	}
 
-	// Merge the new annotations with existing ones
-	for key, value := range annotations {
-		namespace.Annotations[key] = value
+	finalizers := make([]string, 0, len(namespace.Finalizers))
+	for _, finalizer := range namespace.Finalizers {
+		if finalizer != NamespaceFinalizer {
+			finalizers = append(finalizers, finalizer)
+		}
 	}
+	namespace.Finalizers = finalizers
 
-	// Update the namespace
 	_, err = k.client.CoreV1().Namespaces().Update(ctx, namespace, metav1.UpdateOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to update namespace %s metadata: %w", name, err)
+		return fmt.Errorf("failed to remove finalizer from namespace %s: %w", name, err)
 	}
 
-	k.logger.WithFields(logrus.Fields{
-		"namespace":   name,
-		"labels":      labels,
-		"annotations": annotations,
-	}).Info("Successfully updated namespace metadata")
+	k.logger.WithField("namespace", name).Info("Successfully removed namespace finalizer")
 	return nil
 }
 
@@ -231,6 +460,31 @@ func (k *kubernetesService) NamespaceExists(ctx context.Context, name string) (b
 	return true, nil
 }
 
+// GetNamespaceUID returns the UID of a namespace, used to build owner references
+func (k *kubernetesService) GetNamespaceUID(ctx context.Context, name string) (string, error) {
+	namespace, err := k.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get namespace %s: %w", name, err)
+	}
+	return string(namespace.UID), nil
+}
+
+// GetNamespaceMetadata returns the labels and annotations of a namespace
+func (k *kubernetesService) GetNamespaceMetadata(ctx context.Context, name string) (*NamespaceInfo, error) {
+	namespace, err := k.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace %s: %w", name, err)
+	}
+	return &NamespaceInfo{
+		Name:              namespace.Name,
+		Labels:            namespace.Labels,
+		Annotations:       namespace.Annotations,
+		CreationTimestamp: namespace.CreationTimestamp.Time,
+		Terminating:       namespace.DeletionTimestamp != nil || namespace.Status.Phase == corev1.NamespaceTerminating,
+		Active:            namespace.Status.Phase == corev1.NamespaceActive,
+	}, nil
+}
+
 func (k *kubernetesService) CountNamespaces(ctx context.Context) (int, error) {
 	namespaces, err := k.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -239,6 +493,141 @@ func (k *kubernetesService) CountNamespaces(ctx context.Context) (int, error) {
 	return len(namespaces.Items), nil
 }
 
+// CountManagedNamespaces counts only namespaces managed by this service, for capacity enforcement
+func (k *kubernetesService) CountManagedNamespaces(ctx context.Context) (int, error) {
+	namespaces, err := k.ListManagedNamespaces(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(namespaces), nil
+}
+
+// CountNamespacesByOwner returns the number of managed namespaces whose owner label matches owner.
+// Terminating namespaces are not counted.
+func (k *kubernetesService) CountNamespacesByOwner(ctx context.Context, owner string) (int, error) {
+	namespaces, err := k.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+		LabelSelector: ManagedByLabelSelector + "," + OwnerLabel + "=" + owner,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list namespaces owned by %s: %w", owner, err)
+	}
+
+	count := 0
+	for i := range namespaces.Items {
+		if namespaces.Items[i].DeletionTimestamp == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ListManagedNamespaceInfo returns the metadata of managed namespaces that also match selector, if set.
+// Terminating namespaces are left out.
+func (k *kubernetesService) ListManagedNamespaceInfo(ctx context.Context, selector string) ([]NamespaceInfo, error) {
+	labelSelector := ManagedByLabelSelector
+	if selector != "" {
+		labelSelector += "," + selector
+	}
+	namespaces, err := k.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list managed namespaces: %w", err)
+	}
+
+	infos := make([]NamespaceInfo, 0, len(namespaces.Items))
+	for i := range namespaces.Items {
+		namespace := &namespaces.Items[i]
+		if namespace.DeletionTimestamp != nil {
+			continue
+		}
+		infos = append(infos, NamespaceInfo{
+			Name:              namespace.Name,
+			Labels:            namespace.Labels,
+			Annotations:       namespace.Annotations,
+			CreationTimestamp: namespace.CreationTimestamp.Time,
+		})
+	}
+	return infos, nil
+}
+
+// ListEvents returns the Events this service recorded in namespace, oldest first
+func (k *kubernetesService) ListEvents(ctx context.Context, namespace string) ([]types.RegistrationEvent, error) {
+	list, err := k.client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events in namespace %s: %w", namespace, err)
+	}
+
+	events := make([]types.RegistrationEvent, 0, len(list.Items))
+	for i := range list.Items {
+		event := &list.Items[i]
+		if event.ReportingController != GitOpsRegistrationService && event.Source.Component != GitOpsRegistrationService {
+			continue
+		}
+		events = append(events, types.RegistrationEvent{
+			Type:          event.Type,
+			Reason:        event.Reason,
+			Message:       event.Message,
+			Kind:          event.InvolvedObject.Kind,
+			Name:          event.InvolvedObject.Name,
+			Count:         event.Count,
+			LastTimestamp: eventTime(event),
+		})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].LastTimestamp.Before(events[j].LastTimestamp) })
+	return events, nil
+}
+
+// eventTime returns when an event last occurred, whichever of the legacy and events.k8s.io fields is set
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// ListManagedNamespaces returns the names of namespaces managed by this service.
+// Terminating namespaces are left out so the reconciler treats their AppProjects as orphaned.
+func (k *kubernetesService) ListManagedNamespaces(ctx context.Context) ([]string, error) {
+	namespaces, err := k.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+		LabelSelector: ManagedByLabelSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list managed namespaces: %w", err)
+	}
+
+	names := make([]string, 0, len(namespaces.Items))
+	for i := range namespaces.Items {
+		if namespaces.Items[i].DeletionTimestamp != nil {
+			continue
+		}
+		names = append(names, namespaces.Items[i].Name)
+	}
+	return names, nil
+}
+
+// ListTerminatingNamespaces returns the names of managed namespaces that are being deleted but are still
+// held by the registration protection finalizer
+func (k *kubernetesService) ListTerminatingNamespaces(ctx context.Context) ([]string, error) {
+	namespaces, err := k.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+		LabelSelector: ManagedByLabelSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list managed namespaces: %w", err)
+	}
+
+	var names []string
+	for i := range namespaces.Items {
+		namespace := &namespaces.Items[i]
+		if namespace.DeletionTimestamp != nil && slices.Contains(namespace.Finalizers, NamespaceFinalizer) {
+			names = append(names, namespace.Name)
+		}
+	}
+	return names, nil
+}
+
 func (k *kubernetesService) CreateServiceAccount(ctx context.Context, namespace, name string) error {
 	k.logger.WithFields(logrus.Fields{
 		"namespace": namespace,
@@ -331,6 +720,132 @@ func (k *kubernetesService) CreateRoleBinding(ctx context.Context, namespace, na
 	return nil
 }
 
+// CreateLimitRange creates the tenant LimitRange that gives containers without resources the configured
+// defaults. An existing LimitRange of the same name is left as it is.
+func (k *kubernetesService) CreateLimitRange(ctx context.Context, namespace string, spec *types.LimitRangeSpec) error {
+	defaults, err := parseResourceList(spec.Default)
+	if err != nil {
+		return fmt.Errorf("invalid LimitRange default: %w", err)
+	}
+	defaultRequests, err := parseResourceList(spec.DefaultRequest)
+	if err != nil {
+		return fmt.Errorf("invalid LimitRange defaultRequest: %w", err)
+	}
+
+	limitRange := &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TenantLimitRangeName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"gitops.io/managed-by":         GitOpsRegistrationService,
+				"app.kubernetes.io/managed-by": GitOpsRegistrationService,
+				"gitops.io/tenant":             namespace,
+			},
+		},
+		Spec: corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{{
+				Type:           corev1.LimitTypeContainer,
+				Default:        defaults,
+				DefaultRequest: defaultRequests,
+			}},
+		},
+	}
+
+	_, err = k.client.CoreV1().LimitRanges(namespace).Create(ctx, limitRange, metav1.CreateOptions{})
+	if err != nil {
+		if errors.IsAlreadyExists(err) {
+			k.logger.WithField("namespace", namespace).Info("LimitRange already exists")
+			return nil
+		}
+		return fmt.Errorf("failed to create LimitRange in namespace %s: %w", namespace, err)
+	}
+
+	k.logger.WithField("namespace", namespace).Info("Successfully created LimitRange")
+	return nil
+}
+
+// parseResourceList converts resource name to quantity strings into a ResourceList
+func parseResourceList(values map[string]string) (corev1.ResourceList, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	list := make(corev1.ResourceList, len(values))
+	for name, value := range values {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		list[corev1.ResourceName(name)] = quantity
+	}
+	return list, nil
+}
+
+// DeleteServiceAccount removes a service account, treating a missing one as already deleted
+func (k *kubernetesService) DeleteServiceAccount(ctx context.Context, namespace, name string) error {
+	logger := k.logger.WithFields(logrus.Fields{
+		"namespace": namespace,
+		"name":      name,
+	})
+	logger.Info("Deleting service account")
+
+	err := k.client.CoreV1().ServiceAccounts(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("Service account already deleted")
+			return nil
+		}
+		return fmt.Errorf("failed to delete service account %s in namespace %s: %w", name, namespace, err)
+	}
+
+	logger.Info("Successfully deleted service account")
+	return nil
+}
+
+// DeleteRoleBinding removes a role binding, treating a missing one as already deleted
+func (k *kubernetesService) DeleteRoleBinding(ctx context.Context, namespace, name string) error {
+	logger := k.logger.WithFields(logrus.Fields{
+		"namespace": namespace,
+		"name":      name,
+	})
+	logger.Info("Deleting role binding")
+
+	err := k.client.RbacV1().RoleBindings(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("Role binding already deleted")
+			return nil
+		}
+		return fmt.Errorf("failed to delete role binding %s in namespace %s: %w", name, namespace, err)
+	}
+
+	logger.Info("Successfully deleted role binding")
+	return nil
+}
+
+// ServiceAccountExists reports whether a service account exists in a namespace
+func (k *kubernetesService) ServiceAccountExists(ctx context.Context, namespace, name string) (bool, error) {
+	_, err := k.client.CoreV1().ServiceAccounts(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get service account %s in namespace %s: %w", name, namespace, err)
+	}
+	return true, nil
+}
+
+// RoleBindingExists reports whether a role binding exists in a namespace
+func (k *kubernetesService) RoleBindingExists(ctx context.Context, namespace, name string) (bool, error) {
+	_, err := k.client.RbacV1().RoleBindings(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get role binding %s in namespace %s: %w", name, namespace, err)
+	}
+	return true, nil
+}
+
 // ValidateClusterRole validates a ClusterRole and returns security warnings
 func (k *kubernetesService) ValidateClusterRole(ctx context.Context, name string) (*ClusterRoleValidation, error) {
 	validation := &ClusterRoleValidation{
@@ -350,6 +865,7 @@ func (k *kubernetesService) ValidateClusterRole(ctx context.Context, name string
 	validation.Exists = true
 
 	// Analyze rules for security issues
+	k.checkAdminAggregation(clusterRole, validation)
 	for _, rule := range clusterRole.Rules {
 		k.checkClusterAdminPermissions(rule, validation)
 		k.checkNamespaceSpanningPermissions(rule, validation)
@@ -360,6 +876,79 @@ func (k *kubernetesService) ValidateClusterRole(ctx context.Context, name string
 	return validation, nil
 }
 
+// HasNamespaceRole reports whether SubjectAccessReviews allow the user every namespaced permission the
+// ClusterRole grants, within namespace. Non-resource rules are skipped as they cannot be scoped to a namespace.
+func (k *kubernetesService) HasNamespaceRole(
+	ctx context.Context, userInfo *types.UserInfo, namespace, clusterRole string,
+) (bool, error) {
+	role, err := k.client.RbacV1().ClusterRoles().Get(ctx, clusterRole, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to get ClusterRole %s: %w", clusterRole, err)
+	}
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(userInfo.Extra))
+	for key, value := range userInfo.Extra {
+		extra[key] = authorizationv1.ExtraValue{value}
+	}
+
+	// A role without namespaced rules grants nothing that can be checked here
+	reviewed := false
+	for _, rule := range role.Rules {
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				resource, subresource, _ := strings.Cut(resource, "/")
+				for _, verb := range rule.Verbs {
+					review := &authorizationv1.SubjectAccessReview{
+						Spec: authorizationv1.SubjectAccessReviewSpec{
+							User:   userInfo.Username,
+							Groups: userInfo.Groups,
+							Extra:  extra,
+							ResourceAttributes: &authorizationv1.ResourceAttributes{
+								Namespace:   namespace,
+								Verb:        verb,
+								Group:       group,
+								Resource:    resource,
+								Subresource: subresource,
+							},
+						},
+					}
+					result, err := k.client.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+					if err != nil {
+						return false, fmt.Errorf("failed to review access to %s in namespace %s: %w", resource, namespace, err)
+					}
+					if !result.Status.Allowed {
+						return false, nil
+					}
+					reviewed = true
+				}
+			}
+		}
+	}
+	return reviewed, nil
+}
+
+// ResourceKindExists reports whether the API server serves a resource of kind in group, in any version.
+// Groups that fail discovery are skipped, so a kind only they serve is reported as missing.
+func (k *kubernetesService) ResourceKindExists(ctx context.Context, group, kind string) (bool, error) {
+	_, resourceLists, err := k.client.Discovery().ServerGroupsAndResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return false, fmt.Errorf("failed to discover API resources: %w", err)
+	}
+
+	for _, list := range resourceLists {
+		groupVersion, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil || groupVersion.Group != group {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			if resource.Kind == kind {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 // checkClusterAdminPermissions checks for cluster-admin level permissions
 func (k *kubernetesService) checkClusterAdminPermissions(rule rbacv1.PolicyRule, validation *ClusterRoleValidation) {
 	if containsAll(rule.Verbs, []string{"*"}) && containsAll(rule.Resources, []string{"*"}) {
@@ -368,6 +957,46 @@ func (k *kubernetesService) checkClusterAdminPermissions(rule rbacv1.PolicyRule,
 	}
 }
 
+// adminAggregationLabels mark ClusterRoles aggregated into the built-in admin and cluster-admin roles
+var adminAggregationLabels = []string{
+	"rbac.authorization.k8s.io/aggregate-to-admin",
+	"rbac.authorization.k8s.io/aggregate-to-cluster-admin",
+}
+
+// checkAdminAggregation flags a ClusterRole tied to the built-in admin roles through aggregation: one labeled to
+// be aggregated into them, or one whose aggregation rule collects the roles aggregated into them. Its rules
+// alone understate what it grants, since the controller fills in or extends the aggregated rules.
+func (k *kubernetesService) checkAdminAggregation(clusterRole *rbacv1.ClusterRole, validation *ClusterRoleValidation) {
+	for _, label := range adminAggregationLabels {
+		if clusterRole.Labels[label] == "true" {
+			validation.HasClusterAdmin = true
+			validation.Warnings = append(validation.Warnings,
+				fmt.Sprintf("ClusterRole is aggregated into admin roles (%s)", label))
+			return
+		}
+	}
+
+	if clusterRole.AggregationRule == nil {
+		return
+	}
+	for _, selector := range clusterRole.AggregationRule.ClusterRoleSelectors {
+		// An empty selector aggregates every ClusterRole
+		if len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+			validation.HasClusterAdmin = true
+			validation.Warnings = append(validation.Warnings, "ClusterRole aggregates every ClusterRole")
+			return
+		}
+		for _, label := range adminAggregationLabels {
+			if selector.MatchLabels[label] == "true" {
+				validation.HasClusterAdmin = true
+				validation.Warnings = append(validation.Warnings,
+					fmt.Sprintf("ClusterRole aggregates admin roles (%s)", label))
+				return
+			}
+		}
+	}
+}
+
 // checkNamespaceSpanningPermissions checks for namespace-spanning permissions
 func (k *kubernetesService) checkNamespaceSpanningPermissions(rule rbacv1.PolicyRule, validation *ClusterRoleValidation) {
 	if !contains(rule.Verbs, "list") && !contains(rule.Verbs, "watch") {
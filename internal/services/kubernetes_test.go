@@ -5,15 +5,24 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/util/retry"
 )
 
 // Test utility functions
@@ -574,6 +583,66 @@ func TestKubernetesServiceWithFakeClient_Operations(t *testing.T) {
 	})
 }
 
+func TestKubernetesService_ListManagedNamespaces(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{}
+
+	managedLabels := map[string]string{"gitops.io/managed-by": GitOpsRegistrationService}
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a", Labels: managedLabels}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-b", Labels: managedLabels}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:              "tenant-terminating",
+			Labels:            managedLabels,
+			DeletionTimestamp: &metav1.Time{Time: time.Now()},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "other-team",
+			Labels: map[string]string{"gitops.io/managed-by": "someone-else"},
+		}},
+	)
+
+	factory := &TestKubernetesFactory{Client: fakeClient}
+	service, err := NewKubernetesServiceWithFactory(cfg, logger, factory)
+	require.NoError(t, err)
+
+	namespaces, err := service.ListManagedNamespaces(context.Background())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"tenant-a", "tenant-b"}, namespaces)
+}
+
+func TestKubernetesService_CountManagedNamespaces(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{}
+
+	managedLabels := map[string]string{"gitops.io/managed-by": GitOpsRegistrationService}
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a", Labels: managedLabels}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-b", Labels: managedLabels}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-c", Labels: managedLabels}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+	)
+
+	factory := &TestKubernetesFactory{Client: fakeClient}
+	service, err := NewKubernetesServiceWithFactory(cfg, logger, factory)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	managed, err := service.CountManagedNamespaces(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, managed)
+
+	// CountNamespaces still reports every namespace in the cluster
+	total, err := service.CountNamespaces(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 5, total)
+}
+
 func TestKubernetesService_UntestedOperations_WithFakeClient(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.FatalLevel)
@@ -779,10 +848,7 @@ func TestKubernetesService_EdgeCases_Coverage(t *testing.T) {
 
 		// Test with empty strings
 		err = service.CreateNamespace(ctx, "", map[string]string{})
-		// Should handle empty namespace name gracefully
-		if err != nil {
-			assert.Error(t, err)
-		}
+		assert.ErrorIs(t, err, errEmptyNamespaceName)
 
 		// Test with nil maps
 		err = service.CreateNamespaceWithMetadata(ctx, "nil-test", nil, nil)
@@ -799,6 +865,22 @@ func TestKubernetesService_EdgeCases_Coverage(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("Empty namespace names are rejected before reaching the API server", func(t *testing.T) {
+		fakeClient := fake.NewSimpleClientset()
+		service, err := NewKubernetesServiceWithFactory(cfg, logger, &TestKubernetesFactory{Client: fakeClient})
+		require.NoError(t, err)
+
+		ctx := context.Background()
+		err = service.CreateNamespace(ctx, "", nil)
+		require.ErrorIs(t, err, errEmptyNamespaceName)
+		assert.EqualError(t, err, "namespace name must not be empty")
+
+		err = service.CreateNamespaceWithMetadata(ctx, "", map[string]string{"team": "payments"}, nil)
+		require.ErrorIs(t, err, errEmptyNamespaceName)
+
+		assert.Empty(t, fakeClient.Actions())
+	})
+
 	t.Run("Service with nil logger", func(t *testing.T) {
 		factory := NewTestKubernetesFactory()
 		service, err := NewKubernetesServiceWithFactory(cfg, nil, factory)
@@ -1411,3 +1493,709 @@ func TestKubernetesService_RemainingZeroCoverageOperations(t *testing.T) {
 		t.Logf("Total namespace count: %d", count)
 	})
 }
+
+func TestKubernetesService_NamespaceFinalizer(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{}
+	ctx := context.Background()
+
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:       "tenant-a",
+			Finalizers: []string{"example.com/other"},
+		}},
+	)
+
+	factory := &TestKubernetesFactory{Client: fakeClient}
+	service, err := NewKubernetesServiceWithFactory(cfg, logger, factory)
+	require.NoError(t, err)
+
+	t.Run("Add finalizer", func(t *testing.T) {
+		require.NoError(t, service.AddNamespaceFinalizer(ctx, "tenant-a"))
+		// Adding twice is a no-op
+		require.NoError(t, service.AddNamespaceFinalizer(ctx, "tenant-a"))
+
+		ns, err := fakeClient.CoreV1().Namespaces().Get(ctx, "tenant-a", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"example.com/other", NamespaceFinalizer}, ns.Finalizers)
+	})
+
+	t.Run("Remove finalizer keeps other finalizers", func(t *testing.T) {
+		require.NoError(t, service.RemoveNamespaceFinalizer(ctx, "tenant-a"))
+
+		ns, err := fakeClient.CoreV1().Namespaces().Get(ctx, "tenant-a", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"example.com/other"}, ns.Finalizers)
+	})
+
+	t.Run("Add finalizer to missing namespace fails", func(t *testing.T) {
+		err := service.AddNamespaceFinalizer(ctx, "missing")
+		assert.Error(t, err)
+	})
+
+	t.Run("Remove finalizer from missing namespace is a no-op", func(t *testing.T) {
+		assert.NoError(t, service.RemoveNamespaceFinalizer(ctx, "missing"))
+	})
+
+	t.Run("Wildcard finalizer does not count as the protection finalizer", func(t *testing.T) {
+		_, err := fakeClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:       "tenant-wildcard",
+			Finalizers: []string{"*"},
+		}}, metav1.CreateOptions{})
+		require.NoError(t, err)
+
+		require.NoError(t, service.AddNamespaceFinalizer(ctx, "tenant-wildcard"))
+
+		ns, err := fakeClient.CoreV1().Namespaces().Get(ctx, "tenant-wildcard", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"*", NamespaceFinalizer}, ns.Finalizers)
+	})
+}
+
+func TestKubernetesService_ListTerminatingNamespaces(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	deleting := &metav1.Time{Time: time.Now()}
+	namespace := func(name string, managed bool, deletion *metav1.Time, finalizers ...string) *corev1.Namespace {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, DeletionTimestamp: deletion, Finalizers: finalizers}}
+		if managed {
+			ns.Labels = map[string]string{ManagedByLabel: GitOpsRegistrationService}
+		}
+		return ns
+	}
+	fakeClient := fake.NewSimpleClientset(
+		namespace("tenant-active", true, nil, NamespaceFinalizer),
+		namespace("tenant-deleted", true, deleting, NamespaceFinalizer),
+		namespace("tenant-released", true, deleting, "kubernetes"),
+		namespace("unmanaged", false, deleting, NamespaceFinalizer),
+	)
+	service, err := NewKubernetesServiceWithFactory(&config.Config{}, logger, &TestKubernetesFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	names, err := service.ListTerminatingNamespaces(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tenant-deleted"}, names)
+}
+
+func TestKubernetesService_DeleteNamespaceReleasesFinalizer(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:       "tenant-a",
+			Finalizers: []string{NamespaceFinalizer, "example.com/other"},
+		}},
+	)
+
+	factory := &TestKubernetesFactory{Client: fakeClient}
+	service, err := NewKubernetesServiceWithFactory(&config.Config{}, logger, factory)
+	require.NoError(t, err)
+
+	require.NoError(t, service.DeleteNamespace(ctx, "tenant-a"))
+
+	// The finalizer is released before the delete so the namespace does not hang in Terminating
+	var updated *corev1.Namespace
+	for _, action := range fakeClient.Actions() {
+		if update, ok := action.(k8stesting.UpdateAction); ok {
+			updated = update.GetObject().(*corev1.Namespace)
+		}
+	}
+	require.NotNil(t, updated)
+	assert.Equal(t, []string{"example.com/other"}, updated.Finalizers)
+
+	_, err = fakeClient.CoreV1().Namespaces().Get(ctx, "tenant-a", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestKubernetesService_GetNamespaceUID(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{}
+	ctx := context.Background()
+
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a", UID: "uid-123"}},
+	)
+
+	factory := &TestKubernetesFactory{Client: fakeClient}
+	service, err := NewKubernetesServiceWithFactory(cfg, logger, factory)
+	require.NoError(t, err)
+
+	uid, err := service.GetNamespaceUID(ctx, "tenant-a")
+	require.NoError(t, err)
+	assert.Equal(t, "uid-123", uid)
+
+	_, err = service.GetNamespaceUID(ctx, "missing")
+	assert.Error(t, err)
+}
+
+func TestKubernetesService_GetNamespaceMetadata(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{}
+	ctx := context.Background()
+
+	fakeClient := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "tenant-a",
+		Labels:      map[string]string{"team": "a"},
+		Annotations: map[string]string{SyncPruneAnnotation: "false"},
+	}})
+
+	service, err := NewKubernetesServiceWithFactory(cfg, logger, &TestKubernetesFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	info, err := service.GetNamespaceMetadata(ctx, "tenant-a")
+	require.NoError(t, err)
+	assert.Equal(t, "tenant-a", info.Name)
+	assert.Equal(t, "a", info.Labels["team"])
+	assert.Equal(t, "false", info.Annotations[SyncPruneAnnotation])
+
+	_, err = service.GetNamespaceMetadata(ctx, "missing")
+	assert.Error(t, err)
+}
+
+func TestKubernetesService_DeleteServiceAccountAndRoleBinding(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{}
+	ctx := context.Background()
+
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "gitops", Namespace: "tenant-a"}},
+		&rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "gitops-binding", Namespace: "tenant-a"}},
+	)
+
+	factory := &TestKubernetesFactory{Client: fakeClient}
+	service, err := NewKubernetesServiceWithFactory(cfg, logger, factory)
+	require.NoError(t, err)
+
+	exists, err := service.RoleBindingExists(ctx, "tenant-a", "gitops-binding")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = service.ServiceAccountExists(ctx, "tenant-a", "gitops")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	require.NoError(t, service.DeleteRoleBinding(ctx, "tenant-a", "gitops-binding"))
+	exists, err = service.RoleBindingExists(ctx, "tenant-a", "gitops-binding")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, service.DeleteServiceAccount(ctx, "tenant-a", "gitops"))
+	exists, err = service.ServiceAccountExists(ctx, "tenant-a", "gitops")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Deleting resources that are already gone is a no-op
+	assert.NoError(t, service.DeleteRoleBinding(ctx, "tenant-a", "gitops-binding"))
+	assert.NoError(t, service.DeleteServiceAccount(ctx, "tenant-a", "gitops"))
+}
+
+func TestKubernetesService_ValidateClusterRole_AdminAggregation(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	deployRules := []rbacv1.PolicyRule{{
+		APIGroups: []string{"apps"},
+		Resources: []string{"deployments"},
+		Verbs:     []string{"get", "create", "update"},
+	}}
+	tests := []struct {
+		name       string
+		role       *rbacv1.ClusterRole
+		adminEquiv bool
+	}{
+		{
+			name: "Plain role",
+			role: &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "plain"}, Rules: deployRules},
+		},
+		{
+			name: "Labeled for aggregation into admin",
+			role: &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "aggregated",
+					Labels: map[string]string{"rbac.authorization.k8s.io/aggregate-to-admin": "true"},
+				},
+				Rules: deployRules,
+			},
+			adminEquiv: true,
+		},
+		{
+			name: "Aggregation disabled by label value",
+			role: &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "not-aggregated",
+					Labels: map[string]string{"rbac.authorization.k8s.io/aggregate-to-admin": "false"},
+				},
+				Rules: deployRules,
+			},
+		},
+		{
+			name: "Aggregates the admin roles",
+			role: &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "collects-admin"},
+				AggregationRule: &rbacv1.AggregationRule{ClusterRoleSelectors: []metav1.LabelSelector{{
+					MatchLabels: map[string]string{"rbac.authorization.k8s.io/aggregate-to-admin": "true"},
+				}}},
+			},
+			adminEquiv: true,
+		},
+		{
+			name: "Aggregates custom roles",
+			role: &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "collects-custom"},
+				AggregationRule: &rbacv1.AggregationRule{ClusterRoleSelectors: []metav1.LabelSelector{{
+					MatchLabels: map[string]string{"example.com/aggregate-to-gitops": "true"},
+				}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, err := NewKubernetesServiceWithFactory(&config.Config{}, logger,
+				&TestKubernetesFactory{Client: fake.NewSimpleClientset(tt.role)})
+			require.NoError(t, err)
+
+			validation, err := service.ValidateClusterRole(ctx, tt.role.Name)
+			require.NoError(t, err)
+			assert.True(t, validation.Exists)
+			assert.Equal(t, tt.adminEquiv, validation.HasClusterAdmin)
+			if tt.adminEquiv {
+				assert.NotEmpty(t, validation.Warnings)
+			} else {
+				assert.Empty(t, validation.Warnings)
+			}
+		})
+	}
+}
+
+func TestKubernetesService_CreateLimitRange(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	fakeClient := fake.NewSimpleClientset()
+	service, err := NewKubernetesServiceWithFactory(&config.Config{}, logger, &TestKubernetesFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	spec := &types.LimitRangeSpec{
+		Default:        map[string]string{"cpu": "500m", "memory": "512Mi"},
+		DefaultRequest: map[string]string{"cpu": "100m", "memory": "128Mi"},
+	}
+	require.NoError(t, service.CreateLimitRange(ctx, "tenant-a", spec))
+
+	limitRange, err := fakeClient.CoreV1().LimitRanges("tenant-a").Get(ctx, TenantLimitRangeName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, GitOpsRegistrationService, limitRange.Labels[ManagedByLabel])
+	require.Len(t, limitRange.Spec.Limits, 1)
+	limit := limitRange.Spec.Limits[0]
+	assert.Equal(t, corev1.LimitTypeContainer, limit.Type)
+	assert.Equal(t, "500m", limit.Default.Cpu().String())
+	assert.Equal(t, "512Mi", limit.Default.Memory().String())
+	assert.Equal(t, "100m", limit.DefaultRequest.Cpu().String())
+	assert.Equal(t, "128Mi", limit.DefaultRequest.Memory().String())
+
+	t.Run("Existing LimitRange is left alone", func(t *testing.T) {
+		assert.NoError(t, service.CreateLimitRange(ctx, "tenant-a", &types.LimitRangeSpec{
+			Default: map[string]string{"cpu": "2"},
+		}))
+		existing, err := fakeClient.CoreV1().LimitRanges("tenant-a").Get(ctx, TenantLimitRangeName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "500m", existing.Spec.Limits[0].Default.Cpu().String())
+	})
+
+	t.Run("Invalid quantity", func(t *testing.T) {
+		err := service.CreateLimitRange(ctx, "tenant-b", &types.LimitRangeSpec{
+			DefaultRequest: map[string]string{"cpu": "lots"},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid LimitRange defaultRequest")
+	})
+}
+
+func TestKubernetesService_RequireManagedLabelForMutation(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cfg := &config.Config{Security: config.SecurityConfig{
+		RequireManagedLabelForMutation: true,
+		ClaimableNamespaces:            []string{"legacy-*"},
+	}}
+	ctx := context.Background()
+
+	newService := func(t *testing.T) (KubernetesService, *fake.Clientset) {
+		fakeClient := fake.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "team-a",
+				Labels: map[string]string{ManagedByLabel: GitOpsRegistrationService},
+			}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "legacy-billing"}},
+		)
+		service, err := NewKubernetesServiceWithFactory(cfg, logger, &TestKubernetesFactory{Client: fakeClient})
+		require.NoError(t, err)
+		return service, fakeClient
+	}
+
+	t.Run("Managed namespace can be updated and deleted", func(t *testing.T) {
+		service, fakeClient := newService(t)
+
+		require.NoError(t, service.UpdateNamespaceMetadata(ctx, "team-a",
+			map[string]string{"team": "a"}, map[string]string{"owner": "team-a"}))
+		require.NoError(t, service.UpdateNamespaceLabels(ctx, "team-a", map[string]string{"tier": "gold"}))
+		require.NoError(t, service.DeleteNamespace(ctx, "team-a"))
+
+		_, err := fakeClient.CoreV1().Namespaces().Get(ctx, "team-a", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("Unmanaged namespace is refused", func(t *testing.T) {
+		service, fakeClient := newService(t)
+
+		var notManagedErr *NotManagedError
+		err := service.DeleteNamespace(ctx, "kube-system")
+		require.ErrorAs(t, err, &notManagedErr)
+		assert.Equal(t, "kube-system", notManagedErr.Namespace)
+
+		err = service.UpdateNamespaceMetadata(ctx, "kube-system", map[string]string{"team": "a"}, nil)
+		require.ErrorAs(t, err, &notManagedErr)
+		err = service.UpdateNamespaceLabels(ctx, "kube-system", map[string]string{"team": "a"})
+		require.ErrorAs(t, err, &notManagedErr)
+
+		namespace, err := fakeClient.CoreV1().Namespaces().Get(ctx, "kube-system", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, namespace.Labels)
+	})
+
+	t.Run("Update that claims the namespace is refused", func(t *testing.T) {
+		service, fakeClient := newService(t)
+
+		var notManagedErr *NotManagedError
+		err := service.UpdateNamespaceMetadata(ctx, "kube-system",
+			map[string]string{ManagedByLabel: GitOpsRegistrationService}, nil)
+		require.ErrorAs(t, err, &notManagedErr)
+
+		namespace, err := fakeClient.CoreV1().Namespaces().Get(ctx, "kube-system", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, namespace.Labels)
+	})
+
+	t.Run("Claim is limited to allowlisted or managed namespaces", func(t *testing.T) {
+		service, fakeClient := newService(t)
+		claim := map[string]string{ManagedByLabel: GitOpsRegistrationService}
+
+		var notManagedErr *NotManagedError
+		require.ErrorAs(t, service.ClaimNamespace(ctx, "kube-system", claim, nil), &notManagedErr)
+		require.NoError(t, service.ClaimNamespace(ctx, "legacy-billing", claim, nil))
+		require.NoError(t, service.ClaimNamespace(ctx, "team-a", claim, map[string]string{"owner": "team-a"}))
+
+		namespace, err := fakeClient.CoreV1().Namespaces().Get(ctx, "legacy-billing", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, GitOpsRegistrationService, namespace.Labels[ManagedByLabel])
+
+		// Once claimed, the namespace passes the regular guard
+		require.NoError(t, service.UpdateNamespaceLabels(ctx, "legacy-billing", map[string]string{"tier": "gold"}))
+	})
+
+	t.Run("Unclaim reverts only the claimed keys", func(t *testing.T) {
+		service, fakeClient := newService(t)
+		claim := map[string]string{ManagedByLabel: GitOpsRegistrationService, "app.kubernetes.io/managed-by": GitOpsRegistrationService}
+		require.NoError(t, service.ClaimNamespace(ctx, "legacy-billing", claim, map[string]string{OwnerLabel: "alice"}))
+		require.NoError(t, service.UpdateNamespaceLabels(ctx, "legacy-billing", map[string]string{"tier": "gold"}))
+
+		require.NoError(t, service.UnclaimNamespace(ctx, "legacy-billing",
+			NamespaceMetadata{Labels: claim, Annotations: map[string]string{OwnerLabel: "alice"}},
+			NamespaceMetadata{Labels: map[string]string{"app.kubernetes.io/managed-by": "helm"}}))
+
+		namespace, err := fakeClient.CoreV1().Namespaces().Get(ctx, "legacy-billing", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"app.kubernetes.io/managed-by": "helm", "tier": "gold"}, namespace.Labels)
+		assert.Empty(t, namespace.Annotations)
+
+		// Unclaiming does not need the namespace to still be managed, and a missing namespace is a no-op
+		assert.NoError(t, service.UnclaimNamespace(ctx, "legacy-billing", NamespaceMetadata{Labels: claim}, NamespaceMetadata{}))
+		assert.NoError(t, service.UnclaimNamespace(ctx, "team-gone", NamespaceMetadata{Labels: claim}, NamespaceMetadata{}))
+
+		var notManagedErr *NotManagedError
+		assert.ErrorAs(t, service.UnclaimNamespace(ctx, "kube-system", NamespaceMetadata{Labels: claim}, NamespaceMetadata{}), &notManagedErr)
+	})
+
+	t.Run("Deleting a missing namespace is a no-op", func(t *testing.T) {
+		service, _ := newService(t)
+		assert.NoError(t, service.DeleteNamespace(ctx, "team-gone"))
+	})
+
+	t.Run("Guard disabled", func(t *testing.T) {
+		fakeClient := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "scratch"}})
+		service, err := NewKubernetesServiceWithFactory(&config.Config{}, logger, &TestKubernetesFactory{Client: fakeClient})
+		require.NoError(t, err)
+
+		assert.NoError(t, service.DeleteNamespace(ctx, "scratch"))
+	})
+}
+
+func TestKubernetesService_ListEvents(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	newEvent := func(name, namespace, component, reason string, last time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: namespace},
+			InvolvedObject: corev1.ObjectReference{Kind: "Namespace", Name: namespace},
+			Type:           corev1.EventTypeNormal,
+			Reason:         reason,
+			Message:        reason + " in " + namespace,
+			Source:         corev1.EventSource{Component: component},
+			Count:          1,
+			LastTimestamp:  metav1.NewTime(last),
+		}
+	}
+	now := time.Now().Truncate(time.Second)
+
+	fakeClient := fake.NewSimpleClientset(
+		newEvent("registered", "team-a", GitOpsRegistrationService, "Registered", now),
+		newEvent("claimed", "team-a", GitOpsRegistrationService, "NamespaceClaimed", now.Add(-time.Minute)),
+		newEvent("scheduled", "team-a", "default-scheduler", "Scheduled", now),
+		newEvent("other-tenant", "team-b", GitOpsRegistrationService, "Registered", now),
+	)
+	service, err := NewKubernetesServiceWithFactory(&config.Config{}, logger, &TestKubernetesFactory{Client: fakeClient})
+	require.NoError(t, err)
+
+	t.Run("Only this service's events in the namespace, oldest first", func(t *testing.T) {
+		events, err := service.ListEvents(ctx, "team-a")
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, "NamespaceClaimed", events[0].Reason)
+		assert.Equal(t, "Registered", events[1].Reason)
+		assert.Equal(t, "Namespace", events[1].Kind)
+		assert.True(t, now.Equal(events[1].LastTimestamp))
+	})
+
+	t.Run("Namespace without events", func(t *testing.T) {
+		events, err := service.ListEvents(ctx, "team-c")
+		require.NoError(t, err)
+		assert.Empty(t, events)
+		assert.NotNil(t, events)
+	})
+}
+
+// newRoleReviewClient returns a fake clientset holding the given ClusterRoles whose SubjectAccessReviews
+// allow a request only when allow returns true for its resource attributes
+func newRoleReviewClient(allow func(*authorizationv1.ResourceAttributes) bool, roles ...*rbacv1.ClusterRole) *fake.Clientset {
+	objects := make([]runtime.Object, 0, len(roles))
+	for _, role := range roles {
+		objects = append(objects, role)
+	}
+	client := fake.NewSimpleClientset(objects...)
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		review.Status.Allowed = allow(review.Spec.ResourceAttributes)
+		return true, review, nil
+	})
+	return client
+}
+
+func TestKubernetesService_HasNamespaceRole(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+	user := &types.UserInfo{Username: "alice", Groups: []string{"team-a"}}
+
+	editor := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "editor"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"pods", "pods/log"}, Verbs: []string{"get", "list"}},
+			{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"update"}},
+		},
+	}
+	nonResource := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "metrics"},
+		Rules:      []rbacv1.PolicyRule{{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}}},
+	}
+
+	t.Run("Allowed when every permission is granted", func(t *testing.T) {
+		var reviewed []authorizationv1.ResourceAttributes
+		client := newRoleReviewClient(func(attrs *authorizationv1.ResourceAttributes) bool {
+			reviewed = append(reviewed, *attrs)
+			return true
+		}, editor)
+		service := &kubernetesService{client: client, logger: logger}
+
+		allowed, err := service.HasNamespaceRole(ctx, user, "team-a", "editor")
+		require.NoError(t, err)
+		assert.True(t, allowed)
+		require.Len(t, reviewed, 5)
+		assert.Contains(t, reviewed, authorizationv1.ResourceAttributes{
+			Namespace: "team-a", Verb: "get", Resource: "pods", Subresource: "log",
+		})
+		assert.Contains(t, reviewed, authorizationv1.ResourceAttributes{
+			Namespace: "team-a", Verb: "update", Group: "apps", Resource: "deployments",
+		})
+	})
+
+	t.Run("Denied when any permission is missing", func(t *testing.T) {
+		client := newRoleReviewClient(func(attrs *authorizationv1.ResourceAttributes) bool {
+			return attrs.Group != "apps"
+		}, editor)
+		service := &kubernetesService{client: client, logger: logger}
+
+		allowed, err := service.HasNamespaceRole(ctx, user, "team-a", "editor")
+		require.NoError(t, err)
+		assert.False(t, allowed)
+	})
+
+	t.Run("Denied when the role has no namespaced rules", func(t *testing.T) {
+		client := newRoleReviewClient(func(*authorizationv1.ResourceAttributes) bool { return true }, nonResource)
+		service := &kubernetesService{client: client, logger: logger}
+
+		allowed, err := service.HasNamespaceRole(ctx, user, "team-a", "metrics")
+		require.NoError(t, err)
+		assert.False(t, allowed)
+	})
+
+	t.Run("Error when the role does not exist", func(t *testing.T) {
+		client := newRoleReviewClient(func(*authorizationv1.ResourceAttributes) bool { return true })
+		service := &kubernetesService{client: client, logger: logger}
+
+		_, err := service.HasNamespaceRole(ctx, user, "team-a", "missing")
+		assert.Error(t, err)
+	})
+}
+
+// newDiscoveryClient returns a fake clientset whose discovery serves ConfigMaps and Deployments
+func newDiscoveryClient() *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{{Name: "configmaps", Kind: "ConfigMap"}},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment"}},
+		},
+	}
+	return client
+}
+
+func TestKubernetesService_ResourceKindExists(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	service := &kubernetesService{client: newDiscoveryClient(), logger: logger}
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		group  string
+		kind   string
+		exists bool
+	}{
+		{name: "Core kind", group: "", kind: "ConfigMap", exists: true},
+		{name: "Grouped kind", group: "apps", kind: "Deployment", exists: true},
+		{name: "Misspelled kind", group: "apps", kind: "Deployments"},
+		{name: "Kind in another group", group: "", kind: "Deployment"},
+		{name: "Unknown group", group: "example.com", kind: "Widget"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exists, err := service.ResourceKindExists(ctx, tt.group, tt.kind)
+			require.NoError(t, err)
+			assert.Equal(t, tt.exists, exists)
+		})
+	}
+}
+
+func TestKubernetesService_UpdateNamespace_RetriesOnConflict(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	// newService returns a service whose first conflicts namespace updates fail with a conflict. The first
+	// failing update also labels the namespace as a concurrent writer would, so a retry must read it again.
+	newService := func(t *testing.T, conflicts int) (KubernetesService, *fake.Clientset, *int) {
+		fakeClient := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "team-a",
+			Labels: map[string]string{ManagedByLabel: GitOpsRegistrationService},
+		}})
+		updates := 0
+		fakeClient.PrependReactor("update", "namespaces", func(k8stesting.Action) (bool, runtime.Object, error) {
+			updates++
+			if updates > conflicts {
+				return false, nil, nil
+			}
+			if updates == 1 {
+				current, err := fakeClient.Tracker().Get(corev1.SchemeGroupVersion.WithResource("namespaces"), "", "team-a")
+				require.NoError(t, err)
+				namespace := current.(*corev1.Namespace).DeepCopy()
+				namespace.Labels["controller"] = "touched"
+				require.NoError(t, fakeClient.Tracker().Update(corev1.SchemeGroupVersion.WithResource("namespaces"), namespace, ""))
+			}
+			return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "namespaces"}, "team-a",
+				errors.New("the object has been modified"))
+		})
+		service, err := NewKubernetesServiceWithFactory(&config.Config{}, logger, &TestKubernetesFactory{Client: fakeClient})
+		require.NoError(t, err)
+		return service, fakeClient, &updates
+	}
+
+	t.Run("Labels are updated after a conflict", func(t *testing.T) {
+		service, fakeClient, updates := newService(t, 1)
+
+		require.NoError(t, service.UpdateNamespaceLabels(ctx, "team-a", map[string]string{"tier": "gold"}))
+		assert.Equal(t, 2, *updates)
+
+		namespace, err := fakeClient.CoreV1().Namespaces().Get(ctx, "team-a", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "gold", namespace.Labels["tier"])
+		assert.Equal(t, "touched", namespace.Labels["controller"], "the concurrent change should be kept")
+	})
+
+	t.Run("Metadata is updated after a conflict", func(t *testing.T) {
+		service, fakeClient, updates := newService(t, 1)
+
+		require.NoError(t, service.UpdateNamespaceMetadata(ctx, "team-a",
+			map[string]string{"tier": "gold"}, map[string]string{"owner": "team-a"}))
+		assert.Equal(t, 2, *updates)
+
+		namespace, err := fakeClient.CoreV1().Namespaces().Get(ctx, "team-a", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "gold", namespace.Labels["tier"])
+		assert.Equal(t, "touched", namespace.Labels["controller"], "the concurrent change should be kept")
+		assert.Equal(t, "team-a", namespace.Annotations["owner"])
+	})
+
+	t.Run("Retries are bounded", func(t *testing.T) {
+		service, _, updates := newService(t, 100)
+
+		err := service.UpdateNamespaceMetadata(ctx, "team-a", map[string]string{"tier": "gold"}, nil)
+		require.Error(t, err)
+		assert.True(t, apierrors.IsConflict(err))
+		assert.Equal(t, retry.DefaultRetry.Steps, *updates)
+
+		*updates = 0
+		err = service.UpdateNamespaceLabels(ctx, "team-a", map[string]string{"tier": "gold"})
+		require.Error(t, err)
+		assert.True(t, apierrors.IsConflict(err))
+		assert.Equal(t, retry.DefaultRetry.Steps, *updates)
+	})
+
+	t.Run("Other update errors are not retried", func(t *testing.T) {
+		service, fakeClient, _ := newService(t, 0)
+		failures := 0
+		fakeClient.PrependReactor("update", "namespaces", func(k8stesting.Action) (bool, runtime.Object, error) {
+			failures++
+			return true, nil, errors.New("connection refused")
+		})
+
+		require.Error(t, service.UpdateNamespaceLabels(ctx, "team-a", map[string]string{"tier": "gold"}))
+		assert.Equal(t, 1, failures)
+	})
+}
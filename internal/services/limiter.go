@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultUpstreamQueueTimeout bounds the wait for a free slot when upstream.queueTimeout is invalid
+const defaultUpstreamQueueTimeout = 30 * time.Second
+
+// errUpstreamQueueTimeout is wrapped by the UpstreamTimeoutError returned when no slot frees up in time
+var errUpstreamQueueTimeout = errors.New("timed out waiting for a free upstream request slot")
+
+// upstreamLimiter bounds how many Kubernetes and ArgoCD calls are in flight at once, so a burst of
+// registrations cannot overwhelm the API servers. Calls beyond the limit queue for up to queueTimeout.
+// A nil limiter lets every call through.
+type upstreamLimiter struct {
+	slots        chan struct{}
+	queueTimeout time.Duration
+}
+
+// newUpstreamLimiter builds the limiter shared by the Kubernetes and ArgoCD services; it returns nil
+// when upstream.maxConcurrentRequests is not set
+func newUpstreamLimiter(cfg config.UpstreamConfig, logger *logrus.Logger) *upstreamLimiter {
+	if cfg.MaxConcurrentRequests <= 0 {
+		return nil
+	}
+
+	var queueTimeout time.Duration
+	if cfg.QueueTimeout != "" {
+		parsed, err := time.ParseDuration(cfg.QueueTimeout)
+		if err != nil || parsed <= 0 {
+			logger.WithField("queueTimeout", cfg.QueueTimeout).
+				Warnf("Invalid upstream queue timeout, using default %s", defaultUpstreamQueueTimeout)
+			parsed = defaultUpstreamQueueTimeout
+		}
+		queueTimeout = parsed
+	}
+	return &upstreamLimiter{slots: make(chan struct{}, cfg.MaxConcurrentRequests), queueTimeout: queueTimeout}
+}
+
+// acquire waits for a free slot and returns the function that frees it again. Giving up after the queue
+// timeout is reported as an UpstreamTimeoutError so callers handle it like a slow API server.
+func (l *upstreamLimiter) acquire(ctx context.Context, service, operation string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	default:
+	}
+
+	var expired <-chan time.Time
+	if l.queueTimeout > 0 {
+		timer := time.NewTimer(l.queueTimeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	case <-expired:
+		return nil, &UpstreamTimeoutError{Service: service, Operation: operation, Timeout: l.queueTimeout, Err: errUpstreamQueueTimeout}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *upstreamLimiter) release() {
+	<-l.slots
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUpstreamLimiter(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	assert.Nil(t, newUpstreamLimiter(config.UpstreamConfig{QueueTimeout: "30s"}, logger))
+
+	limiter := newUpstreamLimiter(config.UpstreamConfig{MaxConcurrentRequests: 3, QueueTimeout: "5s"}, logger)
+	require.NotNil(t, limiter)
+	assert.Equal(t, 3, cap(limiter.slots))
+	assert.Equal(t, 5*time.Second, limiter.queueTimeout)
+
+	limiter = newUpstreamLimiter(config.UpstreamConfig{MaxConcurrentRequests: 1, QueueTimeout: "soon"}, logger)
+	assert.Equal(t, defaultUpstreamQueueTimeout, limiter.queueTimeout)
+
+	// A disabled limiter is returned unchanged by the wrappers
+	mockK8s := &MockKubernetesService{}
+	assert.Same(t, mockK8s, withKubernetesLimits(mockK8s, 0, nil))
+}
+
+func TestUpstreamLimiter_SaturatedCallsQueue(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	limiter := newUpstreamLimiter(config.UpstreamConfig{MaxConcurrentRequests: 2, QueueTimeout: "5s"}, logger)
+
+	started := make(chan string, 3)
+	unblock := make(chan struct{})
+	mockK8s := &MockKubernetesService{}
+	mockK8s.On("NamespaceExists", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		started <- args.String(1)
+		<-unblock
+	}).Return(true, nil)
+	mockArgoCD := &MockArgoCDService{}
+	mockArgoCD.On("ApplicationExists", mock.Anything, "tenant-c-app").Run(func(args mock.Arguments) {
+		started <- args.String(1)
+	}).Return(true, nil)
+
+	k8sService := withKubernetesLimits(mockK8s, time.Minute, limiter)
+	argoCDService := withArgoCDLimits(mockArgoCD, time.Minute, limiter)
+
+	done := make(chan error, 3)
+	for _, namespace := range []string{"tenant-a", "tenant-b"} {
+		go func(namespace string) {
+			_, err := k8sService.NamespaceExists(ctx, namespace)
+			done <- err
+		}(namespace)
+	}
+	for i := 0; i < 2; i++ {
+		<-started
+	}
+
+	// Both slots are taken, so a call to either service has to wait
+	go func() {
+		_, err := argoCDService.ApplicationExists(ctx, "tenant-c-app")
+		done <- err
+	}()
+	select {
+	case name := <-started:
+		t.Fatalf("%s started while the limiter was saturated", name)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Freeing the slots lets the queued call proceed
+	close(unblock)
+	select {
+	case name := <-started:
+		assert.Equal(t, "tenant-c-app", name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued call did not proceed once slots freed up")
+	}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, <-done)
+	}
+	assert.Empty(t, limiter.slots)
+}
+
+func TestUpstreamLimiter_QueueTimeout(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	limiter := newUpstreamLimiter(config.UpstreamConfig{MaxConcurrentRequests: 1, QueueTimeout: "20ms"}, logger)
+	release, err := limiter.acquire(ctx, "kubernetes", "NamespaceExists")
+	require.NoError(t, err)
+	defer release()
+
+	mockArgoCD := &MockArgoCDService{}
+	service := withArgoCDLimits(mockArgoCD, 0, limiter)
+
+	t.Run("Waiting past the queue timeout returns UpstreamTimeoutError", func(t *testing.T) {
+		err := service.CreateApplication(ctx, &types.Application{Name: "tenant-a-app"})
+
+		var timeoutErr *UpstreamTimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		assert.Equal(t, "argocd", timeoutErr.Service)
+		assert.Equal(t, "CreateApplication", timeoutErr.Operation)
+		assert.ErrorIs(t, err, errUpstreamQueueTimeout)
+		mockArgoCD.AssertNotCalled(t, "CreateApplication", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Cancelled request stops waiting", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := service.ApplicationExists(cancelled, "tenant-a-app")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/konflux-ci/gitops-registration-service/internal/metrics"
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// DriftKind classifies a mismatch between managed namespaces and AppProjects
+type DriftKind string
+
+const (
+	// DriftOrphanedAppProject is a managed AppProject whose tenant namespace no longer exists
+	DriftOrphanedAppProject DriftKind = "orphaned_appproject"
+	// DriftMissingAppProject is a managed namespace without a matching AppProject
+	DriftMissingAppProject DriftKind = "missing_appproject"
+)
+
+// Drift describes a single mismatch found by the reconciler
+type Drift struct {
+	Kind       DriftKind
+	Namespace  string
+	AppProject string
+}
+
+// driftTotal counts newly detected drift by kind; drift still present on later passes is not counted again
+var driftTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "gitops_registration_drift_total",
+	Help: "Number of mismatches detected between managed namespaces and ArgoCD AppProjects",
+}, []string{"kind"})
+
+func init() {
+	prometheus.MustRegister(driftTotal)
+}
+
+// DetectDrift compares managed namespaces against managed AppProjects and returns the mismatches.
+// AppProjects targeting remote clusters still cover a local namespace of the same name, but are never
+// reported as orphaned since their namespaces need not exist locally.
+func DetectDrift(namespaces []string, projects []types.AppProject) []Drift {
+	namespaceSet := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		namespaceSet[namespace] = true
+	}
+
+	var drift []Drift
+	covered := make(map[string]bool, len(projects))
+	for i := range projects {
+		project := &projects[i]
+		namespace := tenantNamespace(project)
+		covered[namespace] = true
+		if !targetsInCluster(project) {
+			continue
+		}
+
+		if !namespaceSet[namespace] {
+			drift = append(drift, Drift{Kind: DriftOrphanedAppProject, Namespace: namespace, AppProject: project.Name})
+		}
+	}
+
+	for _, namespace := range namespaces {
+		if !covered[namespace] {
+			drift = append(drift, Drift{Kind: DriftMissingAppProject, Namespace: namespace})
+		}
+	}
+
+	sort.Slice(drift, func(i, j int) bool {
+		if drift[i].Namespace != drift[j].Namespace {
+			return drift[i].Namespace < drift[j].Namespace
+		}
+		return drift[i].Kind < drift[j].Kind
+	})
+	return drift
+}
+
+// targetsInCluster reports whether an AppProject deploys to the local cluster
+func targetsInCluster(project *types.AppProject) bool {
+	if len(project.Destinations) == 0 {
+		return true
+	}
+	for _, destination := range project.Destinations {
+		if destination.Server == InClusterServer {
+			return true
+		}
+	}
+	return false
+}
+
+// Reconciler periodically detects drift between managed namespaces and AppProjects
+type Reconciler struct {
+	cfg    *config.Config
+	k8s    KubernetesService
+	argocd ArgoCDService
+	logger *logrus.Logger
+
+	mu       sync.Mutex
+	detected map[Drift]bool // drift found by the previous pass
+}
+
+// NewReconciler creates a drift reconciler
+func NewReconciler(cfg *config.Config, k8s KubernetesService, argocd ArgoCDService, logger *logrus.Logger) *Reconciler {
+	return &Reconciler{
+		cfg:    cfg,
+		k8s:    k8s,
+		argocd: argocd,
+		logger: logger,
+	}
+}
+
+// Run reconciles on the configured interval until ctx is cancelled
+func (r *Reconciler) Run(ctx context.Context) {
+	if r.cfg.Reconcile.Interval == "" {
+		r.logger.Info("Drift reconciler disabled")
+		return
+	}
+
+	interval, err := time.ParseDuration(r.cfg.Reconcile.Interval)
+	if err != nil || interval <= 0 {
+		r.logger.WithField("interval", r.cfg.Reconcile.Interval).Info("Drift reconciler disabled")
+		return
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"interval":   interval,
+		"autoRepair": r.cfg.Reconcile.AutoRepair,
+	}).Info("Starting drift reconciler")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.ReconcileOnce(ctx); err != nil {
+				r.logger.WithError(err).Error("Drift reconciliation failed")
+			}
+		}
+	}
+}
+
+// ReconcileOnce releases managed namespaces deleted directly, then lists managed namespaces and AppProjects,
+// records any drift and repairs it when enabled
+func (r *Reconciler) ReconcileOnce(ctx context.Context) ([]Drift, error) {
+	if err := r.releaseTerminatingNamespaces(ctx); err != nil {
+		return nil, err
+	}
+
+	namespaces, err := r.k8s.ListManagedNamespaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list managed namespaces: %w", err)
+	}
+	// Same count as CountManagedNamespaces, without listing namespaces twice
+	metrics.SetCapacity(len(namespaces), r.cfg.Capacity.Limits.MaxNamespaces)
+
+	projects, err := r.argocd.ListManagedAppProjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list managed AppProjects: %w", err)
+	}
+
+	drift := DetectDrift(namespaces, projects)
+
+	r.mu.Lock()
+	detected := make(map[Drift]bool, len(drift))
+	for _, d := range drift {
+		if !r.detected[d] {
+			driftTotal.WithLabelValues(string(d.Kind)).Inc()
+		}
+		detected[d] = true
+	}
+	r.detected = detected
+	r.mu.Unlock()
+
+	for _, d := range drift {
+		logger := r.logger.WithFields(logrus.Fields{
+			"kind":       d.Kind,
+			"namespace":  d.Namespace,
+			"appProject": d.AppProject,
+		})
+		logger.Warn("Detected drift between registrations and cluster state")
+
+		if r.cfg.Reconcile.AutoRepair {
+			if err := r.repair(ctx, d); err != nil {
+				logger.WithError(err).Error("Failed to repair drift")
+			}
+		}
+	}
+
+	return drift, nil
+}
+
+// releaseTerminatingNamespaces finishes deleting managed namespaces that were deleted directly: their tenant
+// Application and AppProject are removed and the protection finalizer holding them in Terminating is
+// released. This runs whether or not auto repair is enabled, since the namespaces are already going away.
+func (r *Reconciler) releaseTerminatingNamespaces(ctx context.Context) error {
+	namespaces, err := r.k8s.ListTerminatingNamespaces(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list terminating namespaces: %w", err)
+	}
+
+	for _, namespace := range namespaces {
+		logger := r.logger.WithField("namespace", namespace)
+		if err := r.releaseNamespace(ctx, namespace); err != nil {
+			logger.WithError(err).Error("Failed to release terminating namespace")
+			continue
+		}
+		logger.Info("Released terminating namespace")
+	}
+	return nil
+}
+
+// releaseNamespace removes a tenant's ArgoCD resources and then the protection finalizer of its namespace
+func (r *Reconciler) releaseNamespace(ctx context.Context, namespace string) error {
+	// Remove the Application first so ArgoCD does not block deleting its project
+	if err := deleteTenantApplication(ctx, r.argocd, r.cfg, namespace); err != nil {
+		return err
+	}
+	if err := r.argocd.DeleteAppProject(ctx, namespace); err != nil {
+		return err
+	}
+	return r.k8s.RemoveNamespaceFinalizer(ctx, namespace)
+}
+
+// repair fixes drift that can be resolved without user input
+func (r *Reconciler) repair(ctx context.Context, d Drift) error {
+	switch d.Kind {
+	case DriftOrphanedAppProject:
+		// Remove the Application first so ArgoCD does not block deleting its project
+		if err := deleteTenantApplication(ctx, r.argocd, r.cfg, d.Namespace); err != nil {
+			return err
+		}
+		if err := r.argocd.DeleteAppProject(ctx, d.AppProject); err != nil {
+			return err
+		}
+		// A namespace deleted directly is held in Terminating by the protection finalizer until now
+		if err := r.k8s.RemoveNamespaceFinalizer(ctx, d.Namespace); err != nil {
+			return err
+		}
+		r.logger.WithField("appProject", d.AppProject).Info("Removed orphaned AppProject")
+		return nil
+	case DriftMissingAppProject:
+		// The repository to recreate the AppProject from is not known here
+		r.logger.WithField("namespace", d.Namespace).Warn("Namespace without AppProject requires re-registration")
+		return nil
+	default:
+		return fmt.Errorf("unknown drift kind %s", d.Kind)
+	}
+}
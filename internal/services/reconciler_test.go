@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/konflux-ci/gitops-registration-service/internal/metrics"
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func inClusterProject(name, namespace string) types.AppProject {
+	return types.AppProject{
+		Name:         name,
+		Destinations: []types.AppProjectDestination{{Server: InClusterServer, Namespace: namespace}},
+	}
+}
+
+func TestDetectDrift(t *testing.T) {
+	tests := []struct {
+		name       string
+		namespaces []string
+		projects   []types.AppProject
+		expected   []Drift
+	}{
+		{
+			name:       "No drift",
+			namespaces: []string{"team-a", "team-b"},
+			projects:   []types.AppProject{inClusterProject("team-a", "team-a"), inClusterProject("team-b", "team-b")},
+			expected:   nil,
+		},
+		{
+			name:       "AppProject without namespace",
+			namespaces: []string{"team-a"},
+			projects:   []types.AppProject{inClusterProject("team-a", "team-a"), inClusterProject("team-b", "team-b")},
+			expected:   []Drift{{Kind: DriftOrphanedAppProject, Namespace: "team-b", AppProject: "team-b"}},
+		},
+		{
+			name:       "Namespace without AppProject",
+			namespaces: []string{"team-a", "team-b"},
+			projects:   []types.AppProject{inClusterProject("team-a", "team-a")},
+			expected:   []Drift{{Kind: DriftMissingAppProject, Namespace: "team-b"}},
+		},
+		{
+			name:       "Tenant label takes precedence over destination",
+			namespaces: []string{"team-a"},
+			projects: []types.AppProject{{
+				Name:         "project-x",
+				Labels:       map[string]string{"gitops.io/tenant": "team-a"},
+				Destinations: []types.AppProjectDestination{{Server: InClusterServer, Namespace: "other"}},
+			}},
+			expected: nil,
+		},
+		{
+			name:       "Remote cluster AppProjects are ignored",
+			namespaces: []string{},
+			projects: []types.AppProject{{
+				Name:         "team-remote",
+				Destinations: []types.AppProjectDestination{{Server: "https://spoke-1.example.com:6443", Namespace: "team-remote"}},
+			}},
+			expected: nil,
+		},
+		{
+			name:       "Remote cluster AppProjects cover a local namespace",
+			namespaces: []string{"team-remote"},
+			projects: []types.AppProject{{
+				Name:         "team-remote",
+				Destinations: []types.AppProjectDestination{{Server: "https://spoke-1.example.com:6443", Namespace: "team-remote"}},
+			}},
+			expected: nil,
+		},
+		{
+			name:       "Results are sorted by namespace",
+			namespaces: []string{"team-c", "team-a"},
+			projects:   []types.AppProject{inClusterProject("team-b", "team-b")},
+			expected: []Drift{
+				{Kind: DriftMissingAppProject, Namespace: "team-a"},
+				{Kind: DriftOrphanedAppProject, Namespace: "team-b", AppProject: "team-b"},
+				{Kind: DriftMissingAppProject, Namespace: "team-c"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectDrift(tt.namespaces, tt.projects))
+		})
+	}
+}
+
+func managedAppProject(name, namespace string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "AppProject",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "argocd",
+				"labels": map[string]interface{}{
+					"gitops.io/managed-by": GitOpsRegistrationService,
+				},
+			},
+			"spec": map[string]interface{}{
+				"destinations": []interface{}{
+					map[string]interface{}{
+						"server":    InClusterServer,
+						"namespace": namespace,
+					},
+				},
+			},
+		},
+	}
+}
+
+func managedNamespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   name,
+		Labels: map[string]string{"gitops.io/managed-by": GitOpsRegistrationService},
+	}}
+}
+
+func TestReconciler_ReconcileOnce_WithFakeClients(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	newReconciler := func(t *testing.T, autoRepair bool) (*Reconciler, *fake.Clientset, *fakedynamic.FakeDynamicClient) {
+		cfg := &config.Config{
+			ArgoCD:    config.ArgoCDConfig{Namespace: "argocd"},
+			Reconcile: config.ReconcileConfig{AutoRepair: autoRepair},
+		}
+
+		// team-b was deleted directly and is held in Terminating by the protection finalizer; team-d's
+		// namespace is already gone
+		terminating := managedNamespace("team-b")
+		terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		terminating.Finalizers = []string{NamespaceFinalizer}
+		k8sClient := fake.NewSimpleClientset(managedNamespace("team-a"), terminating, managedNamespace("team-c"))
+		dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{appProjectGVR: "AppProjectList"},
+			managedAppProject("team-a", "team-a"), managedAppProject("team-b", "team-b"),
+			managedAppProject("team-d", "team-d"))
+
+		svc, err := NewWithFactories(cfg, logger,
+			&TestKubernetesFactory{Client: k8sClient}, &TestArgoCDFactory{Client: dynamicClient})
+		require.NoError(t, err)
+
+		return NewReconciler(cfg, svc.Kubernetes, svc.ArgoCD, logger), k8sClient, dynamicClient
+	}
+
+	t.Run("Drift is reported and counted", func(t *testing.T) {
+		reconciler, _, dynamicClient := newReconciler(t, false)
+		orphanedBefore := testutil.ToFloat64(driftTotal.WithLabelValues(string(DriftOrphanedAppProject)))
+		missingBefore := testutil.ToFloat64(driftTotal.WithLabelValues(string(DriftMissingAppProject)))
+
+		drift, err := reconciler.ReconcileOnce(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, []Drift{
+			{Kind: DriftMissingAppProject, Namespace: "team-c"},
+			{Kind: DriftOrphanedAppProject, Namespace: "team-d", AppProject: "team-d"},
+		}, drift)
+		assert.Equal(t, orphanedBefore+1, testutil.ToFloat64(driftTotal.WithLabelValues(string(DriftOrphanedAppProject))))
+		assert.Equal(t, missingBefore+1, testutil.ToFloat64(driftTotal.WithLabelValues(string(DriftMissingAppProject))))
+
+		// Drift that persists across passes is only counted once
+		_, err = reconciler.ReconcileOnce(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, orphanedBefore+1, testutil.ToFloat64(driftTotal.WithLabelValues(string(DriftOrphanedAppProject))))
+		assert.Equal(t, missingBefore+1, testutil.ToFloat64(driftTotal.WithLabelValues(string(DriftMissingAppProject))))
+
+		// Without auto repair orphaned AppProjects are kept
+		_, err = dynamicClient.Resource(appProjectGVR).Namespace("argocd").Get(ctx, "team-d", metav1.GetOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Terminating namespaces are released without auto repair", func(t *testing.T) {
+		reconciler, k8sClient, dynamicClient := newReconciler(t, false)
+
+		_, err := reconciler.ReconcileOnce(ctx)
+		require.NoError(t, err)
+
+		namespace, err := k8sClient.CoreV1().Namespaces().Get(ctx, "team-b", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, namespace.Finalizers)
+
+		_, err = dynamicClient.Resource(appProjectGVR).Namespace("argocd").Get(ctx, "team-b", metav1.GetOptions{})
+		assert.Error(t, err)
+		_, err = dynamicClient.Resource(appProjectGVR).Namespace("argocd").Get(ctx, "team-a", metav1.GetOptions{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Auto repair removes orphaned AppProjects", func(t *testing.T) {
+		reconciler, _, dynamicClient := newReconciler(t, true)
+
+		_, err := reconciler.ReconcileOnce(ctx)
+		require.NoError(t, err)
+
+		_, err = dynamicClient.Resource(appProjectGVR).Namespace("argocd").Get(ctx, "team-d", metav1.GetOptions{})
+		assert.Error(t, err)
+		_, err = dynamicClient.Resource(appProjectGVR).Namespace("argocd").Get(ctx, "team-a", metav1.GetOptions{})
+		assert.NoError(t, err)
+	})
+}
+
+func TestReconciler_ReconcileOnce_UpdatesCapacityMetrics(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	cfg := &config.Config{
+		ArgoCD:   config.ArgoCDConfig{Namespace: "argocd"},
+		Capacity: config.CapacityConfig{Enabled: true, Limits: config.CapacityLimits{MaxNamespaces: 8}},
+	}
+
+	unmanaged := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}}
+	k8sClient := fake.NewSimpleClientset(managedNamespace("team-a"), managedNamespace("team-b"), unmanaged)
+	dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{appProjectGVR: "AppProjectList"})
+	svc, err := NewWithFactories(cfg, logger,
+		&TestKubernetesFactory{Client: k8sClient}, &TestArgoCDFactory{Client: dynamicClient})
+	require.NoError(t, err)
+	reconciler := NewReconciler(cfg, svc.Kubernetes, svc.ArgoCD, logger)
+
+	_, err = reconciler.ReconcileOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.ManagedNamespaces))
+	assert.Equal(t, 0.25, testutil.ToFloat64(metrics.CapacityUtilization))
+
+	// The next pass picks up newly registered namespaces
+	_, err = k8sClient.CoreV1().Namespaces().Create(ctx, managedNamespace("team-c"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = reconciler.ReconcileOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, testutil.ToFloat64(metrics.ManagedNamespaces))
+	assert.Equal(t, 0.375, testutil.ToFloat64(metrics.CapacityUtilization))
+}
+
+func TestReconciler_Repair_ApplicationSetMode(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	mockK8s := &MockKubernetesService{}
+	mockArgoCD := &MockArgoCDService{}
+	mockArgoCD.On("DeleteApplicationSet", ctx, "team-b-appset").Return(nil)
+	mockArgoCD.On("DeleteAppProject", ctx, "team-b").Return(nil)
+	mockK8s.On("RemoveNamespaceFinalizer", ctx, "team-b").Return(nil)
+
+	cfg := &config.Config{Registration: config.RegistrationConfig{UseApplicationSet: true}}
+	reconciler := NewReconciler(cfg, mockK8s, mockArgoCD, logger)
+
+	err := reconciler.repair(ctx, Drift{Kind: DriftOrphanedAppProject, Namespace: "team-b", AppProject: "team-b"})
+	require.NoError(t, err)
+
+	mockArgoCD.AssertExpectations(t)
+	mockArgoCD.AssertNotCalled(t, "DeleteApplication", mock.Anything, mock.Anything)
+}
+
+func TestReconciler_ReconcileOnce_ListFailure(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+
+	mockK8s := &MockKubernetesService{}
+	mockArgoCD := &MockArgoCDService{}
+	mockK8s.On("ListTerminatingNamespaces", mock.Anything).Return([]string{}, nil)
+	mockK8s.On("ListManagedNamespaces", mock.Anything).Return([]string{"team-a"}, nil)
+	mockArgoCD.On("ListManagedAppProjects", mock.Anything).Return([]types.AppProject(nil), errors.New("forbidden"))
+
+	reconciler := NewReconciler(&config.Config{}, mockK8s, mockArgoCD, logger)
+	_, err := reconciler.ReconcileOnce(ctx)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list managed AppProjects")
+}
+
+func TestReconciler_Run_Disabled(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	for _, interval := range []string{"", "0s", "invalid"} {
+		reconciler := NewReconciler(&config.Config{Reconcile: config.ReconcileConfig{Interval: interval}},
+			&MockKubernetesService{}, &MockArgoCDService{}, logger)
+
+		// Returns immediately without touching the services
+		reconciler.Run(context.Background())
+	}
+}
+
+func TestReconciler_Run_ReconcilesOnInterval(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reconciled := make(chan struct{}, 1)
+	mockK8s := &MockKubernetesService{}
+	mockArgoCD := &MockArgoCDService{}
+	mockK8s.On("ListTerminatingNamespaces", mock.Anything).Return([]string{}, nil)
+	mockK8s.On("ListManagedNamespaces", mock.Anything).Return([]string{}, nil)
+	mockArgoCD.On("ListManagedAppProjects", mock.Anything).Return([]types.AppProject{}, nil).Run(func(mock.Arguments) {
+		select {
+		case reconciled <- struct{}{}:
+		default:
+		}
+	})
+
+	reconciler := NewReconciler(&config.Config{Reconcile: config.ReconcileConfig{Interval: "10ms"}},
+		mockK8s, mockArgoCD, logger)
+
+	done := make(chan struct{})
+	go func() {
+		reconciler.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-reconciled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reconciler did not run")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reconciler did not stop after cancellation")
+	}
+}
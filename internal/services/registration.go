@@ -2,21 +2,45 @@ package services
 
 import (
 	"context"
-	"crypto/sha256"
+	"errors"
 	"fmt"
 	"net/url"
+	"path"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/konflux-ci/gitops-registration-service/internal/config"
 	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/konflux-ci/gitops-registration-service/internal/version"
 	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 )
 
 // Constants for commonly used strings
 const (
-	StatusFailed = "failed"
+	StatusFailed        = "failed"
+	StatusArchived      = "archived"
+	InClusterServer     = "https://kubernetes.default.svc"
+	DefaultBranch       = "main"
+	DefaultManifestPath = "manifests"
+
+	DefaultLegacyServiceAccountName = "gitops"
+	DefaultLegacyRoleBindingName    = "gitops-binding"
+	DefaultLegacyRole               = "gitops-role"
+)
+
+// ClusterScopedDestinationNamespace is the AppProject destination namespace matching cluster-scoped resources
+const ClusterScopedDestinationNamespace = ""
+
+// Annotations on an existing namespace that override the sync policy of its Application
+const (
+	SyncAutomatedAnnotation = "gitops.io/sync-automated"
+	SyncPruneAnnotation     = "gitops.io/sync-prune"
+	SyncSelfHealAnnotation  = "gitops.io/sync-self-heal"
 )
 
 // NamespaceConflictError represents a namespace already exists error
@@ -28,6 +52,146 @@ func (e *NamespaceConflictError) Error() string {
 	return fmt.Sprintf("namespace %s already exists", e.Namespace)
 }
 
+// NamespaceForbiddenError represents a namespace matching one of registration.namespaceDenyPatterns
+type NamespaceForbiddenError struct {
+	Namespace string
+	Pattern   string
+}
+
+func (e *NamespaceForbiddenError) Error() string {
+	return fmt.Sprintf("namespace %s matches deny pattern %q and cannot be registered", e.Namespace, e.Pattern)
+}
+
+// InvalidRepositoryURLError represents a repository URL that is neither scheme://host/path nor
+// scp-style [user@]host:path
+type InvalidRepositoryURLError struct {
+	URL    string
+	Reason string
+}
+
+func (e *InvalidRepositoryURLError) Error() string {
+	if e.URL == "" {
+		return "repository URL is required"
+	}
+	return fmt.Sprintf("invalid repository URL %q: %s", e.URL, e.Reason)
+}
+
+// NamespaceTerminatingError represents a namespace that is still being deleted, e.g. after a recent deregistration
+type NamespaceTerminatingError struct {
+	Namespace  string
+	RetryAfter time.Duration
+}
+
+func (e *NamespaceTerminatingError) Error() string {
+	return fmt.Sprintf("namespace %s is still terminating, retry after %s", e.Namespace, e.RetryAfter)
+}
+
+// DuplicateRegistrationError reports a request repeating a registration that already succeeded: the
+// namespace is managed by this service for the same repository on behalf of the same user and is not
+// archived. It carries the existing registration.
+type DuplicateRegistrationError struct {
+	Registration *types.Registration
+}
+
+func (e *DuplicateRegistrationError) Error() string {
+	return fmt.Sprintf("namespace %s is already registered for this repository", e.Registration.Namespace)
+}
+
+// RepositoryConflictError represents a repository already registered to another tenant
+type RepositoryConflictError struct {
+	RepoURL              string
+	AppProject           string
+	ConflictingNamespace string
+}
+
+func (e *RepositoryConflictError) Error() string {
+	return fmt.Sprintf("repository %s is already registered in AppProject %s (namespace %s)",
+		e.RepoURL, e.AppProject, e.ConflictingNamespace)
+}
+
+// ApplicationConflictError represents an ArgoCD Application name already taken by another resource.
+// Kind names the conflicting resource when it is not an Application, e.g. an AppProject.
+type ApplicationConflictError struct {
+	Application string
+	Kind        string
+}
+
+func (e *ApplicationConflictError) Error() string {
+	kind := e.Kind
+	if kind == "" {
+		kind = "Application"
+	}
+	return fmt.Sprintf("ArgoCD %s %s is already in use", kind, e.Application)
+}
+
+// ClusterRoleNotFoundError represents an impersonation ClusterRole missing from the cluster
+type ClusterRoleNotFoundError struct {
+	ClusterRole string
+}
+
+func (e *ClusterRoleNotFoundError) Error() string {
+	return fmt.Sprintf("impersonation ClusterRole %s does not exist", e.ClusterRole)
+}
+
+// ClusterRoleTooPrivilegedError represents an impersonation ClusterRole granting cluster-admin equivalent
+// permissions, directly or through aggregation
+type ClusterRoleTooPrivilegedError struct {
+	ClusterRole string
+}
+
+func (e *ClusterRoleTooPrivilegedError) Error() string {
+	return fmt.Sprintf("impersonation ClusterRole %s grants cluster-admin equivalent permissions", e.ClusterRole)
+}
+
+// AnnotationNotAllowedError represents a pass-through namespace annotation whose key matches none of
+// tenants.allowedAnnotationPrefixes
+type AnnotationNotAllowedError struct {
+	Key string
+}
+
+func (e *AnnotationNotAllowedError) Error() string {
+	return fmt.Sprintf("namespace annotation %s is not permitted by tenants.allowedAnnotationPrefixes", e.Key)
+}
+
+// ServiceAccountNotFoundError represents a requested pre-created service account missing from the tenant namespace
+type ServiceAccountNotFoundError struct {
+	Namespace      string
+	ServiceAccount string
+}
+
+func (e *ServiceAccountNotFoundError) Error() string {
+	return fmt.Sprintf("service account %s does not exist in namespace %s", e.ServiceAccount, e.Namespace)
+}
+
+// DestinationClusterNotFoundError represents a destination cluster name that is not registered in ArgoCD
+type DestinationClusterNotFoundError struct {
+	Cluster string
+}
+
+func (e *DestinationClusterNotFoundError) Error() string {
+	return fmt.Sprintf("destination cluster %s is not registered in ArgoCD", e.Cluster)
+}
+
+// NotFoundError represents a lookup by ID that matched nothing, e.g. a registration ID no managed namespace carries
+type NotFoundError struct {
+	Resource string
+	ID       string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s %s not found", e.Resource, e.ID)
+}
+
+// UserQuotaExceededError represents a user that already owns the maximum number of registrations
+type UserQuotaExceededError struct {
+	User  string
+	Limit int
+}
+
+func (e *UserQuotaExceededError) Error() string {
+	return fmt.Sprintf("user %s has reached the limit of %d registrations", e.User, e.Limit)
+}
+
 // extractRepositoryDomain extracts a label-safe domain from a repository URL
 func extractRepositoryDomain(repoURL string) string {
 	parsed, err := url.Parse(repoURL)
@@ -57,7 +221,13 @@ type registrationService struct {
 	cfg    *config.Config
 	k8s    KubernetesService
 	argocd ArgoCDService
+	authz  AuthorizationService
 	logger *logrus.Logger
+
+	// namespaceLocks serializes registrations targeting the same namespace
+	namespaceLocks keyedMutex
+	// ownerLocks serializes quota-limited registrations by the same user
+	ownerLocks keyedMutex
 }
 
 // NewRegistrationServiceReal creates a new real RegistrationService implementation
@@ -68,11 +238,14 @@ func NewRegistrationServiceReal(
 		cfg:    cfg,
 		k8s:    k8s,
 		argocd: argocd,
+		authz:  NewAuthorizationService(cfg, k8s, logger),
 		logger: logger,
 	}
 }
 
-func (r *registrationService) CreateRegistration(ctx context.Context, req *types.RegistrationRequest) (*types.Registration, error) {
+func (r *registrationService) CreateRegistration(
+	ctx context.Context, req *types.RegistrationRequest, userInfo *types.UserInfo,
+) (*types.Registration, error) {
 	registrationID := uuid.New().String()
 
 	r.logger.WithFields(logrus.Fields{
@@ -81,45 +254,97 @@ func (r *registrationService) CreateRegistration(ctx context.Context, req *types
 		"registrationID": registrationID,
 	}).Info("Creating registration")
 
+	ctx, span := startSpan(ctx, "CreateRegistration", registrationID, req.Namespace)
+	defer span.End()
+
+	// Concurrent requests for the same namespace must see each other's result in the availability check
+	defer r.namespaceLocks.Lock(req.Namespace)()
+
+	// A repeat of a registration that already succeeded returns it instead of conflicting with itself
+	if existing := r.findDuplicateRegistration(ctx, req, userInfo); existing != nil {
+		return nil, &DuplicateRegistrationError{Registration: existing}
+	}
+
 	// Step 1: Check for repository conflicts
-	if err := r.checkRepositoryConflicts(ctx, req.Repository.URL); err != nil {
+	if err := r.traceStep(ctx, "checkRepositoryConflicts", registrationID, req.Namespace, func(ctx context.Context) error {
+		return r.checkRepositoryConflicts(ctx, req.Repository)
+	}); err != nil {
 		return nil, err
 	}
 
+	// Bindings to a missing ClusterRole grant nothing, so refuse before creating anything
+	if err := r.validateImpersonationClusterRole(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := r.validateRepositoryAccess(ctx, req.Repository); err != nil {
+		return nil, err
+	}
+
+	unlock, err := r.checkUserQuota(ctx, userInfo)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	// Step 2: Validate namespace availability
 	if err := r.validateNamespaceAvailability(ctx, req.Namespace); err != nil {
 		return nil, err
 	}
 
 	// Step 3: Create registration record
-	registration := r.buildRegistrationRecord(registrationID, req)
+	registration := r.buildRegistrationRecord(registrationID, req, userInfo)
 
 	// Step 4: Setup namespace with metadata
-	if err := r.setupNamespace(ctx, req, registrationID); err != nil {
+	if err := abortIfCancelled(ctx, "namespace creation"); err != nil {
+		return nil, err
+	}
+	if err := r.traceStep(ctx, "setupNamespace", registrationID, req.Namespace, func(ctx context.Context) error {
+		return r.setupNamespace(ctx, req, registrationID, userInfo)
+	}); err != nil {
 		registration.Status.Phase = StatusFailed
 		registration.Status.Message = fmt.Sprintf("Failed to create namespace: %v", err)
 		return nil, fmt.Errorf("failed to create namespace: %w", err)
 	}
 
 	// Step 5: Setup service account and role binding
-	serviceAccountName, err := r.setupServiceAccount(ctx, req.Namespace)
+	// A client that disconnected cancels ctx; stop at the next step and remove what was already created
+	if err := abortIfCancelled(ctx, "service account setup"); err != nil {
+		r.cleanupNamespace(ctx, req.Namespace)
+		return nil, err
+	}
+	var serviceAccountName string
+	err = r.traceStep(ctx, "setupServiceAccount", registrationID, req.Namespace, func(ctx context.Context) error {
+		var stepErr error
+		if req.ServiceAccountName != "" {
+			serviceAccountName, stepErr = r.bindExistingServiceAccount(ctx, req.Namespace, req.ServiceAccountName)
+		} else {
+			serviceAccountName, stepErr = r.setupServiceAccount(ctx, req.Namespace)
+		}
+		return stepErr
+	})
 	if err != nil {
 		registration.Status.Phase = StatusFailed
 		registration.Status.Message = fmt.Sprintf("Failed to setup service account: %v", err)
-		if deleteErr := r.k8s.DeleteNamespace(ctx, req.Namespace); deleteErr != nil {
-			r.logger.WithError(deleteErr).Error("Failed to cleanup namespace")
-		}
+		r.cleanupNamespace(ctx, req.Namespace)
 		return nil, fmt.Errorf("failed to setup service account: %w", err)
 	}
 
 	// Step 6: Setup ArgoCD resources
-	appName, projectName, err := r.setupArgoCDResources(ctx, req, serviceAccountName)
+	if err := abortIfCancelled(ctx, "ArgoCD resource setup"); err != nil {
+		r.cleanupNamespace(ctx, req.Namespace)
+		return nil, err
+	}
+	var appName, projectName string
+	err = r.traceStep(ctx, "setupArgoCDResources", registrationID, req.Namespace, func(ctx context.Context) error {
+		var stepErr error
+		appName, projectName, stepErr = r.setupArgoCDResources(ctx, req, serviceAccountName)
+		return stepErr
+	})
 	if err != nil {
 		registration.Status.Phase = StatusFailed
 		registration.Status.Message = fmt.Sprintf("Failed to setup ArgoCD resources: %v", err)
-		if deleteErr := r.k8s.DeleteNamespace(ctx, req.Namespace); deleteErr != nil {
-			r.logger.WithError(deleteErr).Error("Failed to cleanup namespace")
-		}
+		r.cleanupNamespace(ctx, req.Namespace)
 		return nil, fmt.Errorf("failed to setup ArgoCD resources: %w", err)
 	}
 
@@ -138,43 +363,369 @@ func (r *registrationService) CreateRegistration(ctx context.Context, req *types
 	return registration, nil
 }
 
+// checkApplicationConflict returns an ApplicationConflictError if the Application, or the ApplicationSet
+// in ApplicationSet mode, name is already taken
+func (r *registrationService) checkApplicationConflict(ctx context.Context, appName string) error {
+	exists := r.argocd.ApplicationExists
+	if r.cfg.Registration.UseApplicationSet {
+		exists = r.argocd.ApplicationSetExists
+	}
+	taken, err := exists(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed to check Application conflict: %w", err)
+	}
+	if taken {
+		return &ApplicationConflictError{Application: appName}
+	}
+	return nil
+}
+
+// validateImpersonationClusterRole returns a ClusterRoleNotFoundError if impersonation is enabled and its
+// ClusterRole does not exist, or a ClusterRoleTooPrivilegedError if it is cluster-admin equivalent
+func (r *registrationService) validateImpersonationClusterRole(ctx context.Context) error {
+	if !r.cfg.Security.Impersonation.Enabled {
+		return nil
+	}
+
+	clusterRole := r.cfg.Security.Impersonation.ClusterRole
+	validation, err := r.k8s.ValidateClusterRole(ctx, clusterRole)
+	if err != nil {
+		return fmt.Errorf("failed to validate impersonation ClusterRole: %w", err)
+	}
+	if !validation.Exists {
+		return &ClusterRoleNotFoundError{ClusterRole: clusterRole}
+	}
+	if validation.HasClusterAdmin {
+		return &ClusterRoleTooPrivilegedError{ClusterRole: clusterRole}
+	}
+	return nil
+}
+
+// validateRepositoryAccess confirms ArgoCD can reach a repository registered with a credentials secret,
+// so a bad secret fails the registration instead of surfacing later as Application sync errors
+func (r *registrationService) validateRepositoryAccess(ctx context.Context, repo types.Repository) error {
+	if !r.cfg.Registration.ValidateRepoAccess || repo.Credentials.SecretRef == "" {
+		return nil
+	}
+
+	if err := r.argocd.TestRepositoryConnection(ctx, repo.URL, repo.Credentials.SecretRef); err != nil {
+		return fmt.Errorf("failed to validate repository access: %w", err)
+	}
+	return nil
+}
+
+// checkUserQuota enforces registration.maxPerUser for non-admin users. The returned function releases
+// the user's lock, which is held until the registration completes so concurrent requests cannot both
+// take the last slot.
+func (r *registrationService) checkUserQuota(ctx context.Context, userInfo *types.UserInfo) (func(), error) {
+	limit := r.cfg.Registration.MaxPerUser
+	if limit <= 0 || userInfo == nil || r.authz.IsAdminUser(userInfo) {
+		return func() {}, nil
+	}
+
+	owner := GenerateOwnerHash(userInfo.Username)
+	unlock := r.ownerLocks.Lock(owner)
+
+	count, err := r.k8s.CountNamespacesByOwner(ctx, owner)
+	if err != nil {
+		unlock()
+		return nil, fmt.Errorf("failed to count registrations for user %s: %w", userInfo.Username, err)
+	}
+	if count >= limit {
+		unlock()
+		r.logger.WithFields(logrus.Fields{
+			"user":  userInfo.Username,
+			"count": count,
+			"limit": limit,
+		}).Warn("User registration quota exceeded")
+		return nil, &UserQuotaExceededError{User: userInfo.Username, Limit: limit}
+	}
+	return unlock, nil
+}
+
 // checkRepositoryConflicts validates repository availability if impersonation is enabled
-func (r *registrationService) checkRepositoryConflicts(ctx context.Context, repoURL string) error {
+func (r *registrationService) checkRepositoryConflicts(ctx context.Context, repository types.Repository) error {
 	if !r.cfg.Security.Impersonation.Enabled {
 		return nil
 	}
 
-	repoHash := GenerateRepositoryHash(repoURL)
-	conflictExists, err := r.argocd.CheckAppProjectConflict(ctx, repoHash)
+	repoURL := repository.URL
+	repoHash := r.repositoryHash(repository)
+	project, err := r.argocd.FindAppProjectByRepoHash(ctx, repoHash)
 	if err != nil {
 		return fmt.Errorf("failed to check repository conflict: %w", err)
 	}
-	if conflictExists {
-		return fmt.Errorf("repository %s is already registered in another AppProject", repoURL)
+	if project != nil {
+		return &RepositoryConflictError{
+			RepoURL:              repoURL,
+			AppProject:           project.Name,
+			ConflictingNamespace: tenantNamespace(project),
+		}
 	}
 	return nil
 }
 
+// defaultSyncWindows converts argocd.defaultSyncWindows for use in an AppProject
+func (r *registrationService) defaultSyncWindows() []types.SyncWindow {
+	if len(r.cfg.ArgoCD.DefaultSyncWindows) == 0 {
+		return nil
+	}
+
+	windows := make([]types.SyncWindow, 0, len(r.cfg.ArgoCD.DefaultSyncWindows))
+	for _, window := range r.cfg.ArgoCD.DefaultSyncWindows {
+		windows = append(windows, types.SyncWindow{
+			Kind:         window.Kind,
+			Schedule:     window.Schedule,
+			Duration:     window.Duration,
+			Applications: window.Applications,
+		})
+	}
+	return windows
+}
+
+// notificationSubscriptions returns the request's notification subscriptions, or argocd.defaultNotifications
+// converted for use in an Application when the request sets none
+func (r *registrationService) notificationSubscriptions(requested []types.NotificationSubscription) []types.NotificationSubscription {
+	if len(requested) > 0 {
+		return requested
+	}
+	if len(r.cfg.ArgoCD.DefaultNotifications) == 0 {
+		return nil
+	}
+
+	subscriptions := make([]types.NotificationSubscription, 0, len(r.cfg.ArgoCD.DefaultNotifications))
+	for _, subscription := range r.cfg.ArgoCD.DefaultNotifications {
+		subscriptions = append(subscriptions, types.NotificationSubscription{
+			Trigger:    subscription.Trigger,
+			Service:    subscription.Service,
+			Recipients: subscription.Recipients,
+		})
+	}
+	return subscriptions
+}
+
+// repositoryHash returns the value of the repository hash label for a repository. Under the repo+path
+// conflict scope the manifest path is included, so one repository can back several registrations.
+func (r *registrationService) repositoryHash(repository types.Repository) string {
+	length := repositoryHashLength(r.cfg)
+	if r.cfg.Security.ConflictScope == config.ConflictScopeRepoPath {
+		return GenerateRepositoryPathHashWithLength(repository.URL, pathOrDefault(repository.Path), length)
+	}
+	return GenerateRepositoryHashWithLength(repository.URL, length)
+}
+
+// repositoryHashLabel returns the label key holding the repository hash, honoring security.repositoryHashLabelKey
+func repositoryHashLabel(cfg *config.Config) string {
+	if cfg != nil && cfg.Security.RepositoryHashLabelKey != "" {
+		return cfg.Security.RepositoryHashLabelKey
+	}
+	return RepositoryHashLabel
+}
+
+// repositoryHashLength returns the number of hash characters in repository hash labels, honoring
+// security.repositoryHashLength
+func repositoryHashLength(cfg *config.Config) int {
+	if cfg != nil && cfg.Security.RepositoryHashLength > 0 {
+		return cfg.Security.RepositoryHashLength
+	}
+	return DefaultRepositoryHashLength
+}
+
+// pathOrDefault returns the manifest directory of a repository without surrounding slashes,
+// falling back to DefaultManifestPath when blank
+func pathOrDefault(path string) string {
+	if path = strings.Trim(path, "/"); path != "" {
+		return path
+	}
+	return DefaultManifestPath
+}
+
+// branchOrDefault returns the requested branch, falling back to registration.defaultBranch when blank
+func (r *registrationService) branchOrDefault(branch string) string {
+	if branch != "" {
+		return branch
+	}
+	if r.cfg.Registration.DefaultBranch != "" {
+		return r.cfg.Registration.DefaultBranch
+	}
+	return DefaultBranch
+}
+
+// targetRevision returns the revision an Application deploys: the requested revision when set, otherwise the
+// branch or registration.defaultBranch
+func (r *registrationService) targetRevision(repository types.Repository) string {
+	if repository.Revision != "" {
+		return repository.Revision
+	}
+	return r.branchOrDefault(repository.Branch)
+}
+
+// legacyRBACNames returns the service account, role binding and role used when impersonation is disabled,
+// falling back to the defaults for any name not set in security config
+func (r *registrationService) legacyRBACNames() (serviceAccountName, roleBindingName, role string) {
+	serviceAccountName = r.cfg.Security.LegacyServiceAccountName
+	if serviceAccountName == "" {
+		serviceAccountName = DefaultLegacyServiceAccountName
+	}
+	roleBindingName = r.cfg.Security.LegacyRoleBindingName
+	if roleBindingName == "" {
+		roleBindingName = DefaultLegacyRoleBindingName
+	}
+	role = r.cfg.Security.LegacyRole
+	if role == "" {
+		role = DefaultLegacyRole
+	}
+	return serviceAccountName, roleBindingName, role
+}
+
+// tenantNamespace returns the tenant namespace an AppProject was created for
+func tenantNamespace(project *types.AppProject) string {
+	if namespace := project.Labels["gitops.io/tenant"]; namespace != "" {
+		return namespace
+	}
+	if len(project.Destinations) > 0 {
+		return project.Destinations[0].Namespace
+	}
+	return project.Name
+}
+
+// namespaceTerminatingRetryAfter is how long clients are asked to wait before retrying a terminating namespace
+const namespaceTerminatingRetryAfter = 10 * time.Second
+
+// terminatingNamespacePollInterval is how often a terminating namespace is checked while waiting for its removal
+var terminatingNamespacePollInterval = 2 * time.Second
+
+// namespaceActivePollInterval is how often a new namespace is checked while waiting for it to become Active
+var namespaceActivePollInterval = time.Second
+
+// findDuplicateRegistration returns the registration of the requested namespace when this service already
+// manages it for the same repository and the same user, and it is not archived. Another user's registration
+// is never handed out; it and lookup failures are left to the namespace availability check.
+func (r *registrationService) findDuplicateRegistration(ctx context.Context, req *types.RegistrationRequest,
+	userInfo *types.UserInfo) *types.Registration {
+	info, err := r.k8s.GetNamespaceMetadata(ctx, req.Namespace)
+	if err != nil || info.Terminating || isArchived(info) {
+		return nil
+	}
+	if info.Labels[ManagedByLabel] != GitOpsRegistrationService ||
+		info.Labels[repositoryHashLabel(r.cfg)] != r.repositoryHash(req.Repository) {
+		return nil
+	}
+	var caller string
+	if userInfo != nil {
+		caller = userInfo.Username
+	}
+	if info.Annotations[OwnerLabel] != caller {
+		return nil
+	}
+	return r.registrationFromNamespace(info)
+}
+
 // validateNamespaceAvailability checks if the namespace already exists
 func (r *registrationService) validateNamespaceAvailability(ctx context.Context, namespace string) error {
 	exists, err := r.k8s.NamespaceExists(ctx, namespace)
 	if err != nil {
 		return fmt.Errorf("failed to check namespace existence: %w", err)
 	}
-	if exists {
+	if !exists {
+		return nil
+	}
+
+	info, err := r.k8s.GetNamespaceMetadata(ctx, namespace)
+	if err != nil || !info.Terminating {
 		return &NamespaceConflictError{Namespace: namespace}
 	}
-	return nil
+	return r.waitForNamespaceRemoval(ctx, namespace)
+}
+
+// waitForNamespaceRemoval waits up to registration.waitForTerminatingNamespace for a terminating namespace
+// to be removed and returns a NamespaceTerminatingError if it is still there afterwards
+func (r *registrationService) waitForNamespaceRemoval(ctx context.Context, namespace string) error {
+	terminating := &NamespaceTerminatingError{Namespace: namespace, RetryAfter: namespaceTerminatingRetryAfter}
+
+	timeout, err := time.ParseDuration(r.cfg.Registration.WaitForTerminatingNamespace)
+	if err != nil || timeout <= 0 {
+		return terminating
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"namespace": namespace,
+		"timeout":   timeout,
+	}).Info("Waiting for terminating namespace to be removed")
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(terminatingNamespacePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return terminating
+		case <-deadline.C:
+			return terminating
+		case <-ticker.C:
+			exists, err := r.k8s.NamespaceExists(ctx, namespace)
+			if err != nil {
+				return fmt.Errorf("failed to check namespace existence: %w", err)
+			}
+			if !exists {
+				return nil
+			}
+		}
+	}
+}
+
+// waitForNamespaceActive waits up to registration.waitForNamespaceActive for a new namespace to become Active,
+// returning an UpstreamTimeoutError if it is not Active by then. Without the setting it returns at once.
+func (r *registrationService) waitForNamespaceActive(ctx context.Context, namespace string) error {
+	timeout, err := time.ParseDuration(r.cfg.Registration.WaitForNamespaceActive)
+	if err != nil || timeout <= 0 {
+		return nil
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(namespaceActivePollInterval)
+	defer ticker.Stop()
+	for {
+		info, err := r.k8s.GetNamespaceMetadata(ctx, namespace)
+		if err != nil {
+			return fmt.Errorf("failed to check namespace phase: %w", err)
+		}
+		if info.Active {
+			return nil
+		}
+
+		r.logger.WithField("namespace", namespace).Debug("Waiting for namespace to become Active")
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("namespace %s did not become Active: %w", namespace, ctx.Err())
+		case <-deadline.C:
+			return &UpstreamTimeoutError{Service: "kubernetes", Operation: "WaitForNamespaceActive", Timeout: timeout}
+		case <-ticker.C:
+		}
+	}
 }
 
 // buildRegistrationRecord creates the initial registration record
-func (r *registrationService) buildRegistrationRecord(registrationID string, req *types.RegistrationRequest) *types.Registration {
+func (r *registrationService) buildRegistrationRecord(
+	registrationID string, req *types.RegistrationRequest, userInfo *types.UserInfo,
+) *types.Registration {
+	var owner string
+	if userInfo != nil {
+		owner = userInfo.Username
+	}
+
 	return &types.Registration{
 		ID:        registrationID,
 		Namespace: req.Namespace,
+		Owner:     owner,
+		Team:      req.Team,
+		Metadata:  req.Metadata,
 		Repository: types.Repository{
-			URL:    req.Repository.URL,
-			Branch: req.Repository.Branch,
+			URL:      req.Repository.URL,
+			Branch:   r.branchOrDefault(req.Repository.Branch),
+			Revision: req.Repository.Revision,
 		},
 		Status: types.RegistrationStatus{
 			Phase:   "creating",
@@ -190,27 +741,137 @@ func (r *registrationService) buildRegistrationRecord(registrationID string, req
 }
 
 // setupNamespace creates the namespace with proper metadata
-func (r *registrationService) setupNamespace(ctx context.Context, req *types.RegistrationRequest, registrationID string) error {
+func (r *registrationService) setupNamespace(
+	ctx context.Context, req *types.RegistrationRequest, registrationID string, userInfo *types.UserInfo,
+) error {
 	r.logger.WithField("namespace", req.Namespace).Info("Creating namespace")
 
-	repoHash := fmt.Sprintf("%x", sha256.Sum256([]byte(req.Repository.URL)))[:8]
+	repoHash := r.repositoryHash(req.Repository)
 	repoDomain := extractRepositoryDomain(req.Repository.URL)
 
 	namespaceLabels := map[string]string{
 		"gitops.io/registration-id":    registrationID[:8],
-		"gitops.io/repository-hash":    repoHash,
 		"gitops.io/repository-domain":  repoDomain,
 		"gitops.io/managed-by":         "gitops-registration-service",
 		"app.kubernetes.io/managed-by": "gitops-registration-service",
 	}
+	namespaceLabels[repositoryHashLabel(r.cfg)] = repoHash
+	if org := extractRepositoryOrg(req.Repository.URL); org != "" {
+		namespaceLabels[RepositoryOrgLabel] = org
+	}
 
 	namespaceAnnotations := map[string]string{
 		"gitops.io/repository-url":    req.Repository.URL,
-		"gitops.io/repository-branch": req.Repository.Branch,
+		"gitops.io/repository-branch": r.branchOrDefault(req.Repository.Branch),
 		"gitops.io/registration-id":   registrationID,
+		CreatedByVersionAnnotation:    version.Get(),
+	}
+	if req.Repository.Revision != "" {
+		namespaceAnnotations[RepositoryRevisionAnnotation] = req.Repository.Revision
+	}
+	if r.shouldCreateApplication(req) {
+		namespaceAnnotations[ApplicationAnnotation] = TenantApplicationName(r.cfg, req.Namespace)
+	}
+
+	// The owner label counts toward registration.maxPerUser; usernames are not valid label values
+	if userInfo != nil && userInfo.Username != "" {
+		namespaceLabels[OwnerLabel] = GenerateOwnerHash(userInfo.Username)
+		namespaceAnnotations[OwnerLabel] = userInfo.Username
+	}
+	if req.Team != "" {
+		namespaceLabels[TeamLabel] = req.Team
+	}
+	for key, value := range req.Metadata {
+		namespaceAnnotations[MetadataAnnotationPrefix+key] = value
+	}
+
+	// Tenant annotations override the configured defaults and managed keys override both. The defaults were
+	// checked against tenants.allowedAnnotationPrefixes when the config was loaded, the tenant's in
+	// ValidateRegistration.
+	namespaceLabels = withDefaultMetadata(r.cfg.Tenants.DefaultNamespaceLabels, namespaceLabels)
+	annotations := withDefaultMetadata(r.cfg.Tenants.DefaultNamespaceAnnotations, req.Annotations)
+	namespaceAnnotations = withDefaultMetadata(annotations, namespaceAnnotations)
+
+	if err := r.k8s.CreateNamespaceWithMetadata(ctx, req.Namespace, namespaceLabels, namespaceAnnotations); err != nil {
+		return err
+	}
+
+	// Protect the namespace so direct deletion cannot orphan its ArgoCD resources
+	if err := r.k8s.AddNamespaceFinalizer(ctx, req.Namespace); err != nil {
+		r.cleanupNamespace(ctx, req.Namespace)
+		return fmt.Errorf("failed to add namespace finalizer: %w", err)
+	}
+
+	if limitRange := r.cfg.Tenants.DefaultLimitRange; limitRange != nil {
+		spec := &types.LimitRangeSpec{Default: limitRange.Default, DefaultRequest: limitRange.DefaultRequest}
+		if err := r.k8s.CreateLimitRange(ctx, req.Namespace, spec); err != nil {
+			r.cleanupNamespace(ctx, req.Namespace)
+			return fmt.Errorf("failed to create LimitRange: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateAnnotations checks the tenant-supplied namespace annotations in key order: each key must be a valid
+// annotation key outside the reserved gitops.io/ prefix that tenants.allowedAnnotationPrefixes permits
+func (r *registrationService) validateAnnotations(annotations map[string]string) error {
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if errs := k8svalidation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("annotation %q is not a valid annotation key: %s", key, strings.Join(errs, "; "))
+		}
+		if strings.HasPrefix(key, config.ReservedMetadataPrefix) {
+			return fmt.Errorf("annotation %q uses the reserved %s prefix", key, config.ReservedMetadataPrefix)
+		}
+		if !r.cfg.Tenants.AnnotationAllowed(key) {
+			return &AnnotationNotAllowedError{Key: key}
+		}
+	}
+	return nil
+}
+
+// withDefaultMetadata merges configured default labels or annotations under the managed ones.
+// Defaults using the reserved gitops.io/ prefix are ignored and never override managed keys.
+func withDefaultMetadata(defaults, managed map[string]string) map[string]string {
+	merged := make(map[string]string, len(defaults)+len(managed))
+	for key, value := range defaults {
+		if !strings.HasPrefix(key, config.ReservedMetadataPrefix) {
+			merged[key] = value
+		}
+	}
+	for key, value := range managed {
+		merged[key] = value
+	}
+	return merged
+}
+
+// cleanupNamespace deletes a namespace after a failed registration; DeleteNamespace releases the finalizer
+func (r *registrationService) cleanupNamespace(ctx context.Context, namespace string) {
+	if err := r.k8s.DeleteNamespace(cleanupContext(ctx), namespace); err != nil {
+		r.logger.WithError(err).Error("Failed to cleanup namespace")
+	}
+}
+
+// abortIfCancelled returns an error wrapping ctx.Err() once the registration's context is cancelled,
+// naming the step that was about to run
+func abortIfCancelled(ctx context.Context, step string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("registration cancelled before %s: %w", step, err)
 	}
+	return nil
+}
 
-	return r.k8s.CreateNamespaceWithMetadata(ctx, req.Namespace, namespaceLabels, namespaceAnnotations)
+// cleanupContext returns a context for undoing a failed registration: a cancelled ctx would fail every
+// cleanup call, so its values are kept without the cancellation
+func cleanupContext(ctx context.Context) context.Context {
+	if ctx.Err() != nil {
+		return context.WithoutCancel(ctx)
+	}
+	return ctx
 }
 
 // setupServiceAccount creates service account and role binding with or without impersonation
@@ -234,6 +895,13 @@ func (r *registrationService) setupServiceAccountWithImpersonation(ctx context.C
 	roleBindingName := fmt.Sprintf("%s-binding", generatedName)
 	clusterRole := r.cfg.Security.Impersonation.ClusterRole
 	if err := r.k8s.CreateRoleBindingForServiceAccount(ctx, namespace, roleBindingName, clusterRole, generatedName); err != nil {
+		// The generated name is never reused, so a retry would otherwise leave this account behind
+		if cleanupErr := r.k8s.DeleteServiceAccount(ctx, namespace, generatedName); cleanupErr != nil {
+			r.logger.WithError(cleanupErr).WithFields(logrus.Fields{
+				"namespace":      namespace,
+				"serviceAccount": generatedName,
+			}).Error("Failed to cleanup service account")
+		}
 		return "", fmt.Errorf("failed to create role binding: %w", err)
 	}
 
@@ -242,48 +910,246 @@ func (r *registrationService) setupServiceAccountWithImpersonation(ctx context.C
 
 // setupLegacyServiceAccount creates service account with legacy behavior
 func (r *registrationService) setupLegacyServiceAccount(ctx context.Context, namespace string) (string, error) {
-	serviceAccountName := "gitops"
+	serviceAccountName, roleBindingName, role := r.legacyRBACNames()
 	if err := r.k8s.CreateServiceAccount(ctx, namespace, serviceAccountName); err != nil {
 		return "", fmt.Errorf("failed to create service account: %w", err)
 	}
 
-	roleBindingName := "gitops-binding"
-	if err := r.k8s.CreateRoleBinding(ctx, namespace, roleBindingName, "gitops-role", serviceAccountName); err != nil {
+	if err := r.k8s.CreateRoleBinding(ctx, namespace, roleBindingName, role, serviceAccountName); err != nil {
 		return "", fmt.Errorf("failed to create role binding: %w", err)
 	}
 
 	return serviceAccountName, nil
 }
 
-// setupArgoCDResources creates ArgoCD AppProject and Application
-func (r *registrationService) setupArgoCDResources(ctx context.Context, req *types.RegistrationRequest, serviceAccountName string) (appName, projectName string, err error) {
-	projectName = req.Namespace
-	appProject := r.buildAppProject(projectName, req.Namespace, req.Repository.URL, serviceAccountName)
+// bindExistingServiceAccount creates the role binding for a service account the tenant manages, returning a
+// ServiceAccountNotFoundError if it does not exist. The account is not ours, so it is never created or deleted.
+func (r *registrationService) bindExistingServiceAccount(ctx context.Context, namespace, serviceAccountName string) (string, error) {
+	r.logger.WithFields(logrus.Fields{
+		"namespace":      namespace,
+		"serviceAccount": serviceAccountName,
+	}).Info("Binding pre-created service account")
 
-	if err := r.argocd.CreateAppProject(ctx, appProject); err != nil {
-		return "", "", fmt.Errorf("failed to create ArgoCD AppProject: %w", err)
+	exists, err := r.k8s.ServiceAccountExists(ctx, namespace, serviceAccountName)
+	if err != nil {
+		return "", fmt.Errorf("failed to check service account existence: %w", err)
 	}
-
-	appName = fmt.Sprintf("%s-app", req.Namespace)
-	application := &types.Application{
-		Name:    appName,
-		Project: projectName,
-		Source: types.ApplicationSource{
-			RepoURL:        req.Repository.URL,
-			TargetRevision: req.Repository.Branch,
-			Path:           "manifests",
-		},
-		Destination: types.ApplicationDestination{
-			Server:    "https://kubernetes.default.svc",
-			Namespace: req.Namespace,
-		},
+	if !exists {
+		return "", &ServiceAccountNotFoundError{Namespace: namespace, ServiceAccount: serviceAccountName}
 	}
 
-	if err := r.argocd.CreateApplication(ctx, application); err != nil {
-		return "", "", fmt.Errorf("failed to create ArgoCD Application: %w", err)
+	if r.cfg.Security.Impersonation.Enabled {
+		roleBindingName := fmt.Sprintf("%s-binding", serviceAccountName)
+		clusterRole := r.cfg.Security.Impersonation.ClusterRole
+		err = r.k8s.CreateRoleBindingForServiceAccount(ctx, namespace, roleBindingName, clusterRole, serviceAccountName)
+	} else {
+		_, roleBindingName, role := r.legacyRBACNames()
+		err = r.k8s.CreateRoleBinding(ctx, namespace, roleBindingName, role, serviceAccountName)
 	}
-
-	return appName, projectName, nil
+	if err != nil {
+		return "", fmt.Errorf("failed to create role binding: %w", err)
+	}
+	return serviceAccountName, nil
+}
+
+// setupArgoCDResources creates ArgoCD AppProject and Application
+func (r *registrationService) setupArgoCDResources(ctx context.Context, req *types.RegistrationRequest, serviceAccountName string) (appName, projectName string, err error) {
+	// An AppProject created before admission or quota controllers finish the namespace can race with them
+	if err := r.waitForNamespaceActive(ctx, req.Namespace); err != nil {
+		return "", "", err
+	}
+
+	projectName = req.Namespace
+	destinationServer, err := r.resolveDestinationServer(ctx, req.DestinationCluster)
+	if err != nil {
+		return "", "", err
+	}
+	appProject := r.buildAppProject(
+		projectName, req.Namespace, req.Repository, serviceAccountName, destinationServer, req.Team, req.AdditionalSourceRepos...,
+	)
+	if len(req.SyncWindows) > 0 {
+		appProject.SyncWindows = req.SyncWindows
+	}
+	applyRequestResourceRestrictions(appProject, req)
+	ownerReferences := r.namespaceOwnerReferences(ctx, req.Namespace, destinationServer)
+	appProject.OwnerReferences = ownerReferences
+
+	if !r.shouldCreateApplication(req) {
+		if err := r.argocd.CreateAppProject(ctx, appProject); err != nil {
+			return "", "", fmt.Errorf("failed to create ArgoCD AppProject: %w", err)
+		}
+		r.logger.WithField("namespace", req.Namespace).Info("Skipping Application creation, only the AppProject is managed")
+		return "", projectName, nil
+	}
+
+	// Check the Application name before creating anything so a collision leaves no partial resources
+	appName = TenantApplicationName(r.cfg, req.Namespace)
+	if err := r.checkApplicationConflict(ctx, appName); err != nil {
+		return "", "", err
+	}
+
+	if err := r.argocd.CreateAppProject(ctx, appProject); err != nil {
+		return "", "", fmt.Errorf("failed to create ArgoCD AppProject: %w", err)
+	}
+
+	source := r.applicationSource(req.Repository)
+	source.Helm, source.Kustomize = req.Helm, req.Kustomize
+	if err := r.createTenantApplication(ctx, appName, projectName, req.Namespace, source,
+		destinationServer, ownerReferences, r.buildSyncPolicy(), r.notificationSubscriptions(req.Notifications)); err != nil {
+		return "", "", err
+	}
+
+	return appName, projectName, nil
+}
+
+// shouldCreateApplication reports whether a registration gets an Application: registration.manageApplication
+// must be enabled and the request must not opt out with createApplication=false
+func (r *registrationService) shouldCreateApplication(req *types.RegistrationRequest) bool {
+	if !r.cfg.Registration.ManagesApplication() {
+		return false
+	}
+	return req.CreateApplication == nil || *req.CreateApplication
+}
+
+// applicationSource returns the Application source for a repository, without tool-specific overrides
+func (r *registrationService) applicationSource(repository types.Repository) types.ApplicationSource {
+	return types.ApplicationSource{
+		RepoURL:        repository.URL,
+		TargetRevision: r.targetRevision(repository),
+		Path:           pathOrDefault(repository.Path),
+	}
+}
+
+// createTenantApplication creates the tenant's Application, or an ApplicationSet with a git directory
+// generator under source.Path when registration.useApplicationSet is enabled
+func (r *registrationService) createTenantApplication(ctx context.Context, appName, projectName, namespace string,
+	source types.ApplicationSource, destinationServer string, ownerReferences []types.OwnerReference,
+	syncPolicy types.ApplicationSyncPolicy, notifications []types.NotificationSubscription) error {
+	destination := types.ApplicationDestination{
+		Server:    destinationServer,
+		Namespace: namespace,
+	}
+
+	if r.cfg.Registration.UseApplicationSet {
+		appSet := &types.ApplicationSet{
+			Name:            appName,
+			Project:         projectName,
+			RepoURL:         source.RepoURL,
+			TargetRevision:  source.TargetRevision,
+			DirectoryPath:   source.Path + "/*",
+			Destination:     destination,
+			SyncPolicy:      syncPolicy,
+			OwnerReferences: ownerReferences,
+			Helm:            source.Helm,
+			Kustomize:       source.Kustomize,
+			Notifications:   notifications,
+		}
+		if err := r.argocd.CreateApplicationSet(ctx, appSet); err != nil {
+			return fmt.Errorf("failed to create ArgoCD ApplicationSet: %w", err)
+		}
+		return nil
+	}
+
+	application := &types.Application{
+		Name:            appName,
+		Project:         projectName,
+		Source:          source,
+		Destination:     destination,
+		SyncPolicy:      syncPolicy,
+		OwnerReferences: ownerReferences,
+		Notifications:   notifications,
+	}
+	if err := r.argocd.CreateApplication(ctx, application); err != nil {
+		return fmt.Errorf("failed to create ArgoCD Application: %w", err)
+	}
+	return nil
+}
+
+// namespaceOwnerReferences returns an owner reference to the tenant namespace so ArgoCD resources are
+// garbage collected with it. Remote destinations and failed lookups yield no references.
+func (r *registrationService) namespaceOwnerReferences(ctx context.Context, namespace, destinationServer string) []types.OwnerReference {
+	if destinationServer != InClusterServer {
+		return nil
+	}
+
+	uid, err := r.k8s.GetNamespaceUID(ctx, namespace)
+	if err != nil {
+		r.logger.WithError(err).WithField("namespace", namespace).
+			Warn("Failed to look up namespace UID, ArgoCD resources will not be garbage collected with it")
+		return nil
+	}
+
+	return []types.OwnerReference{
+		{
+			APIVersion: "v1",
+			Kind:       "Namespace",
+			Name:       namespace,
+			UID:        uid,
+		},
+	}
+}
+
+// buildSyncPolicy returns the default sync policy for generated Applications: automated with prune and
+// self-heal, plus the configured retry policy
+func (r *registrationService) buildSyncPolicy() types.ApplicationSyncPolicy {
+	policy := types.ApplicationSyncPolicy{
+		Automated: &types.ApplicationSyncPolicyAutomated{Prune: true, SelfHeal: true},
+	}
+
+	retry := r.cfg.ArgoCD.SyncRetry
+	if retry.Limit == 0 {
+		return policy
+	}
+
+	policy.Retry = &types.ApplicationSyncPolicyRetry{
+		Limit: retry.Limit,
+		Backoff: &types.ApplicationSyncPolicyRetryBackoff{
+			Duration:    retry.BackoffDuration,
+			Factor:      retry.BackoffFactor,
+			MaxDuration: retry.BackoffMaxDuration,
+		},
+	}
+	return policy
+}
+
+// existingNamespaceSyncPolicy lets sync annotations already present on an existing namespace override the
+// default sync policy. Unreadable metadata and unparsable values leave the defaults in place.
+func (r *registrationService) existingNamespaceSyncPolicy(ctx context.Context, namespace string) types.ApplicationSyncPolicy {
+	policy := r.buildSyncPolicy()
+
+	info, err := r.k8s.GetNamespaceMetadata(ctx, namespace)
+	if err != nil {
+		r.logger.WithError(err).WithField("namespace", namespace).Warn("Failed to read namespace sync annotations, using defaults")
+		return policy
+	}
+
+	if !r.syncAnnotation(info.Annotations, SyncAutomatedAnnotation, true, namespace) {
+		policy.Automated = nil
+		return policy
+	}
+	policy.Automated = &types.ApplicationSyncPolicyAutomated{
+		Prune:    r.syncAnnotation(info.Annotations, SyncPruneAnnotation, policy.Automated.Prune, namespace),
+		SelfHeal: r.syncAnnotation(info.Annotations, SyncSelfHealAnnotation, policy.Automated.SelfHeal, namespace),
+	}
+	return policy
+}
+
+// syncAnnotation parses a boolean sync annotation, returning fallback when it is absent or invalid
+func (r *registrationService) syncAnnotation(annotations map[string]string, key string, fallback bool, namespace string) bool {
+	value, ok := annotations[key]
+	if !ok {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		r.logger.WithFields(logrus.Fields{
+			"namespace":  namespace,
+			"annotation": key,
+			"value":      value,
+		}).Warn("Ignoring invalid sync annotation on namespace")
+		return fallback
+	}
+	return parsed
 }
 
 // finalizeRegistration updates the registration record with success status
@@ -295,34 +1161,294 @@ func (r *registrationService) finalizeRegistration(registration *types.Registrat
 	registration.Status.LastSyncTime = time.Now()
 	registration.Status.NamespaceCreated = true
 	registration.Status.AppProjectCreated = true
-	registration.Status.ApplicationCreated = true
+	registration.Status.ApplicationCreated = appName != ""
 	registration.UpdatedAt = time.Now()
 }
 
+// GetRegistration rebuilds a registration from the metadata of the managed namespace carrying its ID
 func (r *registrationService) GetRegistration(ctx context.Context, id string) (*types.Registration, error) {
-	// For now, return a simple stub - in a real implementation this would query a database
-	return &types.Registration{
-		ID: id,
-		Status: types.RegistrationStatus{
-			Phase:   "active",
-			Message: "Registration found",
-		},
-	}, nil
+	namespace, err := r.findRegistrationNamespace(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.registrationFromNamespace(namespace), nil
+}
+
+// findRegistrationNamespace returns the managed namespace recording registration id
+func (r *registrationService) findRegistrationNamespace(ctx context.Context, id string) (*NamespaceInfo, error) {
+	namespaces, err := r.k8s.ListManagedNamespaceInfo(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range namespaces {
+		if namespaces[i].Annotations["gitops.io/registration-id"] == id {
+			return &namespaces[i], nil
+		}
+	}
+	return nil, &NotFoundError{Resource: "registration", ID: id}
 }
 
+// ListRegistrations rebuilds registrations from managed namespaces. The "namespace" filter matches the
+// namespace name, the "owner" filter the username of the user who created the registration, the "team"
+// filter the team the registration was made for and the "org" filter the organization that owns the
+// registration's repository, ignoring case. Each "meta.<key>" filter matches the value of the
+// registration's <key> metadata tag; all filters must match. Archived registrations are left out unless the
+// "includeArchived" filter is "true".
 func (r *registrationService) ListRegistrations(
 	ctx context.Context, filters map[string]string,
 ) ([]*types.Registration, error) {
-	// For now, return a simple stub - in a real implementation this would query a database
-	return []*types.Registration{}, nil
+	metadata := map[string]string{}
+	for filter, value := range filters {
+		key, ok := strings.CutPrefix(filter, MetadataFilterPrefix)
+		if !ok {
+			continue
+		}
+		if err := ValidateMetadataKey(key); err != nil {
+			return nil, err
+		}
+		metadata[key] = value
+	}
+
+	var requirements []string
+	if owner := filters["owner"]; owner != "" {
+		requirements = append(requirements, OwnerLabel+"="+GenerateOwnerHash(owner))
+	}
+	if team := filters["team"]; team != "" {
+		if err := ValidateTeam(team); err != nil {
+			return nil, err
+		}
+		requirements = append(requirements, TeamLabel+"="+team)
+	}
+	if org := filters["org"]; org != "" {
+		if err := ValidateRepositoryOrg(org); err != nil {
+			return nil, err
+		}
+		requirements = append(requirements, RepositoryOrgLabel+"="+strings.ToLower(org))
+	}
+
+	namespaces, err := r.k8s.ListManagedNamespaceInfo(ctx, strings.Join(requirements, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	registrations := make([]*types.Registration, 0, len(namespaces))
+	for i := range namespaces {
+		if name := filters["namespace"]; name != "" && namespaces[i].Name != name {
+			continue
+		}
+		if !metadataMatches(namespaces[i].Annotations, metadata) {
+			continue
+		}
+		if isArchived(&namespaces[i]) && filters["includeArchived"] != "true" {
+			continue
+		}
+		registrations = append(registrations, r.registrationFromNamespace(&namespaces[i]))
+	}
+	return registrations, nil
+}
+
+// metadataMatches reports whether annotations tag a registration with every key and value in metadata.
+// Annotations cannot be selected on by the API server, so metadata filters are applied to the listed namespaces.
+func metadataMatches(annotations, metadata map[string]string) bool {
+	for key, value := range metadata {
+		tagged, found := annotations[MetadataAnnotationPrefix+key]
+		if !found || tagged != value {
+			return false
+		}
+	}
+	return true
+}
+
+// metadataFromAnnotations returns the metadata tags recorded in a namespace's annotations, or nil if it has none
+func metadataFromAnnotations(annotations map[string]string) map[string]string {
+	var metadata map[string]string
+	for key, value := range annotations {
+		if tag, ok := strings.CutPrefix(key, MetadataAnnotationPrefix); ok {
+			if metadata == nil {
+				metadata = map[string]string{}
+			}
+			metadata[tag] = value
+		}
+	}
+	return metadata
+}
+
+// isArchived reports whether a registration's namespace was marked archived
+func isArchived(namespace *NamespaceInfo) bool {
+	_, archived := namespace.Annotations[ArchivedAtAnnotation]
+	return archived
 }
 
+// registrationFromNamespace builds a registration from the metadata written when it was created
+func (r *registrationService) registrationFromNamespace(namespace *NamespaceInfo) *types.Registration {
+	phase := "active"
+	if isArchived(namespace) {
+		phase = StatusArchived
+	}
+	// Registrations without an Application (createApplication=false or registration.manageApplication
+	// disabled) carry no application annotation
+	appName := namespace.Annotations[ApplicationAnnotation]
+	return &types.Registration{
+		ID:        namespace.Annotations["gitops.io/registration-id"],
+		Namespace: namespace.Name,
+		Owner:     namespace.Annotations[OwnerLabel],
+		Team:      namespace.Labels[TeamLabel],
+		Metadata:  metadataFromAnnotations(namespace.Annotations),
+		Repository: types.Repository{
+			URL:      namespace.Annotations["gitops.io/repository-url"],
+			Branch:   namespace.Annotations["gitops.io/repository-branch"],
+			Revision: namespace.Annotations[RepositoryRevisionAnnotation],
+		},
+		Status: types.RegistrationStatus{
+			Phase:              phase,
+			ArgoCDApplication:  appName,
+			ApplicationCreated: appName != "",
+		},
+		CreatedAt: namespace.CreationTimestamp,
+		UpdatedAt: namespace.CreationTimestamp,
+		Labels:    namespace.Labels,
+	}
+}
+
+// DeleteRegistration purges a registration: it removes the tenant's Application or ApplicationSet and then
+// its AppProject. With registration.deleteNamespaceOnDeregister the namespace is deleted as well; otherwise
+// it is released by stripping the labels and annotations registering added and removing the protection
+// finalizer, keeping the namespace and everything deployed into it.
 func (r *registrationService) DeleteRegistration(ctx context.Context, id string) error {
-	// For now, return nil - in a real implementation this would clean up resources
-	r.logger.WithField("registrationID", id).Info("Registration deletion (stub)")
+	info, err := r.findRegistrationNamespace(ctx, id)
+	if err != nil {
+		return err
+	}
+	namespace := info.Name
+
+	defer r.namespaceLocks.Lock(namespace)()
+
+	// Remove the Application first so ArgoCD does not block deleting its project
+	if err := deleteTenantApplication(ctx, r.argocd, r.cfg, namespace); err != nil {
+		return fmt.Errorf("failed to delete Application of namespace %s: %w", namespace, err)
+	}
+	if err := r.argocd.DeleteAppProject(ctx, namespace); err != nil {
+		return fmt.Errorf("failed to delete AppProject %s: %w", namespace, err)
+	}
+
+	if r.cfg.Registration.DeleteNamespaceOnDeregister {
+		// DeleteNamespace releases the protection finalizer itself
+		if err := r.k8s.DeleteNamespace(ctx, namespace); err != nil {
+			return err
+		}
+		r.logger.WithFields(logrus.Fields{
+			"registrationID": id,
+			"namespace":      namespace,
+		}).Info("Purged registration and deleted its namespace")
+		return nil
+	}
+
+	if err := r.k8s.UnclaimNamespace(ctx, namespace, managedNamespaceMetadata(r.cfg, info), NamespaceMetadata{}); err != nil {
+		return fmt.Errorf("failed to release namespace %s: %w", namespace, err)
+	}
+	if err := r.k8s.RemoveNamespaceFinalizer(ctx, namespace); err != nil {
+		return err
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"registrationID": id,
+		"namespace":      namespace,
+	}).Info("Purged registration")
 	return nil
 }
 
+// managedNamespaceMetadata returns the labels and annotations of namespace that registering it added: every
+// key under the reserved gitops.io/ prefix, the repository hash label and the app.kubernetes.io/managed-by
+// label naming this service
+func managedNamespaceMetadata(cfg *config.Config, namespace *NamespaceInfo) NamespaceMetadata {
+	managed := NamespaceMetadata{Labels: map[string]string{}, Annotations: map[string]string{}}
+	for key, value := range namespace.Labels {
+		if strings.HasPrefix(key, config.ReservedMetadataPrefix) || key == repositoryHashLabel(cfg) ||
+			(key == "app.kubernetes.io/managed-by" && value == GitOpsRegistrationService) {
+			managed.Labels[key] = value
+		}
+	}
+	for key, value := range namespace.Annotations {
+		if strings.HasPrefix(key, config.ReservedMetadataPrefix) {
+			managed.Annotations[key] = value
+		}
+	}
+	return managed
+}
+
+// ArchiveRegistration is the first phase of a delete: it suspends the registration's Application and marks
+// its namespace archived, leaving every resource in place until the registration is purged. Archiving an
+// archived registration returns it unchanged.
+func (r *registrationService) ArchiveRegistration(ctx context.Context, id string) (*types.Registration, error) {
+	registration, err := r.GetRegistration(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if registration.Status.Phase == StatusArchived {
+		return registration, nil
+	}
+
+	// Registrations made without an Application have nothing to suspend
+	var appNotFound *ApplicationNotFoundError
+	if err := suspendTenantApplication(ctx, r.argocd, r.cfg, registration.Namespace); err != nil && !errors.As(err, &appNotFound) {
+		return nil, fmt.Errorf("failed to suspend %s: %w", TenantApplicationName(r.cfg, registration.Namespace), err)
+	}
+
+	archivedAt := time.Now().UTC().Format(time.RFC3339)
+	if err := r.k8s.UpdateNamespaceMetadata(ctx, registration.Namespace, nil,
+		map[string]string{ArchivedAtAnnotation: archivedAt}); err != nil {
+		return nil, fmt.Errorf("failed to mark namespace %s archived: %w", registration.Namespace, err)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"registrationID": id,
+		"namespace":      registration.Namespace,
+	}).Info("Archived registration")
+
+	registration.Status.Phase = StatusArchived
+	registration.Status.Message = "Archived at " + archivedAt + "; delete with purge=true to remove it"
+	return registration, nil
+}
+
+// UnarchiveRegistration reverses ArchiveRegistration: it restores automated sync on the registration's
+// Application or ApplicationSet and clears the archived mark from its namespace. Unarchiving an active
+// registration returns it unchanged.
+func (r *registrationService) UnarchiveRegistration(ctx context.Context, id string) (*types.Registration, error) {
+	registration, err := r.GetRegistration(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if registration.Status.Phase != StatusArchived {
+		return registration, nil
+	}
+
+	defer r.namespaceLocks.Lock(registration.Namespace)()
+
+	// Namespaces that opted out of automated sync stay suspended
+	if automated := r.existingNamespaceSyncPolicy(ctx, registration.Namespace).Automated; automated != nil {
+		var appNotFound *ApplicationNotFoundError
+		err := resumeTenantApplication(ctx, r.argocd, r.cfg, registration.Namespace, automated)
+		if err != nil && !errors.As(err, &appNotFound) {
+			return nil, fmt.Errorf("failed to resume %s: %w", TenantApplicationName(r.cfg, registration.Namespace), err)
+		}
+	}
+
+	archived := NamespaceMetadata{Annotations: map[string]string{ArchivedAtAnnotation: ""}}
+	if err := r.k8s.UnclaimNamespace(ctx, registration.Namespace, archived, NamespaceMetadata{}); err != nil {
+		return nil, fmt.Errorf("failed to clear archived mark on namespace %s: %w", registration.Namespace, err)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"registrationID": id,
+		"namespace":      registration.Namespace,
+	}).Info("Unarchived registration")
+
+	registration.Status.Phase = "active"
+	registration.Status.Message = ""
+	return registration, nil
+}
+
 func (r *registrationService) RegisterExistingNamespace(ctx context.Context, req *types.ExistingNamespaceRequest, userInfo *types.UserInfo) (*types.Registration, error) {
 	registrationID := uuid.New().String()
 
@@ -333,37 +1459,48 @@ func (r *registrationService) RegisterExistingNamespace(ctx context.Context, req
 		"user":           userInfo.Username,
 	}).Info("Converting existing namespace to GitOps management")
 
+	defer r.namespaceLocks.Lock(req.ExistingNamespace)()
+
 	// Step 1: Validate namespace exists
 	if err := r.validateExistingNamespace(ctx, req.ExistingNamespace); err != nil {
 		return nil, err
 	}
 
+	if err := r.validateRepositoryAccess(ctx, req.Repository); err != nil {
+		return nil, err
+	}
+
 	// Step 2: Create registration record
 	registration := r.buildExistingNamespaceRegistration(registrationID, req)
 
-	// Step 3: Setup service account in existing namespace
-	if err := r.setupServiceAccountInExistingNamespace(ctx, req.ExistingNamespace); err != nil {
+	// Step 3: Claim the namespace by adding GitOps metadata
+	claimed, original, err := r.updateExistingNamespaceMetadata(ctx, req, registrationID, userInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 4: Setup service account in existing namespace
+	created, err := r.setupServiceAccountInExistingNamespace(ctx, req.ExistingNamespace)
+	if err != nil {
 		registration.Status.Phase = StatusFailed
 		registration.Status.Message = fmt.Sprintf("Failed to setup service account: %v", err)
+		r.unclaimExistingNamespace(ctx, req.ExistingNamespace, claimed, original)
 		return nil, fmt.Errorf("failed to setup service account: %w", err)
 	}
 
-	// Step 4: Update namespace metadata
-	r.updateExistingNamespaceMetadata(ctx, req, registrationID)
-
 	// Step 5: Setup ArgoCD resources
-	appName, projectName, err := r.setupArgoCDResourcesForExistingNamespace(ctx, req)
+	appName, projectName, adopted, err := r.setupArgoCDResourcesForExistingNamespace(ctx, req)
 	if err != nil {
 		registration.Status.Phase = StatusFailed
 		registration.Status.Message = fmt.Sprintf("Failed to setup ArgoCD resources: %v", err)
-		if deleteErr := r.k8s.DeleteNamespace(ctx, req.ExistingNamespace); deleteErr != nil {
-			r.logger.WithError(deleteErr).Error("Failed to cleanup namespace")
-		}
+		// The namespace belongs to the user, so only remove what this registration added to it
+		r.cleanupExistingNamespaceResources(ctx, req.ExistingNamespace, projectName, created)
+		r.unclaimExistingNamespace(ctx, req.ExistingNamespace, claimed, original)
 		return nil, fmt.Errorf("failed to setup ArgoCD resources: %w", err)
 	}
 
 	// Step 6: Finalize registration for existing namespace
-	r.finalizeExistingNamespaceRegistration(registration, appName, projectName, userInfo)
+	r.finalizeExistingNamespaceRegistration(registration, appName, projectName, adopted)
 
 	r.logger.WithFields(logrus.Fields{
 		"namespace":         req.ExistingNamespace,
@@ -394,8 +1531,9 @@ func (r *registrationService) buildExistingNamespaceRegistration(registrationID
 		ID:        registrationID,
 		Namespace: req.ExistingNamespace,
 		Repository: types.Repository{
-			URL:    req.Repository.URL,
-			Branch: req.Repository.Branch,
+			URL:      req.Repository.URL,
+			Branch:   r.branchOrDefault(req.Repository.Branch),
+			Revision: req.Repository.Revision,
 		},
 		Status: types.RegistrationStatus{
 			Phase:   "creating",
@@ -410,90 +1548,213 @@ func (r *registrationService) buildExistingNamespaceRegistration(registrationID
 	}
 }
 
-// setupServiceAccountInExistingNamespace creates service account and role binding
-func (r *registrationService) setupServiceAccountInExistingNamespace(ctx context.Context, namespace string) error {
+// existingNamespaceRBAC records which RBAC resources a conversion created, as opposed to found in place
+type existingNamespaceRBAC struct {
+	serviceAccount bool
+	roleBinding    bool
+}
+
+// setupServiceAccountInExistingNamespace creates service account and role binding. Resources that already
+// exist are reused and left out of the result so a failed conversion never deletes them.
+func (r *registrationService) setupServiceAccountInExistingNamespace(ctx context.Context, namespace string) (existingNamespaceRBAC, error) {
 	r.logger.WithField("namespace", namespace).Info("Creating service account in existing namespace")
 
-	serviceAccountName := "gitops"
-	if err := r.k8s.CreateServiceAccount(ctx, namespace, serviceAccountName); err != nil {
-		return fmt.Errorf("failed to create service account: %w", err)
+	var created existingNamespaceRBAC
+	serviceAccountName, roleBindingName, role := r.legacyRBACNames()
+
+	serviceAccountExists, err := r.k8s.ServiceAccountExists(ctx, namespace, serviceAccountName)
+	if err != nil {
+		return created, fmt.Errorf("failed to check service account: %w", err)
+	}
+	if !serviceAccountExists {
+		if err := r.k8s.CreateServiceAccount(ctx, namespace, serviceAccountName); err != nil {
+			return created, fmt.Errorf("failed to create service account: %w", err)
+		}
+		created.serviceAccount = true
 	}
 
-	roleBindingName := "gitops-binding"
-	if err := r.k8s.CreateRoleBinding(ctx, namespace, roleBindingName, "gitops-role", serviceAccountName); err != nil {
-		return fmt.Errorf("failed to create role binding: %w", err)
+	// On failure, do not leave behind a service account this conversion created
+	roleBindingExists, err := r.k8s.RoleBindingExists(ctx, namespace, roleBindingName)
+	if err != nil {
+		r.cleanupExistingNamespaceResources(ctx, namespace, "", created)
+		return existingNamespaceRBAC{}, fmt.Errorf("failed to check role binding: %w", err)
+	}
+	if !roleBindingExists {
+		if err := r.k8s.CreateRoleBinding(ctx, namespace, roleBindingName, role, serviceAccountName); err != nil {
+			r.cleanupExistingNamespaceResources(ctx, namespace, "", created)
+			return existingNamespaceRBAC{}, fmt.Errorf("failed to create role binding: %w", err)
+		}
+		created.roleBinding = true
 	}
 
-	return nil
+	return created, nil
 }
 
-// updateExistingNamespaceMetadata adds GitOps metadata to the existing namespace
-func (r *registrationService) updateExistingNamespaceMetadata(ctx context.Context, req *types.ExistingNamespaceRequest, registrationID string) {
+// updateExistingNamespaceMetadata claims the existing namespace by adding GitOps metadata. It returns what it
+// wrote along with the namespace's metadata from before the claim, so a failed conversion can put back values
+// the claim overwrote. Only a refused claim fails the registration; other metadata errors are logged and
+// registration continues with nothing claimed.
+func (r *registrationService) updateExistingNamespaceMetadata(ctx context.Context, req *types.ExistingNamespaceRequest,
+	registrationID string, userInfo *types.UserInfo) (claimed, original NamespaceMetadata, err error) {
 	r.logger.WithField("namespace", req.ExistingNamespace).Info("Adding GitOps metadata to existing namespace")
 
-	repoHash := fmt.Sprintf("%x", sha256.Sum256([]byte(req.Repository.URL)))[:8]
+	repoHash := r.repositoryHash(req.Repository)
 	repoDomain := extractRepositoryDomain(req.Repository.URL)
 
 	namespaceLabels := map[string]string{
 		"gitops.io/registration-id":    registrationID[:8],
-		"gitops.io/repository-hash":    repoHash,
 		"gitops.io/repository-domain":  repoDomain,
 		"gitops.io/managed-by":         "gitops-registration-service",
 		"app.kubernetes.io/managed-by": "gitops-registration-service",
 	}
+	namespaceLabels[repositoryHashLabel(r.cfg)] = repoHash
+	if org := extractRepositoryOrg(req.Repository.URL); org != "" {
+		namespaceLabels[RepositoryOrgLabel] = org
+	}
 
 	namespaceAnnotations := map[string]string{
 		"gitops.io/repository-url":    req.Repository.URL,
-		"gitops.io/repository-branch": req.Repository.Branch,
+		"gitops.io/repository-branch": r.branchOrDefault(req.Repository.Branch),
 		"gitops.io/registration-id":   registrationID,
+		ApplicationAnnotation:         TenantApplicationName(r.cfg, req.ExistingNamespace),
+	}
+	if req.Repository.Revision != "" {
+		namespaceAnnotations[RepositoryRevisionAnnotation] = req.Repository.Revision
 	}
 
-	err := r.k8s.UpdateNamespaceMetadata(ctx, req.ExistingNamespace, namespaceLabels, namespaceAnnotations)
+	// Record who converted the namespace so access can be re-checked later; the owner label is left
+	// off because existing namespaces do not count toward registration.maxPerUser
+	if userInfo != nil && userInfo.Username != "" {
+		namespaceAnnotations[OwnerLabel] = userInfo.Username
+	}
+
+	if r.cfg.Tenants.ApplyDefaultsToExistingNamespaces {
+		namespaceLabels = withDefaultMetadata(r.cfg.Tenants.DefaultNamespaceLabels, namespaceLabels)
+		namespaceAnnotations = withDefaultMetadata(r.cfg.Tenants.DefaultNamespaceAnnotations, namespaceAnnotations)
+	}
+
+	if info, err := r.k8s.GetNamespaceMetadata(ctx, req.ExistingNamespace); err != nil {
+		r.logger.WithError(err).WithField("namespace", req.ExistingNamespace).
+			Warn("Failed to read namespace metadata; a failed conversion will remove the claimed keys instead of restoring them")
+	} else {
+		original = NamespaceMetadata{Labels: info.Labels, Annotations: info.Annotations}
+	}
+
+	err = r.k8s.ClaimNamespace(ctx, req.ExistingNamespace, namespaceLabels, namespaceAnnotations)
+	var notManaged *NotManagedError
+	if errors.As(err, &notManaged) {
+		return NamespaceMetadata{}, NamespaceMetadata{}, err
+	}
 	if err != nil {
 		r.logger.WithError(err).WithField("namespace", req.ExistingNamespace).Warn("Failed to update namespace metadata, continuing...")
+		return NamespaceMetadata{}, NamespaceMetadata{}, nil
+	}
+	return NamespaceMetadata{Labels: namespaceLabels, Annotations: namespaceAnnotations}, original, nil
+}
+
+// unclaimExistingNamespace reverts the metadata updateExistingNamespaceMetadata wrote: keys the namespace
+// already had get their original value back and the rest are removed, so a failed conversion leaves the
+// namespace as it was and free to be registered again
+func (r *registrationService) unclaimExistingNamespace(ctx context.Context, namespace string, claimed, original NamespaceMetadata) {
+	if len(claimed.Labels) == 0 && len(claimed.Annotations) == 0 {
+		return
+	}
+	if err := r.k8s.UnclaimNamespace(cleanupContext(ctx), namespace, claimed, original); err != nil {
+		r.logger.WithError(err).WithField("namespace", namespace).Error("Failed to revert namespace metadata")
+	}
+}
+
+// cleanupExistingNamespaceResources removes the service account and role binding the conversion created
+// and any partially created AppProject, leaving the existing namespace and pre-existing RBAC untouched
+func (r *registrationService) cleanupExistingNamespaceResources(ctx context.Context, namespace, projectName string, created existingNamespaceRBAC) {
+	ctx = cleanupContext(ctx)
+	logger := r.logger.WithField("namespace", namespace)
+
+	if projectName != "" {
+		if err := r.argocd.DeleteAppProject(ctx, projectName); err != nil {
+			logger.WithError(err).WithField("appProject", projectName).Error("Failed to cleanup AppProject")
+		}
+	}
+
+	serviceAccountName, roleBindingName, _ := r.legacyRBACNames()
+	if created.roleBinding {
+		if err := r.k8s.DeleteRoleBinding(ctx, namespace, roleBindingName); err != nil {
+			logger.WithError(err).Error("Failed to cleanup role binding")
+		}
+	}
+	if created.serviceAccount {
+		if err := r.k8s.DeleteServiceAccount(ctx, namespace, serviceAccountName); err != nil {
+			logger.WithError(err).Error("Failed to cleanup service account")
+		}
 	}
 }
 
-// setupArgoCDResourcesForExistingNamespace creates ArgoCD AppProject and Application for existing namespace
-func (r *registrationService) setupArgoCDResourcesForExistingNamespace(ctx context.Context, req *types.ExistingNamespaceRequest) (appName, projectName string, err error) {
+// setupArgoCDResourcesForExistingNamespace creates ArgoCD AppProject and Application for existing namespace.
+// adopted reports that an AppProject already named after the namespace was taken over instead of created.
+func (r *registrationService) setupArgoCDResourcesForExistingNamespace(ctx context.Context, req *types.ExistingNamespaceRequest) (
+	appName, projectName string, adopted bool, err error,
+) {
 	projectName = req.ExistingNamespace
-	appProject := r.buildAppProject(projectName, req.ExistingNamespace, req.Repository.URL, "gitops")
+	serviceAccountName, _, _ := r.legacyRBACNames()
+	appProject := r.buildAppProject(projectName, req.ExistingNamespace, req.Repository, serviceAccountName, InClusterServer, "")
+	ownerReferences := r.namespaceOwnerReferences(ctx, req.ExistingNamespace, InClusterServer)
+	appProject.OwnerReferences = ownerReferences
 
-	if err := r.argocd.CreateAppProject(ctx, appProject); err != nil {
-		return "", "", fmt.Errorf("failed to create ArgoCD AppProject: %w", err)
+	adopted, err = r.adoptExistingAppProject(ctx, appProject)
+	if err != nil {
+		return "", "", false, err
+	}
+	if !adopted {
+		if err := r.argocd.CreateAppProject(ctx, appProject); err != nil {
+			return "", "", false, fmt.Errorf("failed to create ArgoCD AppProject: %w", err)
+		}
 	}
 
-	appName = fmt.Sprintf("%s-app", req.ExistingNamespace)
-	application := &types.Application{
-		Name:    appName,
-		Project: projectName,
-		Source: types.ApplicationSource{
-			RepoURL:        req.Repository.URL,
-			TargetRevision: req.Repository.Branch,
-			Path:           "manifests",
-		},
-		Destination: types.ApplicationDestination{
-			Server:    "https://kubernetes.default.svc",
-			Namespace: req.ExistingNamespace,
-		},
+	appName = TenantApplicationName(r.cfg, req.ExistingNamespace)
+	syncPolicy := r.existingNamespaceSyncPolicy(ctx, req.ExistingNamespace)
+	source := r.applicationSource(req.Repository)
+	if err := r.createTenantApplication(ctx, appName, projectName, req.ExistingNamespace, source,
+		InClusterServer, ownerReferences, syncPolicy, r.notificationSubscriptions(nil)); err != nil {
+		// Report a created AppProject so the caller can clean it up; an adopted one predates this registration
+		if adopted {
+			return "", "", true, err
+		}
+		return "", projectName, false, err
 	}
 
-	if err := r.argocd.CreateApplication(ctx, application); err != nil {
-		return "", "", fmt.Errorf("failed to create ArgoCD Application: %w", err)
+	return appName, projectName, adopted, nil
+}
+
+// adoptExistingAppProject takes over an AppProject that already exists under the project's name when
+// registration.adoptExistingAppProject is set, and reports an ApplicationConflictError otherwise.
+// It returns false when there is no such AppProject and one should be created.
+func (r *registrationService) adoptExistingAppProject(ctx context.Context, project *types.AppProject) (bool, error) {
+	exists, err := r.argocd.AppProjectExists(ctx, project.Name)
+	if err != nil {
+		return false, fmt.Errorf("failed to check AppProject conflict: %w", err)
+	}
+	if !exists {
+		return false, nil
+	}
+	if !r.cfg.Registration.AdoptExistingAppProject {
+		return false, &ApplicationConflictError{Application: project.Name, Kind: "AppProject"}
 	}
 
-	return appName, projectName, nil
+	if err := r.argocd.AdoptAppProject(ctx, project); err != nil {
+		return false, fmt.Errorf("failed to adopt ArgoCD AppProject: %w", err)
+	}
+	return true, nil
 }
 
 // finalizeExistingNamespaceRegistration updates the registration record with success status
-func (r *registrationService) finalizeExistingNamespaceRegistration(registration *types.Registration, appName, projectName string, userInfo *types.UserInfo) {
+func (r *registrationService) finalizeExistingNamespaceRegistration(registration *types.Registration, appName, projectName string, adopted bool) {
 	registration.Status.Phase = "active"
 	registration.Status.Message = "Existing namespace successfully converted to GitOps management"
 	registration.Status.ArgoCDApplication = appName
 	registration.Status.ArgoCDAppProject = projectName
 	registration.Status.LastSyncTime = time.Now()
 	registration.Status.NamespaceCreated = false // Existing namespace, not created by us
-	registration.Status.AppProjectCreated = true
+	registration.Status.AppProjectCreated = !adopted
 	registration.Status.ApplicationCreated = true
 	registration.UpdatedAt = time.Now()
 }
@@ -503,13 +1764,415 @@ func (r *registrationService) ValidateRegistration(ctx context.Context, req *typ
 	if req.Namespace == "" {
 		return fmt.Errorf("namespace is required")
 	}
-	if req.Repository.URL == "" {
-		return fmt.Errorf("repository URL is required")
+	if err := ValidateRepositoryURL(req.Repository.URL); err != nil {
+		return err
+	}
+	if req.Repository.Revision != "" {
+		if err := ValidateRevision(req.Repository.Revision); err != nil {
+			return err
+		}
+	}
+	if err := ValidateManifestPath(req.Repository.Path); err != nil {
+		return err
+	}
+	if err := r.checkNamespaceDenyPatterns(req.Namespace); err != nil {
+		return err
+	}
+	if err := r.validateDestinationCluster(req.DestinationCluster); err != nil {
+		return err
+	}
+	if err := r.validateRepositoryHost(req.Repository.URL); err != nil {
+		return err
+	}
+	for _, repo := range req.AdditionalSourceRepos {
+		if repo == "" {
+			return fmt.Errorf("additionalSourceRepos must not contain empty URLs")
+		}
+		if err := ValidateRepositoryURL(repo); err != nil {
+			return err
+		}
+		if err := r.validateRepositoryHost(repo); err != nil {
+			return err
+		}
+	}
+	for i, window := range req.SyncWindows {
+		if err := config.ValidateSyncWindow(window.Kind, window.Schedule, window.Duration); err != nil {
+			return fmt.Errorf("syncWindows[%d]: %w", i, err)
+		}
+	}
+	if req.Team != "" {
+		if err := ValidateTeam(req.Team); err != nil {
+			return err
+		}
+	}
+	if err := r.validateRequestResourceRestrictions(req); err != nil {
+		return err
+	}
+	if err := validateSourceTools(req.Helm, req.Kustomize); err != nil {
+		return err
+	}
+	if err := validateNotifications(req.Notifications); err != nil {
+		return err
+	}
+	if err := validateMetadata(req.Metadata); err != nil {
+		return err
+	}
+	if err := r.validateAnnotations(req.Annotations); err != nil {
+		return err
+	}
+	if req.ServiceAccountName != "" {
+		if errs := k8svalidation.IsDNS1123Subdomain(req.ServiceAccountName); len(errs) > 0 {
+			return fmt.Errorf("serviceAccountName %q is not a valid service account name: %s",
+				req.ServiceAccountName, strings.Join(errs, "; "))
+		}
+	}
+
+	return nil
+}
+
+// maxMetadataValueLength bounds a metadata tag value so tags stay small next to the namespace's other annotations
+const maxMetadataValueLength = 256
+
+// validateMetadata checks that every metadata tag can be stored as a gitops.io/meta-<key> annotation,
+// reporting the first invalid key in key order
+func validateMetadata(metadata map[string]string) error {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
 	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if err := ValidateMetadataKey(key); err != nil {
+			return err
+		}
+		if len(metadata[key]) > maxMetadataValueLength {
+			return fmt.Errorf("metadata %q must be at most %d characters", key, maxMetadataValueLength)
+		}
+	}
+	return nil
+}
 
+// validateNotifications checks that each subscription renders to a valid annotation and that no trigger and
+// service pair is subscribed twice, as the later one would silently replace the earlier
+func validateNotifications(subscriptions []types.NotificationSubscription) error {
+	subscribed := make(map[string]bool, len(subscriptions))
+	for i, subscription := range subscriptions {
+		if err := config.ValidateNotificationSubscription(subscription.Trigger, subscription.Service, subscription.Recipients); err != nil {
+			return fmt.Errorf("notifications[%d]: %w", i, err)
+		}
+		key := config.NotificationSubscriptionKey(subscription.Trigger, subscription.Service)
+		if subscribed[key] {
+			return fmt.Errorf("notifications[%d]: duplicate subscription to %s through %s", i, subscription.Trigger, subscription.Service)
+		}
+		subscribed[key] = true
+	}
 	return nil
 }
 
+// validateSourceTools checks the Helm or Kustomize overrides of a request; an Application source is
+// rendered by a single tool, so at most one block may be set
+func validateSourceTools(helm *types.ApplicationSourceHelm, kustomize *types.ApplicationSourceKustomize) error {
+	if helm != nil && kustomize != nil {
+		return fmt.Errorf("only one of helm and kustomize may be set")
+	}
+	if helm != nil {
+		for i, parameter := range helm.Parameters {
+			if parameter.Name == "" {
+				return fmt.Errorf("helm.parameters[%d]: name is required", i)
+			}
+		}
+		if helm.Values != "" {
+			var values map[string]interface{}
+			if err := yaml.Unmarshal([]byte(helm.Values), &values); err != nil {
+				return fmt.Errorf("helm.values must be a YAML mapping: %w", err)
+			}
+		}
+	}
+	if kustomize != nil {
+		for i, image := range kustomize.Images {
+			if strings.TrimSpace(image) == "" {
+				return fmt.Errorf("kustomize.images[%d] must not be empty", i)
+			}
+		}
+	}
+	return nil
+}
+
+// validateRequestResourceRestrictions checks a request's resource lists with the rules applied to the
+// service-level lists. A request list may only narrow the service list of the same kind, so it is
+// rejected when that list is not configured and an allow list must stay within the service allow list.
+func (r *registrationService) validateRequestResourceRestrictions(req *types.RegistrationRequest) error {
+	if err := config.ValidateResourceRestrictions(
+		toServiceResourceRestrictions(req.ResourceAllowList), toServiceResourceRestrictions(req.ResourceDenyList),
+	); err != nil {
+		return err
+	}
+
+	if len(req.ResourceDenyList) > 0 && len(r.cfg.Security.ResourceDenyList) == 0 {
+		return &config.ResourceRestrictionError{
+			Field:  "resourceDenyList",
+			Index:  -1,
+			Reason: "resourceDenyList requires security.resourceDenyList to be configured",
+		}
+	}
+
+	serviceAllowList := r.cfg.Security.ResourceAllowList
+	if len(req.ResourceAllowList) > 0 && len(serviceAllowList) == 0 {
+		return &config.ResourceRestrictionError{
+			Field:  "resourceAllowList",
+			Index:  -1,
+			Reason: "resourceAllowList requires security.resourceAllowList to be configured",
+		}
+	}
+	for i, resource := range req.ResourceAllowList {
+		if !slices.ContainsFunc(serviceAllowList, func(allowed config.ServiceResourceRestriction) bool {
+			return (allowed.Group == "*" || allowed.Group == resource.Group) && (allowed.Kind == "*" || allowed.Kind == resource.Kind)
+		}) {
+			return &config.ResourceRestrictionError{
+				Field:  "resourceAllowList",
+				Index:  i,
+				Reason: fmt.Sprintf("kind %s in group %q is not permitted by the service resource allow list", resource.Kind, resource.Group),
+			}
+		}
+	}
+	return nil
+}
+
+// toServiceResourceRestrictions converts request resource lists for validation against the service rules
+func toServiceResourceRestrictions(resources []types.AppProjectResource) []config.ServiceResourceRestriction {
+	result := make([]config.ServiceResourceRestriction, len(resources))
+	for i, resource := range resources {
+		result[i] = config.ServiceResourceRestriction{Group: resource.Group, Kind: resource.Kind}
+	}
+	return result
+}
+
+// applyRequestResourceRestrictions narrows an AppProject's resource lists with those from the request:
+// an allow list replaces the wider service whitelist and a deny list extends the service blacklist
+func applyRequestResourceRestrictions(appProject *types.AppProject, req *types.RegistrationRequest) {
+	if len(req.ResourceAllowList) > 0 {
+		appProject.ClusterResourceWhitelist = slices.Clone(req.ResourceAllowList)
+		appProject.NamespaceResourceWhitelist = slices.Clone(req.ResourceAllowList)
+	}
+	if len(req.ResourceDenyList) > 0 {
+		appProject.ClusterResourceBlacklist = append(appProject.ClusterResourceBlacklist, req.ResourceDenyList...)
+		appProject.NamespaceResourceBlacklist = append(appProject.NamespaceResourceBlacklist, req.ResourceDenyList...)
+	}
+}
+
+// ValidateTeam ensures a team name can be stored as the value of the gitops.io/team label
+func ValidateTeam(team string) error {
+	if team == "" {
+		return fmt.Errorf("team must not be empty")
+	}
+	if errs := k8svalidation.IsValidLabelValue(team); len(errs) > 0 {
+		return fmt.Errorf("team %q is not a valid label value: %s", team, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// ValidateRepositoryOrg ensures an organization can be matched against the gitops.io/repository-org label
+func ValidateRepositoryOrg(org string) error {
+	if org == "" {
+		return fmt.Errorf("org must not be empty")
+	}
+	if errs := k8svalidation.IsValidLabelValue(strings.ToLower(org)); len(errs) > 0 {
+		return fmt.Errorf("org %q is not a valid label value: %s", org, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// ValidateMetadataKey ensures a metadata tag key can name a gitops.io/meta-<key> annotation
+func ValidateMetadataKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("metadata key must not be empty")
+	}
+	if errs := k8svalidation.IsQualifiedName(MetadataAnnotationPrefix + key); len(errs) > 0 {
+		return fmt.Errorf("metadata key %q is not valid: %s", key, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Lengths of full SHA-1 and SHA-256 commit IDs
+const (
+	maxCommitSHALength    = 40
+	sha256CommitSHALength = 64
+)
+
+// ValidateRevision ensures a repository revision can name a branch, tag or commit. A revision made only of
+// hex digits is taken as a commit SHA and must be at most a full SHA-1 or exactly a SHA-256 commit ID long.
+func ValidateRevision(revision string) error {
+	if strings.ContainsAny(revision, " \t\n~^:?*[\\") || strings.Contains(revision, "..") ||
+		strings.HasPrefix(revision, "-") || strings.HasSuffix(revision, "/") {
+		return fmt.Errorf("repository revision %q is not a valid branch, tag or commit SHA", revision)
+	}
+
+	isHex := strings.Trim(strings.ToLower(revision), "0123456789abcdef") == ""
+	if isHex && len(revision) > maxCommitSHALength && len(revision) != sha256CommitSHALength {
+		return fmt.Errorf("repository revision %q looks like a commit SHA but has %d characters, expected at most %d or %d",
+			revision, len(revision), maxCommitSHALength, sha256CommitSHALength)
+	}
+	return nil
+}
+
+// ValidateManifestPath ensures a manifest directory stays inside the repository: it may not climb out with ..
+// segments or contain backslashes or control characters. Surrounding slashes are allowed and trimmed later.
+func ValidateManifestPath(manifestPath string) error {
+	if strings.ContainsAny(manifestPath, "\\\x00\n\r\t") {
+		return fmt.Errorf("manifest path %q contains characters that are not allowed", manifestPath)
+	}
+	for _, segment := range strings.Split(manifestPath, "/") {
+		if segment == ".." {
+			return fmt.Errorf("manifest path %q must not leave the repository", manifestPath)
+		}
+	}
+	return nil
+}
+
+// repositoryURLSchemes are the URL schemes ArgoCD can clone from
+var repositoryURLSchemes = []string{"https", "http", "ssh", "git"}
+
+// ValidateRepositoryURL returns an InvalidRepositoryURLError unless repoURL is a scheme://host/path URL with a
+// scheme ArgoCD can clone from, or scp-style [user@]host:path. Without this check an unparseable URL would be
+// registered with an "unknown" repository domain and an Application that can never sync.
+func ValidateRepositoryURL(repoURL string) error {
+	if repoURL == "" {
+		return &InvalidRepositoryURLError{}
+	}
+	if strings.ContainsAny(repoURL, " \t\r\n") {
+		return &InvalidRepositoryURLError{URL: repoURL, Reason: "must not contain whitespace"}
+	}
+
+	if strings.Contains(repoURL, "://") {
+		parsed, err := url.Parse(repoURL)
+		if err != nil {
+			return &InvalidRepositoryURLError{URL: repoURL, Reason: "cannot be parsed"}
+		}
+		if !slices.Contains(repositoryURLSchemes, strings.ToLower(parsed.Scheme)) {
+			return &InvalidRepositoryURLError{URL: repoURL,
+				Reason: fmt.Sprintf("scheme must be one of %s", strings.Join(repositoryURLSchemes, ", "))}
+		}
+		if parsed.Hostname() == "" {
+			return &InvalidRepositoryURLError{URL: repoURL, Reason: "host is required"}
+		}
+		if strings.Trim(parsed.Path, "/") == "" {
+			return &InvalidRepositoryURLError{URL: repoURL, Reason: "repository path is required"}
+		}
+		return nil
+	}
+
+	// scp-style syntax has no scheme: [user@]host:path
+	hostPart, repoPath, found := strings.Cut(repoURL, ":")
+	if !found {
+		return &InvalidRepositoryURLError{URL: repoURL, Reason: "must be scheme://host/path or [user@]host:path"}
+	}
+	if at := strings.LastIndex(hostPart, "@"); at >= 0 {
+		hostPart = hostPart[at+1:]
+	}
+	if hostPart == "" || strings.Contains(hostPart, "/") {
+		return &InvalidRepositoryURLError{URL: repoURL, Reason: "host is required"}
+	}
+	if strings.Trim(repoPath, "/") == "" {
+		return &InvalidRepositoryURLError{URL: repoURL, Reason: "repository path is required"}
+	}
+	return nil
+}
+
+// validateRepositoryHost ensures a repository URL points at a host in the configured allowlist
+func (r *registrationService) validateRepositoryHost(repoURL string) error {
+	allowed := r.cfg.Security.AllowedRepositoryHosts
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	host := extractRepositoryHost(repoURL)
+	for _, allowedHost := range allowed {
+		if host != "" && strings.EqualFold(host, allowedHost) {
+			return nil
+		}
+	}
+	return fmt.Errorf("repository host of %s is not allowed", repoURL)
+}
+
+// extractRepositoryHost returns the host of an HTTP(S), SSH or scp-style (git@host:org/repo) repository URL
+func extractRepositoryHost(repoURL string) string {
+	if parsed, err := url.Parse(repoURL); err == nil && parsed.Host != "" {
+		return parsed.Hostname()
+	}
+
+	// scp-style syntax has no scheme: [user@]host:path
+	if strings.Contains(repoURL, "://") {
+		return ""
+	}
+	hostPart, _, found := strings.Cut(repoURL, ":")
+	if !found {
+		return ""
+	}
+	if at := strings.LastIndex(hostPart, "@"); at >= 0 {
+		hostPart = hostPart[at+1:]
+	}
+	return hostPart
+}
+
+// extractRepositoryOrg returns the organization (or top-level GitLab group) that owns an HTTP(S), SSH or
+// scp-style repository, lower-cased because hosts treat it case-insensitively. It returns "" when the path
+// has no owner segment or the owner cannot be stored as a label value.
+func extractRepositoryOrg(repoURL string) string {
+	var repoPath string
+	if parsed, err := url.Parse(repoURL); err == nil && parsed.Host != "" {
+		repoPath = parsed.Path
+	} else if !strings.Contains(repoURL, "://") {
+		// scp-style syntax has no scheme: [user@]host:path
+		_, path, found := strings.Cut(repoURL, ":")
+		if !found {
+			return ""
+		}
+		repoPath = path
+	}
+
+	segments := strings.Split(strings.Trim(repoPath, "/"), "/")
+	if len(segments) < 2 || segments[0] == "" {
+		return ""
+	}
+	org := strings.ToLower(segments[0])
+	if len(k8svalidation.IsValidLabelValue(org)) > 0 {
+		return ""
+	}
+	return org
+}
+
+// validateDestinationCluster ensures a requested remote cluster is in the configured allowlist
+func (r *registrationService) validateDestinationCluster(cluster string) error {
+	if cluster == "" || cluster == InClusterServer {
+		return nil
+	}
+
+	for _, allowed := range r.cfg.ArgoCD.AllowedDestinationClusters {
+		if cluster == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("destination cluster %s is not allowed", cluster)
+}
+
+// resolveDestinationServer returns the API server URL for a destination given as a URL or an ArgoCD
+// cluster name, defaulting to the in-cluster API server. ArgoCD matches AppProject destinations and
+// destination service accounts by server URL, so names are resolved through ArgoCD's cluster secrets.
+func (r *registrationService) resolveDestinationServer(ctx context.Context, cluster string) (string, error) {
+	if cluster == "" {
+		return InClusterServer, nil
+	}
+	if strings.Contains(cluster, "://") {
+		return cluster, nil
+	}
+
+	server, err := r.argocd.ResolveClusterServer(ctx, cluster)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve destination cluster: %w", err)
+	}
+	return server, nil
+}
+
 func (r *registrationService) ValidateExistingNamespaceRequest(
 	ctx context.Context, req *types.ExistingNamespaceRequest,
 ) error {
@@ -517,41 +2180,91 @@ func (r *registrationService) ValidateExistingNamespaceRequest(
 	if req.ExistingNamespace == "" {
 		return fmt.Errorf("existingNamespace is required")
 	}
-	if req.Repository.URL == "" {
-		return fmt.Errorf("repository URL is required")
+	if err := ValidateRepositoryURL(req.Repository.URL); err != nil {
+		return err
+	}
+	if req.Repository.Revision != "" {
+		if err := ValidateRevision(req.Repository.Revision); err != nil {
+			return err
+		}
+	}
+	if err := ValidateManifestPath(req.Repository.Path); err != nil {
+		return err
+	}
+	if err := r.checkNamespaceDenyPatterns(req.ExistingNamespace); err != nil {
+		return err
 	}
+	if err := r.validateRepositoryHost(req.Repository.URL); err != nil {
+		return err
+	}
+
+	return nil
+}
 
+// checkNamespaceDenyPatterns rejects a namespace matching any of registration.namespaceDenyPatterns.
+// Patterns are validated when the configuration is loaded, so a malformed one simply never matches.
+func (r *registrationService) checkNamespaceDenyPatterns(namespace string) error {
+	for _, pattern := range r.cfg.Registration.NamespaceDenyPatterns {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return &NamespaceForbiddenError{Namespace: namespace, Pattern: pattern}
+		}
+	}
 	return nil
 }
 
 func (r *registrationService) buildAppProject(
-	projectName, namespace, repoURL, serviceAccountName string,
+	projectName, namespace string, repository types.Repository, serviceAccountName, destinationServer, team string,
+	additionalRepos ...string,
 ) *types.AppProject {
 	// Generate repository hash for labeling
-	repoHash := GenerateRepositoryHash(repoURL)
+	repoURL := repository.URL
+	repoHash := r.repositoryHash(repository)
 
 	appProject := &types.AppProject{
 		Name:      projectName,
 		Namespace: r.cfg.ArgoCD.Namespace, // AppProjects live in ArgoCD namespace
 		Labels: map[string]string{
-			RepositoryHashLabel:            repoHash,
+			repositoryHashLabel(r.cfg):     repoHash,
 			"gitops.io/managed-by":         "gitops-registration-service",
 			"app.kubernetes.io/managed-by": "gitops-registration-service",
 		},
 		Destinations: []types.AppProjectDestination{
 			{
-				Server:    "https://kubernetes.default.svc",
+				Server:    destinationServer,
 				Namespace: namespace,
 			},
 		},
 		SourceRepos: []string{repoURL},
+		SyncWindows: r.defaultSyncWindows(),
+	}
+	// An empty namespace only matches cluster-scoped resources, so namespaced resources stay locked to
+	// the tenant namespace while clusterResourceWhitelist limits which cluster resources are allowed
+	if r.cfg.Registration.AllowClusterScopedDestination && len(r.cfg.Security.ResourceAllowList) > 0 {
+		appProject.Destinations = append(appProject.Destinations, types.AppProjectDestination{
+			Server:    destinationServer,
+			Namespace: ClusterScopedDestinationNamespace,
+		})
+	}
+	if team != "" {
+		appProject.Labels[TeamLabel] = team
+	}
+	if org := extractRepositoryOrg(repoURL); org != "" {
+		appProject.Labels[RepositoryOrgLabel] = org
+	}
+
+	// Additional repositories only widen what the project may source from; the
+	// primary repository alone drives the hash label and conflict detection
+	for _, repo := range additionalRepos {
+		if !slices.Contains(appProject.SourceRepos, repo) {
+			appProject.SourceRepos = append(appProject.SourceRepos, repo)
+		}
 	}
 
 	// Add impersonation support if enabled
 	if r.cfg.Security.Impersonation.Enabled {
 		appProject.DestinationServiceAccounts = []types.AppProjectDestinationServiceAccount{
 			{
-				Server:                "https://kubernetes.default.svc",
+				Server:                destinationServer,
 				Namespace:             namespace,
 				DefaultServiceAccount: serviceAccountName,
 			},
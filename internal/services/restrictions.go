@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// ValidateResourceRestrictionKinds checks every entry of security.resourceAllowList and resourceDenyList
+// against the cluster's API discovery, since ArgoCD silently ignores entries naming kinds that do not exist.
+// Wildcard entries are not checked. Unknown kinds and discovery failures are logged as warnings, and only
+// returned as an error when security.strictResourceValidation is set.
+func ValidateResourceRestrictionKinds(
+	ctx context.Context, k8s KubernetesService, cfg *config.Config, logger *logrus.Logger,
+) error {
+	lists := []struct {
+		field   string
+		entries []config.ServiceResourceRestriction
+	}{
+		{field: "security.resourceAllowList", entries: cfg.Security.ResourceAllowList},
+		{field: "security.resourceDenyList", entries: cfg.Security.ResourceDenyList},
+	}
+
+	var unknown []string
+	for _, list := range lists {
+		for i, entry := range list.entries {
+			if entry.Group == "*" || entry.Kind == "*" {
+				continue
+			}
+
+			exists, err := k8s.ResourceKindExists(ctx, entry.Group, entry.Kind)
+			if err != nil {
+				logger.WithError(err).Warn("Failed to validate resource restrictions against the cluster")
+				if cfg.Security.StrictResourceValidation {
+					return fmt.Errorf("failed to validate resource restrictions: %w", err)
+				}
+				return nil
+			}
+			if exists {
+				continue
+			}
+
+			logger.WithFields(logrus.Fields{
+				"field": fmt.Sprintf("%s[%d]", list.field, i),
+				"group": entry.Group,
+				"kind":  entry.Kind,
+			}).Warn("Resource restriction names a kind the cluster does not serve and will have no effect")
+			unknown = append(unknown, fmt.Sprintf("%s[%d] (group %q, kind %q)", list.field, i, entry.Group, entry.Kind))
+		}
+	}
+
+	if len(unknown) > 0 && cfg.Security.StrictResourceValidation {
+		return fmt.Errorf("resource restrictions name kinds the cluster does not serve: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
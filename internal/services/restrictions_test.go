@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateResourceRestrictionKinds(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+	service := &kubernetesService{client: newDiscoveryClient(), logger: logger}
+
+	known := []config.ServiceResourceRestriction{{Group: "", Kind: "ConfigMap"}, {Group: "apps", Kind: "*"}}
+	unknown := []config.ServiceResourceRestriction{{Group: "apps", Kind: "Deployment"}, {Group: "apps", Kind: "Deploymnet"}}
+
+	tests := []struct {
+		name      string
+		allowList []config.ServiceResourceRestriction
+		denyList  []config.ServiceResourceRestriction
+		strict    bool
+		errorMsg  string
+	}{
+		{name: "Known kinds", allowList: known, strict: true},
+		{name: "Unknown kind only warns", denyList: unknown},
+		{
+			name:     "Unknown kind fails in strict mode",
+			denyList: unknown,
+			strict:   true,
+			errorMsg: `security.resourceDenyList[1] (group "apps", kind "Deploymnet")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Security: config.SecurityConfig{
+				ResourceAllowList:        tt.allowList,
+				ResourceDenyList:         tt.denyList,
+				StrictResourceValidation: tt.strict,
+			}}
+
+			err := ValidateResourceRestrictionKinds(ctx, service, cfg, logger)
+			if tt.errorMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+
+	t.Run("Discovery failure only fails in strict mode", func(t *testing.T) {
+		mockK8s := &MockKubernetesService{}
+		mockK8s.On("ResourceKindExists", ctx, "", "ConfigMap").Return(false, errors.New("discovery unavailable"))
+		cfg := &config.Config{Security: config.SecurityConfig{ResourceAllowList: known}}
+
+		assert.NoError(t, ValidateResourceRestrictionKinds(ctx, mockK8s, cfg, logger))
+
+		cfg.Security.StrictResourceValidation = true
+		err := ValidateResourceRestrictionKinds(ctx, mockK8s, cfg, logger)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "discovery unavailable")
+		mockK8s.AssertNotCalled(t, "ResourceKindExists", mock.Anything, "apps", mock.Anything)
+	})
+}
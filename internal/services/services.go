@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/x509"
 	"fmt"
+	"time"
 
 	"github.com/konflux-ci/gitops-registration-service/internal/config"
 	"github.com/konflux-ci/gitops-registration-service/internal/types"
@@ -14,12 +16,60 @@ import (
 const (
 	RepositoryHashLabel = "gitops.io/repository-hash"
 	ServiceAccountLabel = "gitops.io/service-account"
+	// Records the service version that created a namespace or ArgoCD resource
+	CreatedByVersionAnnotation = "gitops.io/created-by-version"
+	// Records when a registration was archived; archived registrations await a purge
+	ArchivedAtAnnotation = "gitops.io/archived-at"
+	// Records the requested branch, tag or commit SHA when it differs from following the branch
+	RepositoryRevisionAnnotation = "gitops.io/repository-revision"
+	// Records the tenant's Application or ApplicationSet name; absent when the registration has none
+	ApplicationAnnotation = "gitops.io/application"
+)
+
+// Repository hash lengths: the default keeps labels short and readable, the maximum is the longest label value
+const (
+	DefaultRepositoryHashLength = 8
+	MaxRepositoryHashLength     = 63
 )
 
 // GenerateRepositoryHash creates a consistent hash for repository URLs
 func GenerateRepositoryHash(repositoryURL string) string {
-	hash := sha256.Sum256([]byte(repositoryURL))
-	return fmt.Sprintf("%x", hash)[:8] // Use first 8 characters for readability
+	return GenerateRepositoryHashWithLength(repositoryURL, DefaultRepositoryHashLength)
+}
+
+// GenerateRepositoryHashWithLength creates a repository hash of the first length hex characters of the
+// SHA-256 of the URL, clamped to [1, MaxRepositoryHashLength]
+func GenerateRepositoryHashWithLength(repositoryURL string, length int) string {
+	return truncatedHash(repositoryURL, length)
+}
+
+// GenerateRepositoryPathHash creates a consistent hash for a directory within a repository, so that
+// registrations of different paths in a mono-repo do not collide
+func GenerateRepositoryPathHash(repositoryURL, path string) string {
+	return GenerateRepositoryPathHashWithLength(repositoryURL, path, DefaultRepositoryHashLength)
+}
+
+// GenerateRepositoryPathHashWithLength is GenerateRepositoryPathHash with a configurable length
+func GenerateRepositoryPathHashWithLength(repositoryURL, path string, length int) string {
+	return truncatedHash(repositoryURL+"#"+path, length)
+}
+
+// truncatedHash returns the first length hex characters of the SHA-256 of data
+func truncatedHash(data string, length int) string {
+	if length < 1 {
+		length = 1
+	}
+	if length > MaxRepositoryHashLength {
+		length = MaxRepositoryHashLength
+	}
+	hash := sha256.Sum256([]byte(data))
+	return fmt.Sprintf("%x", hash)[:length]
+}
+
+// GenerateOwnerHash creates a label-safe value identifying the user that owns a tenant namespace
+func GenerateOwnerHash(username string) string {
+	hash := sha256.Sum256([]byte(username))
+	return fmt.Sprintf("%x", hash)[:16]
 }
 
 // Services holds all service dependencies
@@ -38,13 +88,32 @@ type KubernetesService interface {
 	CreateNamespaceWithMetadata(ctx context.Context, name string, labels, annotations map[string]string) error
 	UpdateNamespaceLabels(ctx context.Context, name string, labels map[string]string) error
 	UpdateNamespaceMetadata(ctx context.Context, name string, labels, annotations map[string]string) error
+	ClaimNamespace(ctx context.Context, name string, labels, annotations map[string]string) error
+	UnclaimNamespace(ctx context.Context, name string, claimed, original NamespaceMetadata) error
 	DeleteNamespace(ctx context.Context, name string) error
+	AddNamespaceFinalizer(ctx context.Context, name string) error
+	RemoveNamespaceFinalizer(ctx context.Context, name string) error
 	NamespaceExists(ctx context.Context, name string) (bool, error)
+	GetNamespaceUID(ctx context.Context, name string) (string, error)
+	GetNamespaceMetadata(ctx context.Context, name string) (*NamespaceInfo, error)
 	CountNamespaces(ctx context.Context) (int, error)
+	CountManagedNamespaces(ctx context.Context) (int, error)
+	ListManagedNamespaces(ctx context.Context) ([]string, error)
+	ListTerminatingNamespaces(ctx context.Context) ([]string, error)
+	CountNamespacesByOwner(ctx context.Context, owner string) (int, error)
+	ListManagedNamespaceInfo(ctx context.Context, selector string) ([]NamespaceInfo, error)
+	ListEvents(ctx context.Context, namespace string) ([]types.RegistrationEvent, error)
 	CreateServiceAccount(ctx context.Context, namespace, name string) error
 	CreateRoleBinding(ctx context.Context, namespace, name, role, serviceAccount string) error
+	CreateLimitRange(ctx context.Context, namespace string, spec *types.LimitRangeSpec) error
+	DeleteServiceAccount(ctx context.Context, namespace, name string) error
+	DeleteRoleBinding(ctx context.Context, namespace, name string) error
+	ServiceAccountExists(ctx context.Context, namespace, name string) (bool, error)
+	RoleBindingExists(ctx context.Context, namespace, name string) (bool, error)
 	// New impersonation methods
 	ValidateClusterRole(ctx context.Context, name string) (*ClusterRoleValidation, error)
+	HasNamespaceRole(ctx context.Context, userInfo *types.UserInfo, namespace, clusterRole string) (bool, error)
+	ResourceKindExists(ctx context.Context, group, kind string) (bool, error)
 	CreateServiceAccountWithGenerateName(ctx context.Context, namespace, baseName string) (string, error)
 	CreateRoleBindingForServiceAccount(ctx context.Context, namespace, name, clusterRole, serviceAccountName string) error
 	CheckAppProjectConflict(ctx context.Context, repositoryHash string) (bool, error)
@@ -55,19 +124,42 @@ type ArgoCDService interface {
 	HealthCheck(ctx context.Context) error
 	CreateAppProject(ctx context.Context, project *types.AppProject) error
 	DeleteAppProject(ctx context.Context, name string) error
+	AppProjectExists(ctx context.Context, name string) (bool, error)
+	AdoptAppProject(ctx context.Context, project *types.AppProject) error
 	CreateApplication(ctx context.Context, app *types.Application) error
+	CreateApplicationSet(ctx context.Context, appSet *types.ApplicationSet) error
 	DeleteApplication(ctx context.Context, name string) error
+	DeleteApplicationSet(ctx context.Context, name string) error
+	UpdateApplicationSource(ctx context.Context, name string, source *types.ApplicationSource) error
+	RefreshApplication(ctx context.Context, name string, hard bool) error
+	SuspendApplication(ctx context.Context, name string) error
+	SuspendApplicationSet(ctx context.Context, name string) error
+	ResumeApplication(ctx context.Context, name string, automated *types.ApplicationSyncPolicyAutomated) error
+	ResumeApplicationSet(ctx context.Context, name string, automated *types.ApplicationSyncPolicyAutomated) error
+	ApplicationExists(ctx context.Context, name string) (bool, error)
+	ApplicationSetExists(ctx context.Context, name string) (bool, error)
 	GetApplicationStatus(ctx context.Context, name string) (*types.ApplicationStatus, error)
+	ListApplicationStatuses(ctx context.Context) (map[string]*types.ApplicationStatus, error)
+	GetApplicationResources(ctx context.Context, name string) ([]types.ApplicationResource, error)
+	GetApplicationHistory(ctx context.Context, name string) ([]types.ApplicationHistoryEntry, error)
 	// New impersonation method
 	CheckAppProjectConflict(ctx context.Context, repositoryHash string) (bool, error)
+	FindAppProjectByRepoHash(ctx context.Context, repositoryHash string) (*types.AppProject, error)
+	DeleteAppProjectByRepoHash(ctx context.Context, repositoryHash string) (*types.AppProject, error)
+	ListManagedAppProjects(ctx context.Context) ([]types.AppProject, error)
+	EnsureRepoHashLabel(ctx context.Context, name, repoURL string) (bool, error)
+	ResolveClusterServer(ctx context.Context, name string) (string, error)
+	TestRepositoryConnection(ctx context.Context, repoURL, credsSecret string) error
 }
 
 // RegistrationService interface for registration management
 type RegistrationService interface {
-	CreateRegistration(ctx context.Context, req *types.RegistrationRequest) (*types.Registration, error)
+	CreateRegistration(ctx context.Context, req *types.RegistrationRequest, userInfo *types.UserInfo) (*types.Registration, error)
 	GetRegistration(ctx context.Context, id string) (*types.Registration, error)
 	ListRegistrations(ctx context.Context, filters map[string]string) ([]*types.Registration, error)
 	DeleteRegistration(ctx context.Context, id string) error
+	ArchiveRegistration(ctx context.Context, id string) (*types.Registration, error)
+	UnarchiveRegistration(ctx context.Context, id string) (*types.Registration, error)
 	RegisterExistingNamespace(
 		ctx context.Context, req *types.ExistingNamespaceRequest, userInfo *types.UserInfo,
 	) (*types.Registration, error)
@@ -85,10 +177,27 @@ type RegistrationControlService interface {
 type AuthorizationService interface {
 	ValidateNamespaceAccess(ctx context.Context, userInfo *types.UserInfo, namespace string) error
 	ExtractUserInfo(ctx context.Context, token string) (*types.UserInfo, error)
+	ExtractUserInfoFromCertificate(ctx context.Context, cert *x509.Certificate) (*types.UserInfo, error)
 	IsAdminUser(userInfo *types.UserInfo) bool
 }
 
 // ClusterRoleValidation holds the result of ClusterRole validation
+// NamespaceInfo is the metadata of a managed namespace that registrations are rebuilt from
+type NamespaceInfo struct {
+	Name              string
+	Labels            map[string]string
+	Annotations       map[string]string
+	CreationTimestamp time.Time
+	Terminating       bool // Deletion has started but the namespace has not been removed yet
+	Active            bool // The namespace status phase is Active
+}
+
+// NamespaceMetadata is a set of namespace labels and annotations
+type NamespaceMetadata struct {
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
 type ClusterRoleValidation struct {
 	Exists               bool     `json:"exists"`
 	HasClusterAdmin      bool     `json:"hasClusterAdmin"`
@@ -112,12 +221,17 @@ func NewWithFactories(cfg *config.Config, logger *logrus.Logger, k8sFactory Kube
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kubernetes service: %w", err)
 	}
+	// One limiter is shared so upstream.maxConcurrentRequests bounds Kubernetes and ArgoCD calls together
+	limiter := newUpstreamLimiter(cfg.Upstream, logger)
+	k8sService = withKubernetesLimits(k8sService, parseRequestTimeout(cfg.Kubernetes.RequestTimeout, logger), limiter)
+	k8sService = WithClusterRoleCache(k8sService, parseClusterRoleCacheTTL(cfg.Security.ClusterRoleCacheTTL, logger))
 
 	// Initialize ArgoCD service using factory
 	argoCDService, err := NewArgoCDServiceWithFactory(cfg, logger, argoCDFactory)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create argocd service: %w", err)
 	}
+	argoCDService = withArgoCDLimits(argoCDService, parseRequestTimeout(cfg.ArgoCD.RequestTimeout, logger), limiter)
 
 	// Initialize Authorization service
 	authService := NewAuthorizationService(cfg, k8sService, logger)
@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"testing"
 
 	"github.com/konflux-ci/gitops-registration-service/internal/config"
@@ -9,6 +11,9 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestKubernetesServiceStub_HealthCheck(t *testing.T) {
@@ -70,6 +75,18 @@ func TestKubernetesServiceStub_CountNamespaces(t *testing.T) {
 	assert.Equal(t, 5, count, "Stub should return fixed value of 5")
 }
 
+func TestKubernetesServiceStub_CountManagedNamespaces(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	stub := &kubernetesServiceStub{logger: logger}
+
+	ctx := context.Background()
+	count, err := stub.CountManagedNamespaces(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, count, "Stub should return fixed value of 5")
+}
+
 func TestArgoCDServiceStub_HealthCheck(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.FatalLevel)
@@ -157,6 +174,81 @@ func TestAuthorizationServiceStub_ValidateNamespaceAccess(t *testing.T) {
 	assert.NoError(t, err, "ValidateNamespaceAccess should succeed for stub")
 }
 
+func TestAuthorizationServiceStub_ValidateNamespaceAccess_RequiredRoles(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	ctx := context.Background()
+	userInfo := &types.UserInfo{Username: "test-user"}
+
+	roleFor := func(name, resource string) *rbacv1.ClusterRole {
+		return &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{resource}, Verbs: []string{"get"}},
+			},
+		}
+	}
+	// The user may only read configmaps, which only the second role requires
+	client := newRoleReviewClient(func(attrs *authorizationv1.ResourceAttributes) bool {
+		return attrs.Resource == "configmaps"
+	}, roleFor("konflux-admin-user-actions", "secrets"), roleFor("konflux-viewer-user-actions", "configmaps"))
+	k8s := &kubernetesService{client: client, logger: logger}
+
+	newStub := func(auth config.AuthorizationConfig) *authorizationServiceStub {
+		return &authorizationServiceStub{cfg: &config.Config{Authorization: auth}, k8s: k8s, logger: logger}
+	}
+
+	t.Run("Allowed by the second of two roles", func(t *testing.T) {
+		stub := newStub(config.AuthorizationConfig{
+			EnableSubjectAccessReview: true,
+			RequiredRoles:             []string{"konflux-admin-user-actions", "konflux-viewer-user-actions"},
+		})
+		assert.NoError(t, stub.ValidateNamespaceAccess(ctx, userInfo, "team-a"))
+	})
+
+	t.Run("Single requiredRole still applies", func(t *testing.T) {
+		stub := newStub(config.AuthorizationConfig{
+			EnableSubjectAccessReview: true,
+			RequiredRole:              "konflux-admin-user-actions",
+		})
+		err := stub.ValidateNamespaceAccess(ctx, userInfo, "team-a")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not hold any of the roles")
+	})
+
+	t.Run("requiredRole and requiredRoles combine", func(t *testing.T) {
+		stub := newStub(config.AuthorizationConfig{
+			EnableSubjectAccessReview: true,
+			RequiredRole:              "konflux-admin-user-actions",
+			RequiredRoles:             []string{"konflux-viewer-user-actions"},
+		})
+		assert.NoError(t, stub.ValidateNamespaceAccess(ctx, userInfo, "team-a"))
+	})
+
+	t.Run("Missing role does not mask a later grant", func(t *testing.T) {
+		stub := newStub(config.AuthorizationConfig{
+			EnableSubjectAccessReview: true,
+			RequiredRoles:             []string{"missing-role", "konflux-viewer-user-actions"},
+		})
+		assert.NoError(t, stub.ValidateNamespaceAccess(ctx, userInfo, "team-a"))
+	})
+
+	t.Run("Error when no role can be checked", func(t *testing.T) {
+		stub := newStub(config.AuthorizationConfig{
+			EnableSubjectAccessReview: true,
+			RequiredRoles:             []string{"missing-role"},
+		})
+		err := stub.ValidateNamespaceAccess(ctx, userInfo, "team-a")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to check access")
+	})
+
+	t.Run("Skipped when SubjectAccessReview is disabled", func(t *testing.T) {
+		stub := newStub(config.AuthorizationConfig{RequiredRoles: []string{"missing-role"}})
+		assert.NoError(t, stub.ValidateNamespaceAccess(ctx, userInfo, "team-a"))
+	})
+}
+
 func TestAuthorizationServiceStub_ExtractUserInfo(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.FatalLevel)
@@ -178,11 +270,38 @@ func TestAuthorizationServiceStub_ExtractUserInfo(t *testing.T) {
 	assert.Contains(t, userInfo.Groups, "stub-group")
 }
 
+func TestAuthorizationServiceStub_ExtractUserInfoFromCertificate(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	stub := &authorizationServiceStub{cfg: &config.Config{}, logger: logger}
+	ctx := context.Background()
+
+	cert := &x509.Certificate{Subject: pkix.Name{
+		CommonName:         "ci-pipeline",
+		OrganizationalUnit: []string{"platform-admins", "release"},
+	}}
+	userInfo, err := stub.ExtractUserInfoFromCertificate(ctx, cert)
+	require.NoError(t, err)
+	assert.Equal(t, "ci-pipeline", userInfo.Username)
+	assert.Equal(t, []string{"platform-admins", "release"}, userInfo.Groups)
+
+	_, err = stub.ExtractUserInfoFromCertificate(ctx, &x509.Certificate{})
+	assert.Error(t, err)
+
+	_, err = stub.ExtractUserInfoFromCertificate(ctx, nil)
+	assert.Error(t, err)
+}
+
 func TestAuthorizationServiceStub_IsAdminUser(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.FatalLevel)
 
-	cfg := &config.Config{}
+	cfg := &config.Config{
+		Authorization: config.AuthorizationConfig{
+			AdminGroups: []string{"platform-admins"},
+			AdminUsers:  []string{"alice"},
+		},
+	}
 	k8sStub := &kubernetesServiceStub{logger: logger}
 	stub := &authorizationServiceStub{
 		cfg:    cfg,
@@ -190,9 +309,43 @@ func TestAuthorizationServiceStub_IsAdminUser(t *testing.T) {
 		logger: logger,
 	}
 
-	userInfo := &types.UserInfo{Username: "test-user"}
-	isAdmin := stub.IsAdminUser(userInfo)
-	assert.False(t, isAdmin, "Stub should return false for admin user check")
+	tests := []struct {
+		name     string
+		userInfo *types.UserInfo
+		expected bool
+	}{
+		{
+			name:     "User in admin group",
+			userInfo: &types.UserInfo{Username: "bob", Groups: []string{"developers", "platform-admins"}},
+			expected: true,
+		},
+		{
+			name:     "Explicitly listed admin user",
+			userInfo: &types.UserInfo{Username: "alice"},
+			expected: true,
+		},
+		{
+			name:     "Username match is case-sensitive",
+			userInfo: &types.UserInfo{Username: "Alice"},
+			expected: false,
+		},
+		{
+			name:     "Regular user",
+			userInfo: &types.UserInfo{Username: "test-user", Groups: []string{"developers"}},
+			expected: false,
+		},
+		{
+			name:     "Nil user",
+			userInfo: nil,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, stub.IsAdminUser(tt.userInfo))
+		})
+	}
 }
 
 /*
@@ -300,7 +453,7 @@ func TestRegistrationServiceStub_CreateRegistration(t *testing.T) {
 		Namespace: "test-namespace",
 	}
 
-	registration, err := stub.CreateRegistration(ctx, req)
+	registration, err := stub.CreateRegistration(ctx, req, nil)
 	require.NoError(t, err)
 	assert.NotNil(t, registration)
 	assert.Equal(t, "stub-reg-123", registration.ID)
@@ -362,7 +515,8 @@ func TestRegistrationServiceStub_GetRegistration(t *testing.T) {
 	registration, err := stub.GetRegistration(ctx, "non-existent-id")
 	assert.Error(t, err, "Should return error for non-existent registration")
 	assert.Nil(t, registration)
-	assert.Contains(t, err.Error(), "registration not found")
+	var notFound *NotFoundError
+	assert.ErrorAs(t, err, &notFound)
 }
 
 func TestRegistrationServiceStub_ListRegistrations(t *testing.T) {
@@ -602,6 +756,18 @@ func TestKubernetesServiceStub_UntestedMethods(t *testing.T) {
 	})
 }
 
+func TestArgoCDServiceStub_GetApplicationResources(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	stub := &argoCDServiceStub{logger: logger}
+
+	resources, err := stub.GetApplicationResources(context.Background(), "test-app")
+	require.NoError(t, err)
+	assert.NotNil(t, resources)
+	assert.Empty(t, resources)
+}
+
 func TestArgoCDServiceStub_UntestedMethods(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.FatalLevel)
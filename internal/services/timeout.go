@@ -0,0 +1,486 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultRequestTimeout bounds upstream calls when the configured timeout is invalid
+const DefaultRequestTimeout = 10 * time.Second
+
+// UpstreamTimeoutError indicates a Kubernetes or ArgoCD call did not complete within the configured timeout
+type UpstreamTimeoutError struct {
+	Service   string
+	Operation string
+	Timeout   time.Duration
+	Err       error
+}
+
+func (e *UpstreamTimeoutError) Error() string {
+	return fmt.Sprintf("%s %s timed out after %s", e.Service, e.Operation, e.Timeout)
+}
+
+func (e *UpstreamTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// parseRequestTimeout converts a configured request timeout; an empty value disables the timeout
+func parseRequestTimeout(raw string, logger *logrus.Logger) time.Duration {
+	if raw == "" {
+		return 0
+	}
+
+	timeout, err := time.ParseDuration(raw)
+	if err != nil || timeout < 0 {
+		logger.WithField("requestTimeout", raw).Warnf("Invalid request timeout, using default %s", DefaultRequestTimeout)
+		return DefaultRequestTimeout
+	}
+	return timeout
+}
+
+// runWithTimeout calls fn with a context bounded by timeout and reports deadline expiry as an UpstreamTimeoutError
+func runWithTimeout(ctx context.Context, timeout time.Duration, service, operation string, fn func(context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(ctx)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &UpstreamTimeoutError{Service: service, Operation: operation, Timeout: timeout, Err: err}
+	}
+	return err
+}
+
+// callWithTimeout passes calls that also return a value through a wrapper's run method
+func callWithTimeout[T any](ctx context.Context, run func(context.Context, string, func(context.Context) error) error,
+	operation string, fn func(context.Context) (T, error)) (T, error) {
+	var result T
+	err := run(ctx, operation, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		return err
+	})
+	return result, err
+}
+
+// timeoutKubernetesService applies a per-call timeout and the shared upstream concurrency limit to every
+// KubernetesService method
+type timeoutKubernetesService struct {
+	next    KubernetesService
+	timeout time.Duration
+	limiter *upstreamLimiter
+}
+
+// WithKubernetesTimeout wraps a KubernetesService so each call is bounded by timeout; zero disables it
+func WithKubernetesTimeout(next KubernetesService, timeout time.Duration) KubernetesService {
+	return withKubernetesLimits(next, timeout, nil)
+}
+
+// withKubernetesLimits wraps a KubernetesService so each call waits for a slot from limiter and is then
+// bounded by timeout; the service is returned unchanged when neither applies
+func withKubernetesLimits(next KubernetesService, timeout time.Duration, limiter *upstreamLimiter) KubernetesService {
+	if timeout <= 0 && limiter == nil {
+		return next
+	}
+	return &timeoutKubernetesService{next: next, timeout: timeout, limiter: limiter}
+}
+
+func (t *timeoutKubernetesService) run(ctx context.Context, operation string, fn func(context.Context) error) error {
+	release, err := t.limiter.acquire(ctx, "kubernetes", operation)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return runWithTimeout(ctx, t.timeout, "kubernetes", operation, fn)
+}
+
+func (t *timeoutKubernetesService) HealthCheck(ctx context.Context) error {
+	return t.run(ctx, "HealthCheck", t.next.HealthCheck)
+}
+
+func (t *timeoutKubernetesService) CreateNamespace(ctx context.Context, name string, labels map[string]string) error {
+	return t.run(ctx, "CreateNamespace", func(ctx context.Context) error {
+		return t.next.CreateNamespace(ctx, name, labels)
+	})
+}
+
+func (t *timeoutKubernetesService) CreateNamespaceWithMetadata(ctx context.Context, name string, labels, annotations map[string]string) error {
+	return t.run(ctx, "CreateNamespaceWithMetadata", func(ctx context.Context) error {
+		return t.next.CreateNamespaceWithMetadata(ctx, name, labels, annotations)
+	})
+}
+
+func (t *timeoutKubernetesService) UpdateNamespaceLabels(ctx context.Context, name string, labels map[string]string) error {
+	return t.run(ctx, "UpdateNamespaceLabels", func(ctx context.Context) error {
+		return t.next.UpdateNamespaceLabels(ctx, name, labels)
+	})
+}
+
+func (t *timeoutKubernetesService) UpdateNamespaceMetadata(ctx context.Context, name string, labels, annotations map[string]string) error {
+	return t.run(ctx, "UpdateNamespaceMetadata", func(ctx context.Context) error {
+		return t.next.UpdateNamespaceMetadata(ctx, name, labels, annotations)
+	})
+}
+
+func (t *timeoutKubernetesService) ClaimNamespace(ctx context.Context, name string, labels, annotations map[string]string) error {
+	return t.run(ctx, "ClaimNamespace", func(ctx context.Context) error {
+		return t.next.ClaimNamespace(ctx, name, labels, annotations)
+	})
+}
+
+func (t *timeoutKubernetesService) UnclaimNamespace(ctx context.Context, name string, claimed, original NamespaceMetadata) error {
+	return t.run(ctx, "UnclaimNamespace", func(ctx context.Context) error {
+		return t.next.UnclaimNamespace(ctx, name, claimed, original)
+	})
+}
+
+func (t *timeoutKubernetesService) DeleteNamespace(ctx context.Context, name string) error {
+	return t.run(ctx, "DeleteNamespace", func(ctx context.Context) error {
+		return t.next.DeleteNamespace(ctx, name)
+	})
+}
+
+func (t *timeoutKubernetesService) AddNamespaceFinalizer(ctx context.Context, name string) error {
+	return t.run(ctx, "AddNamespaceFinalizer", func(ctx context.Context) error {
+		return t.next.AddNamespaceFinalizer(ctx, name)
+	})
+}
+
+func (t *timeoutKubernetesService) RemoveNamespaceFinalizer(ctx context.Context, name string) error {
+	return t.run(ctx, "RemoveNamespaceFinalizer", func(ctx context.Context) error {
+		return t.next.RemoveNamespaceFinalizer(ctx, name)
+	})
+}
+
+func (t *timeoutKubernetesService) NamespaceExists(ctx context.Context, name string) (bool, error) {
+	return callWithTimeout(ctx, t.run, "NamespaceExists", func(ctx context.Context) (bool, error) {
+		return t.next.NamespaceExists(ctx, name)
+	})
+}
+
+func (t *timeoutKubernetesService) GetNamespaceUID(ctx context.Context, name string) (string, error) {
+	return callWithTimeout(ctx, t.run, "GetNamespaceUID", func(ctx context.Context) (string, error) {
+		return t.next.GetNamespaceUID(ctx, name)
+	})
+}
+
+func (t *timeoutKubernetesService) GetNamespaceMetadata(ctx context.Context, name string) (*NamespaceInfo, error) {
+	return callWithTimeout(ctx, t.run, "GetNamespaceMetadata", func(ctx context.Context) (*NamespaceInfo, error) {
+		return t.next.GetNamespaceMetadata(ctx, name)
+	})
+}
+
+func (t *timeoutKubernetesService) CountNamespaces(ctx context.Context) (int, error) {
+	return callWithTimeout(ctx, t.run, "CountNamespaces", t.next.CountNamespaces)
+}
+
+func (t *timeoutKubernetesService) CountManagedNamespaces(ctx context.Context) (int, error) {
+	return callWithTimeout(ctx, t.run, "CountManagedNamespaces", t.next.CountManagedNamespaces)
+}
+
+func (t *timeoutKubernetesService) ListManagedNamespaces(ctx context.Context) ([]string, error) {
+	return callWithTimeout(ctx, t.run, "ListManagedNamespaces", t.next.ListManagedNamespaces)
+}
+
+func (t *timeoutKubernetesService) ListTerminatingNamespaces(ctx context.Context) ([]string, error) {
+	return callWithTimeout(ctx, t.run, "ListTerminatingNamespaces", t.next.ListTerminatingNamespaces)
+}
+
+func (t *timeoutKubernetesService) CountNamespacesByOwner(ctx context.Context, owner string) (int, error) {
+	return callWithTimeout(ctx, t.run, "CountNamespacesByOwner", func(ctx context.Context) (int, error) {
+		return t.next.CountNamespacesByOwner(ctx, owner)
+	})
+}
+
+func (t *timeoutKubernetesService) ListManagedNamespaceInfo(ctx context.Context, selector string) ([]NamespaceInfo, error) {
+	return callWithTimeout(ctx, t.run, "ListManagedNamespaceInfo", func(ctx context.Context) ([]NamespaceInfo, error) {
+		return t.next.ListManagedNamespaceInfo(ctx, selector)
+	})
+}
+
+func (t *timeoutKubernetesService) ListEvents(ctx context.Context, namespace string) ([]types.RegistrationEvent, error) {
+	return callWithTimeout(ctx, t.run, "ListEvents", func(ctx context.Context) ([]types.RegistrationEvent, error) {
+		return t.next.ListEvents(ctx, namespace)
+	})
+}
+
+func (t *timeoutKubernetesService) CreateServiceAccount(ctx context.Context, namespace, name string) error {
+	return t.run(ctx, "CreateServiceAccount", func(ctx context.Context) error {
+		return t.next.CreateServiceAccount(ctx, namespace, name)
+	})
+}
+
+func (t *timeoutKubernetesService) CreateRoleBinding(ctx context.Context, namespace, name, role, serviceAccount string) error {
+	return t.run(ctx, "CreateRoleBinding", func(ctx context.Context) error {
+		return t.next.CreateRoleBinding(ctx, namespace, name, role, serviceAccount)
+	})
+}
+
+func (t *timeoutKubernetesService) CreateLimitRange(ctx context.Context, namespace string, spec *types.LimitRangeSpec) error {
+	return t.run(ctx, "CreateLimitRange", func(ctx context.Context) error {
+		return t.next.CreateLimitRange(ctx, namespace, spec)
+	})
+}
+
+func (t *timeoutKubernetesService) DeleteServiceAccount(ctx context.Context, namespace, name string) error {
+	return t.run(ctx, "DeleteServiceAccount", func(ctx context.Context) error {
+		return t.next.DeleteServiceAccount(ctx, namespace, name)
+	})
+}
+
+func (t *timeoutKubernetesService) DeleteRoleBinding(ctx context.Context, namespace, name string) error {
+	return t.run(ctx, "DeleteRoleBinding", func(ctx context.Context) error {
+		return t.next.DeleteRoleBinding(ctx, namespace, name)
+	})
+}
+
+func (t *timeoutKubernetesService) ServiceAccountExists(ctx context.Context, namespace, name string) (bool, error) {
+	return callWithTimeout(ctx, t.run, "ServiceAccountExists", func(ctx context.Context) (bool, error) {
+		return t.next.ServiceAccountExists(ctx, namespace, name)
+	})
+}
+
+func (t *timeoutKubernetesService) RoleBindingExists(ctx context.Context, namespace, name string) (bool, error) {
+	return callWithTimeout(ctx, t.run, "RoleBindingExists", func(ctx context.Context) (bool, error) {
+		return t.next.RoleBindingExists(ctx, namespace, name)
+	})
+}
+
+func (t *timeoutKubernetesService) ValidateClusterRole(ctx context.Context, name string) (*ClusterRoleValidation, error) {
+	return callWithTimeout(ctx, t.run, "ValidateClusterRole", func(ctx context.Context) (*ClusterRoleValidation, error) {
+		return t.next.ValidateClusterRole(ctx, name)
+	})
+}
+
+func (t *timeoutKubernetesService) HasNamespaceRole(
+	ctx context.Context, userInfo *types.UserInfo, namespace, clusterRole string,
+) (bool, error) {
+	return callWithTimeout(ctx, t.run, "HasNamespaceRole", func(ctx context.Context) (bool, error) {
+		return t.next.HasNamespaceRole(ctx, userInfo, namespace, clusterRole)
+	})
+}
+
+func (t *timeoutKubernetesService) ResourceKindExists(ctx context.Context, group, kind string) (bool, error) {
+	return callWithTimeout(ctx, t.run, "ResourceKindExists", func(ctx context.Context) (bool, error) {
+		return t.next.ResourceKindExists(ctx, group, kind)
+	})
+}
+
+func (t *timeoutKubernetesService) CreateServiceAccountWithGenerateName(ctx context.Context, namespace, baseName string) (string, error) {
+	return callWithTimeout(ctx, t.run, "CreateServiceAccountWithGenerateName", func(ctx context.Context) (string, error) {
+		return t.next.CreateServiceAccountWithGenerateName(ctx, namespace, baseName)
+	})
+}
+
+func (t *timeoutKubernetesService) CreateRoleBindingForServiceAccount(ctx context.Context, namespace, name, clusterRole, serviceAccountName string) error {
+	return t.run(ctx, "CreateRoleBindingForServiceAccount", func(ctx context.Context) error {
+		return t.next.CreateRoleBindingForServiceAccount(ctx, namespace, name, clusterRole, serviceAccountName)
+	})
+}
+
+func (t *timeoutKubernetesService) CheckAppProjectConflict(ctx context.Context, repositoryHash string) (bool, error) {
+	return callWithTimeout(ctx, t.run, "CheckAppProjectConflict", func(ctx context.Context) (bool, error) {
+		return t.next.CheckAppProjectConflict(ctx, repositoryHash)
+	})
+}
+
+// timeoutArgoCDService applies a per-call timeout and the shared upstream concurrency limit to every
+// ArgoCDService method
+type timeoutArgoCDService struct {
+	next    ArgoCDService
+	timeout time.Duration
+	limiter *upstreamLimiter
+}
+
+// WithArgoCDTimeout wraps an ArgoCDService so each call is bounded by timeout; zero disables it
+func WithArgoCDTimeout(next ArgoCDService, timeout time.Duration) ArgoCDService {
+	return withArgoCDLimits(next, timeout, nil)
+}
+
+// withArgoCDLimits wraps an ArgoCDService so each call waits for a slot from limiter and is then
+// bounded by timeout; the service is returned unchanged when neither applies
+func withArgoCDLimits(next ArgoCDService, timeout time.Duration, limiter *upstreamLimiter) ArgoCDService {
+	if timeout <= 0 && limiter == nil {
+		return next
+	}
+	return &timeoutArgoCDService{next: next, timeout: timeout, limiter: limiter}
+}
+
+func (t *timeoutArgoCDService) run(ctx context.Context, operation string, fn func(context.Context) error) error {
+	release, err := t.limiter.acquire(ctx, "argocd", operation)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return runWithTimeout(ctx, t.timeout, "argocd", operation, fn)
+}
+
+func (t *timeoutArgoCDService) HealthCheck(ctx context.Context) error {
+	return t.run(ctx, "HealthCheck", t.next.HealthCheck)
+}
+
+func (t *timeoutArgoCDService) CreateAppProject(ctx context.Context, project *types.AppProject) error {
+	return t.run(ctx, "CreateAppProject", func(ctx context.Context) error {
+		return t.next.CreateAppProject(ctx, project)
+	})
+}
+
+func (t *timeoutArgoCDService) DeleteAppProject(ctx context.Context, name string) error {
+	return t.run(ctx, "DeleteAppProject", func(ctx context.Context) error {
+		return t.next.DeleteAppProject(ctx, name)
+	})
+}
+
+func (t *timeoutArgoCDService) AppProjectExists(ctx context.Context, name string) (bool, error) {
+	return callWithTimeout(ctx, t.run, "AppProjectExists", func(ctx context.Context) (bool, error) {
+		return t.next.AppProjectExists(ctx, name)
+	})
+}
+
+func (t *timeoutArgoCDService) AdoptAppProject(ctx context.Context, project *types.AppProject) error {
+	return t.run(ctx, "AdoptAppProject", func(ctx context.Context) error {
+		return t.next.AdoptAppProject(ctx, project)
+	})
+}
+
+func (t *timeoutArgoCDService) CreateApplication(ctx context.Context, app *types.Application) error {
+	return t.run(ctx, "CreateApplication", func(ctx context.Context) error {
+		return t.next.CreateApplication(ctx, app)
+	})
+}
+
+func (t *timeoutArgoCDService) CreateApplicationSet(ctx context.Context, appSet *types.ApplicationSet) error {
+	return t.run(ctx, "CreateApplicationSet", func(ctx context.Context) error {
+		return t.next.CreateApplicationSet(ctx, appSet)
+	})
+}
+
+func (t *timeoutArgoCDService) DeleteApplication(ctx context.Context, name string) error {
+	return t.run(ctx, "DeleteApplication", func(ctx context.Context) error {
+		return t.next.DeleteApplication(ctx, name)
+	})
+}
+
+func (t *timeoutArgoCDService) DeleteApplicationSet(ctx context.Context, name string) error {
+	return t.run(ctx, "DeleteApplicationSet", func(ctx context.Context) error {
+		return t.next.DeleteApplicationSet(ctx, name)
+	})
+}
+
+func (t *timeoutArgoCDService) UpdateApplicationSource(ctx context.Context, name string, source *types.ApplicationSource) error {
+	return t.run(ctx, "UpdateApplicationSource", func(ctx context.Context) error {
+		return t.next.UpdateApplicationSource(ctx, name, source)
+	})
+}
+
+func (t *timeoutArgoCDService) ApplicationExists(ctx context.Context, name string) (bool, error) {
+	return callWithTimeout(ctx, t.run, "ApplicationExists", func(ctx context.Context) (bool, error) {
+		return t.next.ApplicationExists(ctx, name)
+	})
+}
+
+func (t *timeoutArgoCDService) ApplicationSetExists(ctx context.Context, name string) (bool, error) {
+	return callWithTimeout(ctx, t.run, "ApplicationSetExists", func(ctx context.Context) (bool, error) {
+		return t.next.ApplicationSetExists(ctx, name)
+	})
+}
+
+func (t *timeoutArgoCDService) GetApplicationStatus(ctx context.Context, name string) (*types.ApplicationStatus, error) {
+	return callWithTimeout(ctx, t.run, "GetApplicationStatus", func(ctx context.Context) (*types.ApplicationStatus, error) {
+		return t.next.GetApplicationStatus(ctx, name)
+	})
+}
+
+func (t *timeoutArgoCDService) ListApplicationStatuses(ctx context.Context) (map[string]*types.ApplicationStatus, error) {
+	return callWithTimeout(ctx, t.run, "ListApplicationStatuses", t.next.ListApplicationStatuses)
+}
+
+func (t *timeoutArgoCDService) CheckAppProjectConflict(ctx context.Context, repositoryHash string) (bool, error) {
+	return callWithTimeout(ctx, t.run, "CheckAppProjectConflict", func(ctx context.Context) (bool, error) {
+		return t.next.CheckAppProjectConflict(ctx, repositoryHash)
+	})
+}
+
+func (t *timeoutArgoCDService) FindAppProjectByRepoHash(ctx context.Context, repositoryHash string) (*types.AppProject, error) {
+	return callWithTimeout(ctx, t.run, "FindAppProjectByRepoHash", func(ctx context.Context) (*types.AppProject, error) {
+		return t.next.FindAppProjectByRepoHash(ctx, repositoryHash)
+	})
+}
+
+func (t *timeoutArgoCDService) DeleteAppProjectByRepoHash(ctx context.Context, repositoryHash string) (*types.AppProject, error) {
+	return callWithTimeout(ctx, t.run, "DeleteAppProjectByRepoHash", func(ctx context.Context) (*types.AppProject, error) {
+		return t.next.DeleteAppProjectByRepoHash(ctx, repositoryHash)
+	})
+}
+
+func (t *timeoutArgoCDService) RefreshApplication(ctx context.Context, name string, hard bool) error {
+	return t.run(ctx, "RefreshApplication", func(ctx context.Context) error {
+		return t.next.RefreshApplication(ctx, name, hard)
+	})
+}
+
+func (t *timeoutArgoCDService) SuspendApplication(ctx context.Context, name string) error {
+	return t.run(ctx, "SuspendApplication", func(ctx context.Context) error {
+		return t.next.SuspendApplication(ctx, name)
+	})
+}
+
+func (t *timeoutArgoCDService) SuspendApplicationSet(ctx context.Context, name string) error {
+	return t.run(ctx, "SuspendApplicationSet", func(ctx context.Context) error {
+		return t.next.SuspendApplicationSet(ctx, name)
+	})
+}
+
+func (t *timeoutArgoCDService) ResumeApplication(ctx context.Context, name string, automated *types.ApplicationSyncPolicyAutomated) error {
+	return t.run(ctx, "ResumeApplication", func(ctx context.Context) error {
+		return t.next.ResumeApplication(ctx, name, automated)
+	})
+}
+
+func (t *timeoutArgoCDService) ResumeApplicationSet(ctx context.Context, name string, automated *types.ApplicationSyncPolicyAutomated) error {
+	return t.run(ctx, "ResumeApplicationSet", func(ctx context.Context) error {
+		return t.next.ResumeApplicationSet(ctx, name, automated)
+	})
+}
+
+func (t *timeoutArgoCDService) GetApplicationResources(ctx context.Context, name string) ([]types.ApplicationResource, error) {
+	return callWithTimeout(ctx, t.run, "GetApplicationResources", func(ctx context.Context) ([]types.ApplicationResource, error) {
+		return t.next.GetApplicationResources(ctx, name)
+	})
+}
+
+func (t *timeoutArgoCDService) GetApplicationHistory(ctx context.Context, name string) ([]types.ApplicationHistoryEntry, error) {
+	return callWithTimeout(ctx, t.run, "GetApplicationHistory", func(ctx context.Context) ([]types.ApplicationHistoryEntry, error) {
+		return t.next.GetApplicationHistory(ctx, name)
+	})
+}
+
+func (t *timeoutArgoCDService) EnsureRepoHashLabel(ctx context.Context, name, repoURL string) (bool, error) {
+	return callWithTimeout(ctx, t.run, "EnsureRepoHashLabel", func(ctx context.Context) (bool, error) {
+		return t.next.EnsureRepoHashLabel(ctx, name, repoURL)
+	})
+}
+
+func (t *timeoutArgoCDService) ResolveClusterServer(ctx context.Context, name string) (string, error) {
+	return callWithTimeout(ctx, t.run, "ResolveClusterServer", func(ctx context.Context) (string, error) {
+		return t.next.ResolveClusterServer(ctx, name)
+	})
+}
+
+func (t *timeoutArgoCDService) TestRepositoryConnection(ctx context.Context, repoURL, credsSecret string) error {
+	return t.run(ctx, "TestRepositoryConnection", func(ctx context.Context) error {
+		return t.next.TestRepositoryConnection(ctx, repoURL, credsSecret)
+	})
+}
+
+func (t *timeoutArgoCDService) ListManagedAppProjects(ctx context.Context) ([]types.AppProject, error) {
+	return callWithTimeout(ctx, t.run, "ListManagedAppProjects", t.next.ListManagedAppProjects)
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// blockUntilDone simulates an upstream call that hangs until its context is cancelled
+func blockUntilDone(args mock.Arguments) {
+	<-args.Get(0).(context.Context).Done()
+}
+
+func TestRunWithTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Blocked call times out", func(t *testing.T) {
+		err := runWithTimeout(ctx, 10*time.Millisecond, "kubernetes", "CreateNamespace", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		var timeoutErr *UpstreamTimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		assert.Equal(t, "kubernetes", timeoutErr.Service)
+		assert.Equal(t, "CreateNamespace", timeoutErr.Operation)
+		assert.Equal(t, "kubernetes CreateNamespace timed out after 10ms", err.Error())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Other errors pass through", func(t *testing.T) {
+		expected := errors.New("forbidden")
+		err := runWithTimeout(ctx, time.Second, "argocd", "CreateApplication", func(ctx context.Context) error {
+			return expected
+		})
+		assert.Equal(t, expected, err)
+	})
+
+	t.Run("Zero timeout leaves the context unbounded", func(t *testing.T) {
+		err := runWithTimeout(ctx, 0, "argocd", "CreateApplication", func(ctx context.Context) error {
+			_, hasDeadline := ctx.Deadline()
+			assert.False(t, hasDeadline)
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+}
+
+func TestParseRequestTimeout(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	assert.Equal(t, time.Duration(0), parseRequestTimeout("", logger))
+	assert.Equal(t, 5*time.Second, parseRequestTimeout("5s", logger))
+	assert.Equal(t, DefaultRequestTimeout, parseRequestTimeout("soon", logger))
+	assert.Equal(t, DefaultRequestTimeout, parseRequestTimeout("-1s", logger))
+}
+
+func TestWithKubernetesTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Zero timeout returns the service unchanged", func(t *testing.T) {
+		mockK8s := &MockKubernetesService{}
+		assert.Same(t, mockK8s, WithKubernetesTimeout(mockK8s, 0))
+	})
+
+	t.Run("Blocked call returns UpstreamTimeoutError", func(t *testing.T) {
+		mockK8s := &MockKubernetesService{}
+		mockK8s.On("NamespaceExists", mock.Anything, "tenant-a").Run(blockUntilDone).Return(false, context.DeadlineExceeded)
+
+		service := WithKubernetesTimeout(mockK8s, 10*time.Millisecond)
+		_, err := service.NamespaceExists(ctx, "tenant-a")
+
+		var timeoutErr *UpstreamTimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		assert.Equal(t, "kubernetes", timeoutErr.Service)
+		assert.Equal(t, "NamespaceExists", timeoutErr.Operation)
+	})
+
+	t.Run("Successful call passes results through", func(t *testing.T) {
+		mockK8s := &MockKubernetesService{}
+		mockK8s.On("ListManagedNamespaces", mock.Anything).Return([]string{"tenant-a"}, nil)
+
+		service := WithKubernetesTimeout(mockK8s, time.Second)
+		namespaces, err := service.ListManagedNamespaces(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tenant-a"}, namespaces)
+	})
+}
+
+func TestWithArgoCDTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	mockArgoCD := &MockArgoCDService{}
+	mockArgoCD.On("CreateApplication", mock.Anything, mock.AnythingOfType("*types.Application")).
+		Run(blockUntilDone).Return(context.DeadlineExceeded)
+
+	service := WithArgoCDTimeout(mockArgoCD, 10*time.Millisecond)
+	err := service.CreateApplication(ctx, &types.Application{Name: "tenant-a-app"})
+
+	var timeoutErr *UpstreamTimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	assert.Equal(t, "argocd", timeoutErr.Service)
+	assert.Equal(t, "CreateApplication", timeoutErr.Operation)
+}
+
+func TestKubernetesService_RequestTimeout_BlockingAPIServer(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	// API server that never answers until the client gives up
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer apiServer.Close()
+
+	restConfig := &rest.Config{Host: apiServer.URL}
+	client, err := kubernetes.NewForConfig(restConfig)
+	require.NoError(t, err)
+
+	cfg := &config.Config{
+		Kubernetes: config.KubernetesConfig{RequestTimeout: "50ms"},
+	}
+	factory := &TestKubernetesFactory{Client: client, Config: restConfig}
+	argoCDFactory := &TestArgoCDFactory{}
+
+	svc, err := NewWithFactories(cfg, logger, factory, argoCDFactory)
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = svc.Kubernetes.NamespaceExists(context.Background(), "tenant-a")
+
+	var timeoutErr *UpstreamTimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	assert.Equal(t, "NamespaceExists", timeoutErr.Operation)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
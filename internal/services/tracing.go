@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName identifies spans emitted by the services package
+const TracerName = "github.com/konflux-ci/gitops-registration-service/internal/services"
+
+// Span attribute keys for registration spans
+const (
+	RegistrationIDAttribute = attribute.Key("gitops.registration.id")
+	NamespaceAttribute      = attribute.Key("gitops.namespace")
+)
+
+// startSpan starts a span tagged with the registration ID and namespace.
+// The global tracer provider is a no-op unless tracing is enabled.
+func startSpan(ctx context.Context, name, registrationID, namespace string) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(
+		RegistrationIDAttribute.String(registrationID),
+		NamespaceAttribute.String(namespace),
+	))
+}
+
+// traceStep runs a registration pipeline step inside its own span, recording any error
+func (r *registrationService) traceStep(
+	ctx context.Context, name, registrationID, namespace string, step func(ctx context.Context) error,
+) error {
+	ctx, span := startSpan(ctx, name, registrationID, namespace)
+	defer span.End()
+
+	if err := step(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRegistrationService_CreateRegistration_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	original := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(original)
+
+	service, mockK8s, mockArgoCD := setupRegistrationService(t)
+
+	req := &types.RegistrationRequest{
+		Namespace: "traced-namespace",
+		Repository: types.Repository{
+			URL:    "https://github.com/test/repo",
+			Branch: "main",
+		},
+	}
+
+	mockK8s.On("GetNamespaceMetadata", mock.Anything, req.Namespace).
+		Return((*NamespaceInfo)(nil), errors.New("namespace not found"))
+	mockK8s.On("NamespaceExists", mock.Anything, req.Namespace).Return(false, nil)
+	mockK8s.On("CreateNamespaceWithMetadata", mock.Anything, req.Namespace,
+		mock.AnythingOfType("map[string]string"), mock.AnythingOfType("map[string]string")).Return(nil)
+	mockK8s.On("AddNamespaceFinalizer", mock.Anything, req.Namespace).Return(nil)
+	mockK8s.On("GetNamespaceUID", mock.Anything, req.Namespace).Return("ns-uid", nil)
+	mockK8s.On("CreateServiceAccount", mock.Anything, req.Namespace, mock.AnythingOfType("string")).Return(nil)
+	mockK8s.On("CreateRoleBinding", mock.Anything, req.Namespace, mock.AnythingOfType("string"),
+		mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+	mockArgoCD.On("ApplicationExists", mock.Anything, "traced-namespace-app").Return(false, nil)
+	mockArgoCD.On("CreateAppProject", mock.Anything, mock.AnythingOfType("*types.AppProject")).Return(nil)
+	mockArgoCD.On("CreateApplication", mock.Anything, mock.AnythingOfType("*types.Application")).Return(nil)
+
+	registration, err := service.CreateRegistration(context.Background(), req, nil)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	names := make([]string, 0, len(spans))
+	for _, span := range spans {
+		names = append(names, span.Name())
+
+		attrs := map[string]string{}
+		for _, attr := range span.Attributes() {
+			attrs[string(attr.Key)] = attr.Value.AsString()
+		}
+		assert.Equal(t, registration.ID, attrs[string(RegistrationIDAttribute)], span.Name())
+		assert.Equal(t, req.Namespace, attrs[string(NamespaceAttribute)], span.Name())
+	}
+
+	assert.Equal(t, []string{
+		"checkRepositoryConflicts",
+		"setupNamespace",
+		"setupServiceAccount",
+		"setupArgoCDResources",
+		"CreateRegistration",
+	}, names)
+
+	// Step spans are children of the CreateRegistration span
+	root := spans[len(spans)-1]
+	for _, span := range spans[:len(spans)-1] {
+		assert.Equal(t, root.SpanContext().SpanID(), span.Parent().SpanID(), span.Name())
+	}
+}
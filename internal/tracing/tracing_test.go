@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/konflux-ci/gitops-registration-service/internal/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSetup_Disabled(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	original := otel.GetTracerProvider()
+
+	shutdown, err := Setup(context.Background(), config.TracingConfig{Enabled: false}, logger)
+	require.NoError(t, err)
+	require.NotNil(t, shutdown)
+	assert.NoError(t, shutdown(context.Background()))
+
+	// The global provider is left untouched
+	assert.Equal(t, original, otel.GetTracerProvider())
+}
+
+func TestSetup_Enabled(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	original := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(original)
+
+	// TLS is the default; insecure export must be requested explicitly
+	for _, insecure := range []bool{false, true} {
+		shutdown, err := Setup(context.Background(), config.TracingConfig{
+			Enabled:      true,
+			OTLPEndpoint: "localhost:4318",
+			Insecure:     insecure,
+		}, logger)
+		require.NoError(t, err)
+		require.NotNil(t, shutdown)
+
+		_, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+		assert.True(t, ok, "an SDK tracer provider should be installed")
+
+		// No spans were recorded, so shutdown does not need a reachable collector
+		assert.NoError(t, shutdown(context.Background()))
+	}
+}
@@ -9,17 +9,23 @@ type Registration struct {
 	ID          string             `json:"id"`
 	Repository  Repository         `json:"repository"`
 	Namespace   string             `json:"namespace"`
+	Owner       string             `json:"owner,omitempty"`    // Username of the user who registered the tenant
+	Team        string             `json:"team,omitempty"`     // Owning team, recorded as the gitops.io/team label
+	Metadata    map[string]string  `json:"metadata,omitempty"` // Free-form tags, recorded as gitops.io/meta-<key> annotations
 	Status      RegistrationStatus `json:"status"`
 	CreatedAt   time.Time          `json:"createdAt"`
 	UpdatedAt   time.Time          `json:"updatedAt"`
 	Labels      map[string]string  `json:"labels,omitempty"`
 	Annotations map[string]string  `json:"annotations,omitempty"`
+	SelfLink    string             `json:"selfLink,omitempty"` // API path of the registration, set on create responses
 }
 
 // Repository represents a Git repository configuration
 type Repository struct {
 	URL         string      `json:"url"`
 	Branch      string      `json:"branch"`
+	Revision    string      `json:"revision,omitempty"` // Branch, tag or commit SHA to deploy, takes precedence over Branch
+	Path        string      `json:"path,omitempty"`     // Directory holding the manifests, defaults to manifests
 	Credentials Credentials `json:"credentials,omitempty"`
 }
 
@@ -31,7 +37,7 @@ type Credentials struct {
 
 // RegistrationStatus represents the status of a registration
 type RegistrationStatus struct {
-	Phase              string    `json:"phase"` // pending, active, failed, deleting
+	Phase              string    `json:"phase"` // pending, active, failed, deleting, archived
 	Message            string    `json:"message,omitempty"`
 	ArgoCDApplication  string    `json:"argocdApplication,omitempty"`
 	ArgoCDAppProject   string    `json:"argocdAppProject,omitempty"`
@@ -43,8 +49,77 @@ type RegistrationStatus struct {
 
 // RegistrationRequest represents a request to register a new GitOps repository
 type RegistrationRequest struct {
-	Repository Repository `json:"repository"`
-	Namespace  string     `json:"namespace"`
+	Repository         Repository `json:"repository"`
+	Namespace          string     `json:"namespace"`
+	DestinationCluster string     `json:"destinationCluster,omitempty"` // API server URL or ArgoCD cluster name, defaults to in-cluster
+	// Extra repositories the tenant's AppProject may source from, e.g. a shared base
+	AdditionalSourceRepos []string `json:"additionalSourceRepos,omitempty"`
+	// Sync windows for the tenant's AppProject, replacing argocd.defaultSyncWindows when set
+	SyncWindows []SyncWindow `json:"syncWindows,omitempty"`
+	// Owning team, written as the gitops.io/team label on the namespace and AppProject
+	Team string `json:"team,omitempty"`
+	// Further restrict the resources the tenant's AppProject allows; at most one list may be set.
+	// The allow list must stay within security.resourceAllowList when that is configured.
+	ResourceAllowList []AppProjectResource `json:"resourceAllowList,omitempty"`
+	ResourceDenyList  []AppProjectResource `json:"resourceDenyList,omitempty"`
+	// Set to false to provision only the AppProject and RBAC and create Applications yourself.
+	// Ignored when registration.manageApplication is disabled, as no Application is created then.
+	CreateApplication *bool `json:"createApplication,omitempty"`
+	// Tool-specific overrides for the generated Application source; at most one block may be set
+	Helm      *ApplicationSourceHelm      `json:"helm,omitempty"`
+	Kustomize *ApplicationSourceKustomize `json:"kustomize,omitempty"`
+	// ArgoCD notifications subscriptions for the tenant's Application, replacing argocd.defaultNotifications when set
+	Notifications []NotificationSubscription `json:"notifications,omitempty"`
+	// Service account the tenant manages in the namespace, e.g. one created by a policy engine when the namespace
+	// appears. It must exist once the namespace is created; only its role binding is created.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// Free-form tags such as environment=staging, written as gitops.io/meta-<key> namespace annotations and
+	// matched by the meta.<key> filters of the registration list
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Extra annotations for the new namespace. Keys must match tenants.allowedAnnotationPrefixes and may not
+	// use the reserved gitops.io/ prefix; they override tenants.defaultNamespaceAnnotations.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// NotificationSubscription subscribes recipients to an ArgoCD notifications trigger through a service,
+// e.g. the on-health-degraded trigger through slack to a channel
+type NotificationSubscription struct {
+	Trigger    string   `json:"trigger"`
+	Service    string   `json:"service"`
+	Recipients []string `json:"recipients,omitempty"`
+}
+
+// RegistrationUpdateRequest represents a request to repoint a registration's Application source
+type RegistrationUpdateRequest struct {
+	Branch        string `json:"branch,omitempty"`
+	Path          string `json:"path,omitempty"`
+	RepositoryURL string `json:"repositoryURL,omitempty"` // Must match the registered repository if set
+}
+
+// BatchRegistrationRequest represents a request to register several new namespaces at once
+type BatchRegistrationRequest struct {
+	Registrations []RegistrationRequest `json:"registrations"`
+}
+
+// BatchRegistrationResponse reports the outcome of each item in a batch, in request order
+type BatchRegistrationResponse struct {
+	Results []BatchRegistrationResult `json:"results"`
+}
+
+// BatchRegistrationResult reports the outcome of a single batch item
+type BatchRegistrationResult struct {
+	Index        int            `json:"index"`
+	Namespace    string         `json:"namespace"`
+	Status       int            `json:"status"` // HTTP status the item would have received on its own
+	Registration *Registration  `json:"registration,omitempty"`
+	Error        *ErrorResponse `json:"error,omitempty"`
+}
+
+// RepositoryHashCollision reports a repository hash label value shared by AppProjects of different repositories
+type RepositoryHashCollision struct {
+	Hash         string   `json:"hash"`
+	Repositories []string `json:"repositories"`
+	AppProjects  []string `json:"appProjects"`
 }
 
 // ExistingNamespaceRequest represents a request to register an existing namespace
@@ -74,6 +149,24 @@ type AppProject struct {
 	NamespaceResourceWhitelist []AppProjectResource                  `json:"namespaceResourceWhitelist,omitempty"`
 	ClusterResourceBlacklist   []AppProjectResource                  `json:"clusterResourceBlacklist,omitempty"`
 	NamespaceResourceBlacklist []AppProjectResource                  `json:"namespaceResourceBlacklist,omitempty"`
+	OwnerReferences            []OwnerReference                      `json:"ownerReferences,omitempty"`
+	SyncWindows                []SyncWindow                          `json:"syncWindows,omitempty"`
+}
+
+// SyncWindow restricts when ArgoCD may sync the Applications of an AppProject
+type SyncWindow struct {
+	Kind         string   `json:"kind"`     // allow or deny
+	Schedule     string   `json:"schedule"` // Cron expression for the window start
+	Duration     string   `json:"duration"`
+	Applications []string `json:"applications,omitempty"` // Application name globs, all when empty
+}
+
+// OwnerReference identifies the Kubernetes object that owns a generated ArgoCD resource
+type OwnerReference struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	UID        string `json:"uid"`
 }
 
 // AppProjectDestination represents allowed destinations for an AppProject
@@ -95,6 +188,12 @@ type AppProjectRole struct {
 	Policies []string `json:"policies"`
 }
 
+// LimitRangeSpec holds the default container resources of a tenant LimitRange, keyed by resource name
+type LimitRangeSpec struct {
+	Default        map[string]string `json:"default,omitempty"`        // Limits for containers that set none
+	DefaultRequest map[string]string `json:"defaultRequest,omitempty"` // Requests for containers that set none
+}
+
 // AppProjectResource represents allowed resources for an AppProject
 type AppProjectResource struct {
 	Group string `json:"group"`
@@ -103,19 +202,60 @@ type AppProjectResource struct {
 
 // Application represents an ArgoCD Application configuration
 type Application struct {
-	Name        string                 `json:"name"`
-	Namespace   string                 `json:"namespace"`
-	Project     string                 `json:"project"`
-	Source      ApplicationSource      `json:"source"`
-	Destination ApplicationDestination `json:"destination"`
-	SyncPolicy  ApplicationSyncPolicy  `json:"syncPolicy,omitempty"`
+	Name            string                 `json:"name"`
+	Namespace       string                 `json:"namespace"`
+	Project         string                 `json:"project"`
+	Source          ApplicationSource      `json:"source"`
+	Destination     ApplicationDestination `json:"destination"`
+	SyncPolicy      ApplicationSyncPolicy  `json:"syncPolicy,omitempty"`
+	OwnerReferences []OwnerReference       `json:"ownerReferences,omitempty"`
+	// Rendered as notifications.argoproj.io/subscribe.* annotations
+	Notifications []NotificationSubscription `json:"notifications,omitempty"`
+}
+
+// ApplicationSet represents an ArgoCD ApplicationSet that generates one Application per repository directory
+type ApplicationSet struct {
+	Name            string                 `json:"name"`
+	Namespace       string                 `json:"namespace"`
+	Project         string                 `json:"project"`
+	RepoURL         string                 `json:"repoURL"`
+	TargetRevision  string                 `json:"targetRevision"`
+	DirectoryPath   string                 `json:"directoryPath"` // Glob matched by the git directory generator
+	Destination     ApplicationDestination `json:"destination"`
+	SyncPolicy      ApplicationSyncPolicy  `json:"syncPolicy,omitempty"`
+	OwnerReferences []OwnerReference       `json:"ownerReferences,omitempty"`
+	// Applied to the source of every generated Application
+	Helm      *ApplicationSourceHelm      `json:"helm,omitempty"`
+	Kustomize *ApplicationSourceKustomize `json:"kustomize,omitempty"`
+	// Applied to every generated Application as notifications.argoproj.io/subscribe.* annotations
+	Notifications []NotificationSubscription `json:"notifications,omitempty"`
 }
 
 // ApplicationSource represents the source configuration for an Application
 type ApplicationSource struct {
-	RepoURL        string `json:"repoURL"`
-	Path           string `json:"path"`
-	TargetRevision string `json:"targetRevision"`
+	RepoURL        string                      `json:"repoURL"`
+	Path           string                      `json:"path"`
+	TargetRevision string                      `json:"targetRevision"`
+	Helm           *ApplicationSourceHelm      `json:"helm,omitempty"`
+	Kustomize      *ApplicationSourceKustomize `json:"kustomize,omitempty"`
+}
+
+// ApplicationSourceHelm holds Helm overrides for an Application source
+type ApplicationSourceHelm struct {
+	Values     string          `json:"values,omitempty"` // Inline values.yaml content
+	Parameters []HelmParameter `json:"parameters,omitempty"`
+}
+
+// HelmParameter overrides a single Helm value, as with helm --set
+type HelmParameter struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ApplicationSourceKustomize holds Kustomize overrides for an Application source
+type ApplicationSourceKustomize struct {
+	NamePrefix string   `json:"namePrefix,omitempty"`
+	Images     []string `json:"images,omitempty"` // Image overrides such as nginx=nginx:1.25
 }
 
 // ApplicationDestination represents the destination for an Application
@@ -159,6 +299,57 @@ type ApplicationStatus struct {
 	Sync         string    `json:"sync"`
 }
 
+// ApplicationResource is a resource ArgoCD reports as managed by an Application
+type ApplicationResource struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Health    string `json:"health,omitempty"`
+	Sync      string `json:"sync"`
+}
+
+// ApplicationHistoryEntry is a sync ArgoCD recorded in an Application's history. ArgoCD only records
+// completed syncs, so every entry is a successful deployment of Revision.
+type ApplicationHistoryEntry struct {
+	ID              int64      `json:"id"`
+	Revision        string     `json:"revision"`
+	DeployedAt      time.Time  `json:"deployedAt"`
+	DeployStartedAt *time.Time `json:"deployStartedAt,omitempty"`
+	InitiatedBy     string     `json:"initiatedBy,omitempty"` // Username, or "automated" for automated syncs
+}
+
+// RegistrationEvent is a Kubernetes Event this service recorded in a registration's namespace
+type RegistrationEvent struct {
+	Type          string    `json:"type"`
+	Reason        string    `json:"reason"`
+	Message       string    `json:"message"`
+	Kind          string    `json:"kind,omitempty"`
+	Name          string    `json:"name,omitempty"`
+	Count         int32     `json:"count,omitempty"`
+	LastTimestamp time.Time `json:"lastTimestamp"`
+}
+
+// TenantStatus represents a managed namespace and the sync health of its Application
+type TenantStatus struct {
+	Namespace   string `json:"namespace"`
+	Application string `json:"application"`
+	Health      string `json:"health"`
+	Sync        string `json:"sync"`
+	Message     string `json:"message,omitempty"`
+}
+
+// ReauthorizationResult reports whether a registration's owner still has access to its namespace
+type ReauthorizationResult struct {
+	RegistrationID string    `json:"registrationId"`
+	Namespace      string    `json:"namespace"`
+	Owner          string    `json:"owner"`
+	Allowed        bool      `json:"allowed"`
+	Reason         string    `json:"reason,omitempty"`
+	CheckedAt      time.Time `json:"checkedAt"`
+}
+
 // ServiceRegistrationStatus represents current service registration settings
 type ServiceRegistrationStatus struct {
 	AllowNewNamespaces bool   `json:"allowNewNamespaces"`
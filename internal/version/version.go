@@ -0,0 +1,43 @@
+package version
+
+import "runtime"
+
+// Build metadata, set at build time with
+// -ldflags "-X github.com/konflux-ci/gitops-registration-service/internal/version.Version=<version>"
+// and likewise for GitCommit and BuildDate
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildDate = "unknown"
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the build version, or "dev" for builds that did not set one
+func Get() string {
+	return valueOr(Version, "dev")
+}
+
+// Current returns the build metadata of the running binary, defaulting unset values
+// to "dev" for the version and "unknown" otherwise
+func Current() Info {
+	return Info{
+		Version:   Get(),
+		GitCommit: valueOr(GitCommit, "unknown"),
+		BuildDate: valueOr(BuildDate, "unknown"),
+		GoVersion: runtime.Version(),
+	}
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
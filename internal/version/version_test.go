@@ -0,0 +1,40 @@
+package version
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	original := Version
+	t.Cleanup(func() { Version = original })
+
+	assert.Equal(t, "dev", Get())
+
+	Version = "v1.4.0"
+	assert.Equal(t, "v1.4.0", Get())
+
+	Version = ""
+	assert.Equal(t, "dev", Get())
+}
+
+func TestCurrent(t *testing.T) {
+	originalVersion, originalCommit, originalDate := Version, GitCommit, BuildDate
+	t.Cleanup(func() { Version, GitCommit, BuildDate = originalVersion, originalCommit, originalDate })
+
+	Version, GitCommit, BuildDate = "", "", ""
+	assert.Equal(t, Info{
+		Version:   "dev",
+		GitCommit: "unknown",
+		BuildDate: "unknown",
+		GoVersion: runtime.Version(),
+	}, Current())
+
+	Version, GitCommit, BuildDate = "v1.4.0", "abc1234", "2026-01-02T03:04:05Z"
+	info := Current()
+	assert.Equal(t, "v1.4.0", info.Version)
+	assert.Equal(t, "abc1234", info.GitCommit)
+	assert.Equal(t, "2026-01-02T03:04:05Z", info.BuildDate)
+}